@@ -1,9 +1,11 @@
 package account
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"fmt"
+
 	"github.com/iost-official/Go-IOS-Protocol/common"
-	"crypto/rand"
 )
 
 var (
@@ -26,26 +28,55 @@ var (
 )
 
 type Account struct {
-	ID     string
-	Pubkey []byte
-	Seckey []byte
+	ID        string
+	Pubkey    []byte
+	Seckey    []byte
+	Algorithm common.SignAlgorithm
 }
 
+// NewAccount makes an account from seckey, a 64 byte seckey is taken as ed25519, otherwise
+// secp256k1 is used. A nil seckey generates a new secp256k1 account
 func NewAccount(seckey []byte) (Account, error) {
+	if len(seckey) == 64 {
+		return NewAccountWithAlgo(seckey, common.Ed25519)
+	}
+	return NewAccountWithAlgo(seckey, common.Secp256k1)
+}
+
+func NewAccountWithAlgo(seckey []byte, algo common.SignAlgorithm) (Account, error) {
 	var m Account
 	var err error
-	if seckey == nil {
-		seckey, err = randomSeckey()
-		if err != nil {
-			return Account{}, err
+	switch algo {
+	case common.Secp256k1:
+		if seckey == nil {
+			seckey, err = randomSeckey()
+			if err != nil {
+				return Account{}, err
+			}
 		}
-	}
-	if len(seckey) != 32 {
-		return Account{}, fmt.Errorf("seckey length error")
+		if len(seckey) != 32 {
+			return Account{}, fmt.Errorf("seckey length error")
+		}
+	case common.Ed25519:
+		if seckey == nil {
+			_, seckey, err = ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				return Account{}, err
+			}
+		}
+		if len(seckey) != ed25519.PrivateKeySize {
+			return Account{}, fmt.Errorf("seckey length error")
+		}
+	default:
+		return Account{}, fmt.Errorf("algorithm not exist")
 	}
 
 	m.Seckey = seckey
-	m.Pubkey = makePubkey(seckey)
+	m.Algorithm = algo
+	m.Pubkey = makePubkey(seckey, algo)
+	if m.Pubkey == nil {
+		return Account{}, fmt.Errorf("seckey error")
+	}
 	m.ID = GetIdByPubkey(m.Pubkey)
 	return m, nil
 }
@@ -63,10 +94,16 @@ func randomSeckey() ([]byte, error) {
 	return seckey, nil
 }
 
-func makePubkey(seckey []byte) []byte {
+func makePubkey(seckey []byte, algo common.SignAlgorithm) []byte {
+	if algo == common.Ed25519 {
+		return common.CalcPubkeyInEd25519(seckey)
+	}
 	return common.CalcPubkeyInSecp256k1(seckey)
 }
 
+// GetIdByPubkey derives the account id of pubkey. Ed25519 keys are 32 bytes and compressed
+// secp256k1 keys 33 bytes, so ids of the two algorithms never collide and the algorithm can
+// be told from the id by GetAlgoByID
 func GetIdByPubkey(pubkey []byte) string {
 	return common.Base58Encode(pubkey)
 }
@@ -74,3 +111,13 @@ func GetIdByPubkey(pubkey []byte) string {
 func GetPubkeyByID(ID string) []byte {
 	return common.Base58Decode(ID)
 }
+
+func GetAlgoByID(ID string) (common.SignAlgorithm, error) {
+	switch len(GetPubkeyByID(ID)) {
+	case 33:
+		return common.Secp256k1, nil
+	case 32:
+		return common.Ed25519, nil
+	}
+	return 0, fmt.Errorf("invalid account id")
+}
@@ -31,6 +31,33 @@ func TestMember(t *testing.T) {
 			m, err := NewAccount(Base58Decode("3BZ3HWs2nWucCCvLp7FRFv1K7RR3fAjjEQccf9EJrTv4"))
 			So(err, ShouldBeNil)
 			So(Base58Encode(m.Pubkey), ShouldEqual, "iWgLQj3VTPN4dZnomuJMMCggv22LFw4nAkA6bmrVsmCo")
+			So(m.Algorithm, ShouldEqual, Secp256k1)
+		})
+		Convey("ed25519 account", func() {
+			m, err := NewAccountWithAlgo(nil, Ed25519)
+			So(err, ShouldBeNil)
+			So(len(m.Pubkey), ShouldEqual, 32)
+			So(len(m.Seckey), ShouldEqual, 64)
+
+			m2, err := NewAccount(m.Seckey)
+			So(err, ShouldBeNil)
+			So(m2.Algorithm, ShouldEqual, Ed25519)
+			So(m2.ID, ShouldEqual, m.ID)
+
+			algo, err := GetAlgoByID(m.ID)
+			So(err, ShouldBeNil)
+			So(algo, ShouldEqual, Ed25519)
+			algo, err = GetAlgoByID("iWgLQj3VTPN4dZnomuJMMCggv22LFw4nAkA6bmrVsmCo")
+			So(err, ShouldBeNil)
+			So(algo, ShouldEqual, Secp256k1)
+
+			sig, err := Sign(m.Algorithm, Sha256([]byte("hello")), m.Seckey)
+			So(err, ShouldBeNil)
+			So(bytes.Equal(sig.Pubkey, m.Pubkey), ShouldBeTrue)
+			So(VerifySignature(Sha256([]byte("hello")), sig), ShouldBeTrue)
+
+			_, err = NewAccountWithAlgo(make([]byte, 32), Ed25519)
+			So(err, ShouldNotBeNil)
 		})
 	})
 }
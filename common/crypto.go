@@ -1,6 +1,7 @@
 package common
 
 import (
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
 
@@ -58,3 +59,37 @@ func CalcPubkeyInSecp256k1(privkey []byte) []byte {
 	x, y := myCurve.ScalarBaseMult(privkey)
 	return secp256k1.CompressPubkey(x, y)
 }
+
+// SignInEd25519 accepts either a 32 byte seed or a 64 byte private key
+func SignInEd25519(info, privkey []byte) []byte {
+	sk := ed25519PrivateKey(privkey)
+	if sk == nil {
+		return nil
+	}
+	return ed25519.Sign(sk, info)
+}
+
+func VerifySignInEd25519(info, pubkey, sig []byte) bool {
+	if len(pubkey) != ed25519.PublicKeySize || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(ed25519.PublicKey(pubkey), info, sig)
+}
+
+func CalcPubkeyInEd25519(privkey []byte) []byte {
+	sk := ed25519PrivateKey(privkey)
+	if sk == nil {
+		return nil
+	}
+	return []byte(sk.Public().(ed25519.PublicKey))
+}
+
+func ed25519PrivateKey(privkey []byte) ed25519.PrivateKey {
+	switch len(privkey) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(privkey)
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(privkey)
+	}
+	return nil
+}
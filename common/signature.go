@@ -10,8 +10,30 @@ import (
 
 type SignAlgorithm uint8
 
+func (a SignAlgorithm) String() string {
+	switch a {
+	case Secp256k1:
+		return "secp256k1"
+	case Ed25519:
+		return "ed25519"
+	}
+	return "unknown"
+}
+
+// ParseSignAlgorithm is the inverse of SignAlgorithm.String, "secp" is accepted for secp256k1
+func ParseSignAlgorithm(s string) (SignAlgorithm, error) {
+	switch s {
+	case "secp256k1", "secp":
+		return Secp256k1, nil
+	case "ed25519":
+		return Ed25519, nil
+	}
+	return 0, fmt.Errorf("algorithm not exist")
+}
+
 const (
 	Secp256k1 SignAlgorithm = iota
+	Ed25519
 )
 
 type Signature struct {
@@ -29,6 +51,13 @@ func Sign(algo SignAlgorithm, info, privkey []byte) (Signature, error) {
 		s.Pubkey = CalcPubkeyInSecp256k1(privkey)
 		s.Sig = SignInSecp256k1(info, privkey)
 		return s, nil
+	case Ed25519:
+		s.Pubkey = CalcPubkeyInEd25519(privkey)
+		s.Sig = SignInEd25519(info, privkey)
+		if s.Sig == nil {
+			return s, fmt.Errorf("ed25519 private key length error")
+		}
+		return s, nil
 	}
 	return s, fmt.Errorf("algorithm not exist")
 }
@@ -37,6 +66,8 @@ func VerifySignature(info []byte, s Signature) bool {
 	switch s.Algorithm {
 	case Secp256k1:
 		return VerifySignInSecp256k1(info, s.Pubkey, s.Sig)
+	case Ed25519:
+		return VerifySignInEd25519(info, s.Pubkey, s.Sig)
 	}
 	return false
 }
@@ -32,5 +32,30 @@ func TestSignature(t *testing.T) {
 			So(sig.Algorithm, ShouldEqual, sig2.Algorithm)
 		})
 
+		Convey("Cross algorithm", func() {
+			info := Sha256([]byte("hello"))
+			seckey := Sha256([]byte("seckey"))
+
+			secp, err := Sign(Secp256k1, info, seckey)
+			So(err, ShouldBeNil)
+			ed, err := Sign(Ed25519, info, seckey)
+			So(err, ShouldBeNil)
+			So(len(secp.Pubkey), ShouldEqual, 33)
+			So(len(ed.Pubkey), ShouldEqual, 32)
+			So(VerifySignature(info, secp), ShouldBeTrue)
+			So(VerifySignature(info, ed), ShouldBeTrue)
+
+			var sig2 Signature
+			So(sig2.Decode(ed.Encode()), ShouldBeNil)
+			So(sig2.Algorithm, ShouldEqual, Ed25519)
+			So(VerifySignature(info, sig2), ShouldBeTrue)
+
+			secp.Algorithm = Ed25519
+			So(VerifySignature(info, secp), ShouldBeFalse)
+			ed.Algorithm = Secp256k1
+			So(VerifySignature(info, ed), ShouldBeFalse)
+			So(VerifySignature(info, Signature{Algorithm: Ed25519, Pubkey: sig2.Pubkey, Sig: secp.Sig}), ShouldBeFalse)
+		})
+
 	})
 }
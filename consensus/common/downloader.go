@@ -0,0 +1,557 @@
+package consensus_common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	sy "sync"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/block"
+	"github.com/iost-official/Go-IOS-Protocol/core/message"
+	. "github.com/iost-official/Go-IOS-Protocol/network"
+)
+
+var (
+	// MaxHeadersPerRequest is the number of headers asked from a peer at once
+	MaxHeadersPerRequest uint64 = 128
+	// MaxHeadersAhead bounds how far headers are fetched beyond the downloaded blocks
+	MaxHeadersAhead uint64 = 2048
+	// SyncWindow bounds how far bodies are downloaded beyond the confirmed height, blocks too far
+	// ahead are not accepted by the block cache
+	SyncWindow uint64 = 64
+	// MaxBlocksPerPeer is the number of body requests in flight to one peer
+	MaxBlocksPerPeer = 8
+	// SyncRequestTimeout is how long a peer has to answer a request before it is asked from another
+	SyncRequestTimeout = 5 * time.Second
+
+	maxSyncFailures = 3
+	syncTick        = 500 * time.Millisecond
+)
+
+// errors of header validation
+var (
+	ErrHeadParent = errors.New("header does not link to its parent")
+	ErrHeadTime   = errors.New("header time is not after its parent")
+)
+
+// HeadVerifier checks the signature and the witness of a header, the consensus knows the schedule.
+type HeadVerifier func(head *block.BlockHead) error
+
+// SyncProgress is the state of the block synchronization.
+type SyncProgress struct {
+	Syncing bool
+	Start   uint64 // the first block of the sync
+	Current uint64 // the highest block downloaded with all its ancestors
+	Headers uint64 // the highest header validated
+	Target  uint64
+	Peers   int
+	Rate    float64 // blocks per second
+
+	Snapshot   uint64 // the height of the snapshot the state is restored from, 0 if none
+	Chunks     int    // the chunks of the snapshot
+	ChunksDone int    // the chunks downloaded
+}
+
+// syncPeer is a peer blocks are downloaded from.
+type syncPeer struct {
+	id       string
+	height   uint64
+	inflight int
+	fails    int
+}
+
+// syncTask is a request to a peer, for the headers from start to end or for the block start.
+type syncTask struct {
+	start, end uint64
+	peer       string
+	deadline   time.Time
+	tried      map[string]bool
+}
+
+// downloader synchronizes blocks header first: header chains are fetched from several peers and
+// validated, then the bodies of the validated headers are downloaded in parallel. Requests which
+// time out are given to other peers, peers which keep failing are left out.
+type downloader struct {
+	mu     sy.Mutex
+	sync   *SyncImpl
+	verify HeadVerifier
+
+	running       bool
+	start, target uint64
+	anchor        *block.BlockHead              // the block before start
+	heads         map[uint64]*block.BlockHead   // validated headers
+	headTop       uint64                        // the highest validated header
+	batches       map[uint64][]*block.BlockHead // header batches received but not linked yet, by first number
+	batchFrom     map[uint64]string
+	headTasks     map[uint64]*syncTask
+	bodyTasks     map[uint64]*syncTask
+	done          map[uint64]bool
+	current       uint64 // the highest block downloaded with all its ancestors
+	peers         map[string]*syncPeer
+	dropped       map[string]bool
+	startTime     time.Time
+	downloaded    int
+	snap          *snapSync // the snapshot being restored
+	snapshot      uint64    // the height of the snapshot restored
+}
+
+func newDownloader(sync *SyncImpl, verify HeadVerifier) *downloader {
+	return &downloader{sync: sync, verify: verify}
+}
+
+// begin starts a sync up to target, or raises the target of the running one.
+func (d *downloader) begin(start, target uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		if target > d.target {
+			d.target = target
+		}
+		return
+	}
+	confirmed := d.sync.blockCache.ConfirmedLength()
+	if start < confirmed {
+		start = confirmed
+	}
+	if start == 0 || target < start {
+		return
+	}
+	anchor := d.sync.blockCache.BlockChain().GetBlockByNumber(start - 1)
+	if anchor == nil {
+		return
+	}
+	d.running, d.start, d.target = true, start, target
+	d.anchor = &anchor.Head
+	d.heads = make(map[uint64]*block.BlockHead)
+	d.headTop, d.current = start-1, start-1
+	d.batches = make(map[uint64][]*block.BlockHead)
+	d.batchFrom = make(map[uint64]string)
+	d.headTasks = make(map[uint64]*syncTask)
+	d.bodyTasks = make(map[uint64]*syncTask)
+	d.done = make(map[uint64]bool)
+	d.peers = make(map[string]*syncPeer)
+	d.dropped = make(map[string]bool)
+	d.startTime, d.downloaded = time.Now(), 0
+	d.snap, d.snapshot = nil, 0
+	if d.sync.stateDB != nil && target >= start+SnapshotSyncDistance {
+		d.snap = newSnapSync(d.startTime)
+	}
+	d.sync.log.I("sync blocks from %v to %v", start, target)
+}
+
+// progress returns the state of the sync.
+func (d *downloader) progress() SyncProgress {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p := SyncProgress{
+		Syncing: d.running,
+		Start:   d.start,
+		Current: d.current,
+		Headers: d.headTop,
+		Target:  d.target,
+		Peers:   len(d.peers),
+	}
+	if elapsed := time.Since(d.startTime).Seconds(); d.running && elapsed > 0 {
+		p.Rate = float64(d.downloaded) / elapsed
+	}
+	p.Snapshot = d.snapshot
+	if d.snap != nil && d.snap.manifest != nil {
+		p.Snapshot = uint64(d.snap.manifest.Height)
+		p.Chunks, p.ChunksDone = len(d.snap.chunks), d.snap.done
+	}
+	return p
+}
+
+// updatePeers adds the peers higher than the next block needed.
+func (d *downloader) updatePeers() {
+	for id, height := range d.sync.router.PeerHeights() {
+		if d.dropped[id] || height <= d.current {
+			continue
+		}
+		if p, ok := d.peers[id]; ok {
+			p.height = height
+		} else {
+			d.peers[id] = &syncPeer{id: id, height: height}
+		}
+	}
+}
+
+// pickPeer returns the least busy peer having block num which is not in tried.
+func (d *downloader) pickPeer(num uint64, tried map[string]bool) *syncPeer {
+	return d.pick(func(p *syncPeer) bool {
+		return p.height >= num && !tried[p.id]
+	})
+}
+
+// pick returns the least busy peer which fits.
+func (d *downloader) pick(fits func(p *syncPeer) bool) *syncPeer {
+	candidates := make([]*syncPeer, 0, len(d.peers))
+	for _, p := range d.peers {
+		if p.inflight < MaxBlocksPerPeer && fits(p) {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].inflight != candidates[j].inflight {
+			return candidates[i].inflight < candidates[j].inflight
+		}
+		return candidates[i].id < candidates[j].id
+	})
+	return candidates[0]
+}
+
+// fail records a request to id which failed, id is dropped after maxSyncFailures.
+func (d *downloader) fail(id string) {
+	p, ok := d.peers[id]
+	if !ok {
+		return
+	}
+	p.fails++
+	if p.fails >= maxSyncFailures {
+		d.drop(id)
+	}
+}
+
+// drop leaves id out of the sync, its requests are given to other peers.
+func (d *downloader) drop(id string) {
+	d.sync.log.I("drop sync peer %v", id)
+	delete(d.peers, id)
+	d.dropped[id] = true
+	for _, tasks := range d.taskSets() {
+		for _, t := range tasks {
+			if t.peer == id {
+				t.peer = ""
+			}
+		}
+	}
+}
+
+// taskSets returns the requests of the running sync.
+func (d *downloader) taskSets() []map[uint64]*syncTask {
+	sets := []map[uint64]*syncTask{d.headTasks, d.bodyTasks}
+	if d.snap != nil {
+		sets = append(sets, d.snap.tasks)
+	}
+	return sets
+}
+
+func (d *downloader) release(id string) {
+	if p, ok := d.peers[id]; ok && p.inflight > 0 {
+		p.inflight--
+	}
+}
+
+// expire gives up the tasks past their deadline, a peer fails once however many of its tasks
+// expire together.
+func (d *downloader) expire(now time.Time) {
+	failed := make(map[string]bool)
+	for _, tasks := range d.taskSets() {
+		for _, t := range tasks {
+			if t.peer != "" && now.After(t.deadline) {
+				d.release(t.peer)
+				failed[t.peer] = true
+				t.peer = ""
+			}
+		}
+	}
+	for id := range failed {
+		d.fail(id)
+	}
+}
+
+// assign gives a peer to the tasks waiting for one and returns the requests to send.
+func (d *downloader) assign(now time.Time) []message.Message {
+	msgs := make([]message.Message, 0)
+	// header batches, in parallel up to the number of peers
+	for s, t := range d.headTasks {
+		if s <= d.headTop {
+			if t.peer != "" {
+				d.release(t.peer)
+			}
+			delete(d.headTasks, s)
+		}
+	}
+	// headers up to the target are needed to find the snapshot committed to last
+	ahead := d.current + MaxHeadersAhead
+	if d.snap != nil {
+		ahead = d.target
+	}
+	for s := d.headTop + 1; s <= d.target && s <= ahead && len(d.headTasks) < len(d.peers); s += MaxHeadersPerRequest {
+		if _, ok := d.batches[s]; ok {
+			continue
+		}
+		if _, ok := d.headTasks[s]; !ok {
+			end := s + MaxHeadersPerRequest - 1
+			if end > d.target {
+				end = d.target
+			}
+			d.headTasks[s] = &syncTask{start: s, end: end, tried: make(map[string]bool)}
+		}
+	}
+	for _, t := range d.headTasks {
+		if t.peer != "" {
+			continue
+		}
+		p := d.pickPeer(t.start, t.tried)
+		if p == nil {
+			p = d.pickPeer(t.start, nil)
+		}
+		if p == nil {
+			continue
+		}
+		t.peer, t.deadline = p.id, now.Add(SyncRequestTimeout)
+		t.tried[p.id] = true
+		p.inflight++
+		q := message.BlockHashQuery{Start: t.start, End: t.end}
+		body, _ := q.Marshal(nil)
+		msgs = append(msgs, message.Message{
+			Time:    now.UnixNano(),
+			To:      p.id,
+			ReqType: int32(ReqBlockHeaders),
+			Body:    body,
+		})
+	}
+
+	if d.snap != nil {
+		msgs = append(msgs, d.assignSnapshot(now)...)
+		// bodies are downloaded once the snapshot is restored or given up
+		if d.snap != nil {
+			return msgs
+		}
+	}
+
+	// bodies of the validated headers within the window
+	limit := d.sync.blockCache.ConfirmedLength() + SyncWindow
+	for n := d.current + 1; n <= d.headTop && n < limit; n++ {
+		if d.done[n] {
+			continue
+		}
+		if msg, ok := d.assignBody(n, now); ok {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// assignBody gives a peer to the request of block n if it waits for one, it returns the request to
+// send.
+func (d *downloader) assignBody(n uint64, now time.Time) (message.Message, bool) {
+	t, ok := d.bodyTasks[n]
+	if !ok {
+		t = &syncTask{start: n, end: n, tried: make(map[string]bool)}
+		d.bodyTasks[n] = t
+	}
+	if t.peer != "" {
+		return message.Message{}, false
+	}
+	p := d.pickPeer(n, t.tried)
+	if p == nil {
+		p = d.pickPeer(n, nil)
+	}
+	if p == nil {
+		return message.Message{}, false
+	}
+	t.peer, t.deadline = p.id, now.Add(SyncRequestTimeout)
+	t.tried[p.id] = true
+	p.inflight++
+	req := &message.RequestBlock{BlockNumber: n, BlockHash: d.heads[n].Hash()}
+	return message.Message{
+		Time:    now.UnixNano(),
+		To:      p.id,
+		ReqType: int32(ReqDownloadBlock),
+		Body:    req.Encode(),
+	}, true
+}
+
+// checkHead checks that head follows parent and is signed by the witness of its slot.
+func (d *downloader) checkHead(head, parent *block.BlockHead) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid header: %v", r)
+		}
+	}()
+	if head.Number != parent.Number+1 || !bytes.Equal(head.ParentHash, parent.Hash()) {
+		return ErrHeadParent
+	}
+	if head.Time <= parent.Time {
+		return ErrHeadTime
+	}
+	if d.verify != nil {
+		return d.verify(head)
+	}
+	return nil
+}
+
+// link validates the received batches which follow the validated headers.
+func (d *downloader) link() {
+	for {
+		s := d.headTop + 1
+		heads, ok := d.batches[s]
+		if !ok {
+			break
+		}
+		from := d.batchFrom[s]
+		delete(d.batches, s)
+		delete(d.batchFrom, s)
+		for _, head := range heads {
+			parent := d.anchor
+			if d.headTop >= d.start {
+				parent = d.heads[d.headTop]
+			}
+			if err := d.checkHead(head, parent); err != nil {
+				d.sync.log.I("invalid header %v from %v: %v", head.Number, from, err)
+				d.sync.router.Penalize(from, PenaltyInvalidBlock)
+				d.drop(from)
+				break
+			}
+			d.heads[uint64(head.Number)] = head
+			d.headTop = uint64(head.Number)
+			if d.snap != nil {
+				d.snap.noteCommit(head)
+				d.snap.deadline = time.Now().Add(SnapshotWait)
+			}
+		}
+	}
+	for s := range d.batches {
+		if s <= d.headTop {
+			delete(d.batches, s)
+			delete(d.batchFrom, s)
+		}
+	}
+}
+
+// handleHeaders takes the headers answered by a peer.
+func (d *downloader) handleHeaders(from string, heads []*block.BlockHead) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running || len(heads) == 0 {
+		return
+	}
+	s := heads[0].Number
+	if s < 0 {
+		return
+	}
+	t, ok := d.headTasks[uint64(s)]
+	if !ok || t.peer != from {
+		return
+	}
+	d.release(from)
+	delete(d.headTasks, uint64(s))
+	for i, head := range heads {
+		if head.Number != s+int64(i) || uint64(head.Number) > t.end {
+			d.sync.router.Penalize(from, PenaltyBadMsg)
+			return
+		}
+	}
+	d.batches[uint64(s)] = heads
+	d.batchFrom[uint64(s)] = from
+	d.link()
+}
+
+// blockDownloaded takes a block answered by a peer. Blocks which do not match their header or are
+// invalid are asked from another peer.
+func (d *downloader) blockDownloaded(blk *block.Block, from string, valid bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running || blk.Head.Number < 0 {
+		return
+	}
+	n := uint64(blk.Head.Number)
+	t, ok := d.bodyTasks[n]
+	if !ok {
+		return
+	}
+	if !valid || !bytes.Equal(blk.HeadHash(), d.heads[n].Hash()) {
+		// a block matching its header may be refused for a state diverging from the sender's
+		if !valid && bytes.Equal(blk.HeadHash(), d.heads[n].Hash()) {
+			d.sync.log.I("block %v from %v refused", n, from)
+		} else {
+			d.sync.router.Penalize(from, PenaltyInvalidBlock)
+		}
+		if t.peer == from {
+			d.release(from)
+			d.fail(from)
+			t.peer = ""
+		}
+		return
+	}
+	// the block may come from a peer it was asked from before
+	if t.peer != "" {
+		d.release(t.peer)
+	}
+	delete(d.bodyTasks, n)
+	if d.snap != nil && d.snap.manifest != nil && n == uint64(d.snap.manifest.Height) {
+		d.restore(blk)
+		return
+	}
+	d.done[n] = true
+	d.downloaded++
+	d.advance()
+}
+
+// advance moves current over the blocks downloaded.
+func (d *downloader) advance() {
+	for d.done[d.current+1] {
+		delete(d.done, d.current+1)
+		d.current++
+		if d.current > d.start {
+			delete(d.heads, d.current-1)
+		}
+	}
+	if d.current >= d.target {
+		d.sync.log.I("sync done at %v, %v blocks in %v", d.current, d.downloaded, time.Since(d.startTime))
+		d.running = false
+	}
+}
+
+// step expires and assigns the tasks of the running sync, it returns the requests to send.
+func (d *downloader) step(now time.Time) []message.Message {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running {
+		return nil
+	}
+	// blocks may have come through broadcast as well
+	confirmed := d.sync.blockCache.ConfirmedLength()
+	for n := d.current + 1; n < confirmed && n <= d.headTop; n++ {
+		if t, ok := d.bodyTasks[n]; ok && t.peer != "" {
+			d.release(t.peer)
+		}
+		delete(d.bodyTasks, n)
+		d.done[n] = true
+	}
+	d.advance()
+	if !d.running || confirmed > d.target {
+		d.running = false
+		return nil
+	}
+	d.updatePeers()
+	if len(d.peers) == 0 && now.Sub(d.startTime) > SyncRequestTimeout {
+		d.sync.log.I("sync stopped at %v, no peer has the blocks up to %v", d.current, d.target)
+		d.running = false
+		return nil
+	}
+	d.expire(now)
+	return d.assign(now)
+}
+
+// loop drives the running sync.
+func (d *downloader) loop(exit chan struct{}) {
+	ticker := time.NewTicker(syncTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, msg := range d.step(now) {
+				d.sync.router.Send(msg)
+			}
+		case <-exit:
+			return
+		}
+	}
+}
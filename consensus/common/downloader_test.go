@@ -0,0 +1,376 @@
+package consensus_common
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/golang/mock/gomock"
+	"github.com/iost-official/Go-IOS-Protocol/core/block"
+	"github.com/iost-official/Go-IOS-Protocol/core/blockcache"
+	"github.com/iost-official/Go-IOS-Protocol/core/message"
+	"github.com/iost-official/Go-IOS-Protocol/core/mocks"
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/db"
+	"github.com/iost-official/Go-IOS-Protocol/log"
+	. "github.com/iost-official/Go-IOS-Protocol/network"
+	"github.com/iost-official/Go-IOS-Protocol/network/mocks"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testCache struct {
+	blockcache.BlockCache
+	chain     block.Chain
+	confirmed uint64
+	restored  *block.Block
+}
+
+func (c *testCache) Restore(blk *block.Block) error {
+	c.restored = blk
+	c.confirmed = uint64(blk.Head.Number) + 1
+	return nil
+}
+
+func (c *testCache) BlockChain() block.Chain {
+	return c.chain
+}
+
+func (c *testCache) ConfirmedLength() uint64 {
+	return c.confirmed
+}
+
+func testHeads(n int) []*block.BlockHead {
+	heads := []*block.BlockHead{{Number: 0, Time: 0}}
+	for i := 1; i <= n; i++ {
+		heads = append(heads, &block.BlockHead{
+			Number:     int64(i),
+			ParentHash: heads[i-1].Hash(),
+			Time:       int64(i),
+			Witness:    "w",
+		})
+	}
+	return heads
+}
+
+func countByPeer(msgs []message.Message, reqType ReqType) map[string]int {
+	count := make(map[string]int)
+	for _, m := range msgs {
+		if m.ReqType == int32(reqType) {
+			count[m.To]++
+		}
+	}
+	return count
+}
+
+func TestDownloader(t *testing.T) {
+	Convey("Test of downloader", t, func() {
+		ctl := NewController(t)
+		defer ctl.Finish()
+		heads := testHeads(200)
+
+		mockChain := core_mock.NewMockChain(ctl)
+		mockChain.EXPECT().GetBlockByNumber(Any()).AnyTimes().Return(&block.Block{Head: *heads[0]})
+		mockRouter := protocol_mock.NewMockRouter(ctl)
+		mockRouter.EXPECT().PeerHeights().AnyTimes().Return(map[string]uint64{"a": 200, "b": 200})
+		penalized := make(map[string]int)
+		mockRouter.EXPECT().Penalize(Any(), Any()).AnyTimes().Do(func(from string, penalty int) {
+			penalized[from] += penalty
+		})
+
+		logger, _ := log.NewLogger("synchronizer.log")
+		defer os.Remove("synchronizer.log")
+		sync := &SyncImpl{blockCache: &testCache{chain: mockChain, confirmed: 1}, router: mockRouter, log: logger}
+		d := newDownloader(sync, func(head *block.BlockHead) error {
+			if head.Witness != "w" {
+				return errors.New("wrong witness")
+			}
+			return nil
+		})
+		d.begin(1, 200)
+		now := time.Now()
+
+		Convey("fetches headers from several peers before bodies", func() {
+			msgs := d.step(now)
+			So(len(msgs), ShouldEqual, 2)
+			So(len(countByPeer(msgs, ReqBlockHeaders)), ShouldEqual, 2)
+
+			peer := d.headTasks[129].peer
+			d.handleHeaders(peer, heads[129:])
+			So(d.headTop, ShouldEqual, 0)
+			d.handleHeaders(d.headTasks[1].peer, heads[1:129])
+			So(d.headTop, ShouldEqual, 200)
+
+			msgs = d.step(now)
+			count := countByPeer(msgs, ReqDownloadBlock)
+			So(count["a"], ShouldEqual, MaxBlocksPerPeer)
+			So(count["b"], ShouldEqual, MaxBlocksPerPeer)
+
+			Convey("and reassigns the blocks not answered", func() {
+				for n := uint64(1); n <= 16; n++ {
+					if d.bodyTasks[n].peer == "a" {
+						d.blockDownloaded(&block.Block{Head: *heads[n]}, "a", true)
+					}
+				}
+				So(d.current, ShouldEqual, 1)
+				msgs = d.step(now.Add(SyncRequestTimeout + time.Second))
+				count = countByPeer(msgs, ReqDownloadBlock)
+				So(count["a"], ShouldEqual, MaxBlocksPerPeer)
+				So(d.peers["b"].fails, ShouldEqual, 1)
+				for n := uint64(2); n <= 16; n += 2 {
+					So(d.bodyTasks[n].peer, ShouldEqual, "a")
+				}
+			})
+
+			Convey("and refuses blocks not matching their header", func() {
+				n := uint64(1)
+				from := d.bodyTasks[n].peer
+				other := *heads[n]
+				other.Info = []byte("fork")
+				d.blockDownloaded(&block.Block{Head: other}, from, true)
+				So(penalized[from], ShouldEqual, PenaltyInvalidBlock)
+				So(d.current, ShouldEqual, 0)
+				So(d.bodyTasks[n].peer, ShouldEqual, "")
+			})
+
+			Convey("and asks again without penalty the blocks refused by the state", func() {
+				n := uint64(1)
+				from := d.bodyTasks[n].peer
+				d.blockDownloaded(&block.Block{Head: *heads[n]}, from, false)
+				So(penalized[from], ShouldEqual, 0)
+				So(d.peers[from].fails, ShouldEqual, 1)
+				So(d.bodyTasks[n].peer, ShouldEqual, "")
+			})
+
+			Convey("and completes", func() {
+				for n := uint64(1); n <= 200; n++ {
+					d.done[n] = true
+				}
+				d.advance()
+				So(d.progress().Current, ShouldEqual, 200)
+				So(d.progress().Syncing, ShouldBeFalse)
+			})
+		})
+
+		Convey("drops peers sending invalid headers", func() {
+			d.step(now)
+			bad := make([]*block.BlockHead, 0)
+			for _, h := range heads[1:129] {
+				c := *h
+				bad = append(bad, &c)
+			}
+			bad[10].Witness = "x"
+			peer := d.headTasks[1].peer
+			d.handleHeaders(peer, bad)
+			So(d.headTop, ShouldEqual, 10)
+			So(penalized[peer], ShouldEqual, PenaltyInvalidBlock)
+			So(d.dropped[peer], ShouldBeTrue)
+			So(d.peers[peer], ShouldBeNil)
+		})
+	})
+}
+
+// memKeys is a memory database whose keys can be listed.
+type memKeys struct {
+	*db.MemDatabase
+}
+
+func (m memKeys) Keys(prefix []byte) ([][]byte, error) {
+	keys := make([][]byte, 0)
+	for _, k := range m.MemDatabase.Keys() {
+		if bytes.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+// takeSnapshot takes a snapshot of the state in d, with its chunks.
+func takeSnapshot(d *state.Database, height int64, blockHash []byte) (*state.SnapshotManifest, [][]byte, error) {
+	chunks := make([][]byte, 0)
+	m, err := d.Snapshot(height, blockHash, func(chunk []byte) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	return m, chunks, err
+}
+
+func newMemState() state.Database {
+	mem, _ := db.NewMemDatabase()
+	return state.NewDatabase(memKeys{mem})
+}
+
+// answerHeaders answers the header requests in msgs with the headers of heads.
+func answerHeaders(d *downloader, msgs []message.Message, heads []*block.BlockHead) {
+	for _, m := range msgs {
+		if m.ReqType != int32(ReqBlockHeaders) {
+			continue
+		}
+		var q message.BlockHashQuery
+		q.Unmarshal(m.Body)
+		d.handleHeaders(m.To, heads[q.Start:q.End+1])
+	}
+}
+
+func TestSnapshotSync(t *testing.T) {
+	Convey("Test of snapshot sync", t, func() {
+		ctl := NewController(t)
+		defer ctl.Finish()
+		state.SnapshotChunkSize = 64
+		defer func() { state.SnapshotChunkSize = 128 << 10 }()
+
+		src := newMemState()
+		for _, k := range []string{"a", "b", "c", "d", "e", "f"} {
+			src.Put(state.Key(k), state.MakeVString("value of "+k))
+		}
+		heads := testHeads(1000)
+		m, chunks, err := takeSnapshot(&src, 1000, heads[1000].Hash())
+		So(err, ShouldBeNil)
+		So(len(chunks), ShouldBeGreaterThan, 1)
+		commit, _ := (&state.SnapshotCommit{Height: 1000, Root: m.Root()}).Marshal(nil)
+		for i := 1001; i <= 2100; i++ {
+			heads = append(heads, &block.BlockHead{
+				Number:     int64(i),
+				ParentHash: heads[i-1].Hash(),
+				Time:       int64(i),
+				Witness:    []string{"w1", "w2"}[i%2],
+				Info:       commit,
+			})
+		}
+
+		mockChain := core_mock.NewMockChain(ctl)
+		mockChain.EXPECT().GetBlockByNumber(Any()).AnyTimes().Return(&block.Block{Head: *heads[0]})
+		mockRouter := protocol_mock.NewMockRouter(ctl)
+		mockRouter.EXPECT().PeerHeights().AnyTimes().Return(map[string]uint64{"a": 2100, "b": 2100})
+		penalized := make(map[string]int)
+		mockRouter.EXPECT().Penalize(Any(), Any()).AnyTimes().Do(func(from string, penalty int) {
+			penalized[from] += penalty
+		})
+
+		logger, _ := log.NewLogger("synchronizer.log")
+		defer os.Remove("synchronizer.log")
+		cache := &testCache{chain: mockChain, confirmed: 1}
+		dst := newMemState()
+		dst.Put(state.Key("stale"), state.MakeVString("genesis"))
+		sync := &SyncImpl{blockCache: cache, router: mockRouter, log: logger, confirmNumber: 2, stateDB: &dst}
+		d := newDownloader(sync, nil)
+		d.begin(1, 2100)
+		So(d.snap, ShouldNotBeNil)
+		now := time.Now()
+
+		msgs := d.step(now)
+		So(len(countByPeer(msgs, ReqSnapshotManifest)), ShouldEqual, 2)
+		So(len(countByPeer(msgs, ReqDownloadBlock)), ShouldEqual, 0)
+		for d.headTop < 2100 {
+			answerHeaders(d, msgs, heads)
+			msgs = d.step(now)
+		}
+
+		Convey("restores the state and syncs the blocks after it", func() {
+			d.handleManifest("a", m)
+			d.handleManifest("b", m)
+			msgs = d.step(now)
+			So(d.progress().Snapshot, ShouldEqual, 1000)
+			count := countByPeer(msgs, ReqSnapshotChunk)
+			So(count["a"]+count["b"], ShouldEqual, len(chunks))
+			for _, msg := range msgs {
+				if msg.ReqType != int32(ReqSnapshotChunk) {
+					continue
+				}
+				var q message.SnapshotChunkQuery
+				q.Unmarshal(msg.Body)
+				d.handleChunk(msg.To, &message.SnapshotChunk{Root: q.Root, Index: q.Index, Data: chunks[q.Index]})
+			}
+			So(d.progress().ChunksDone, ShouldEqual, len(chunks))
+
+			msgs = d.step(now)
+			So(len(msgs), ShouldEqual, 1)
+			So(msgs[0].ReqType, ShouldEqual, ReqDownloadBlock)
+			d.blockDownloaded(&block.Block{Head: *heads[1000]}, msgs[0].To, true)
+			So(cache.restored.Head.Number, ShouldEqual, 1000)
+			So(d.current, ShouldEqual, 1000)
+			v, _ := dst.Get(state.Key("c"))
+			So(v.EncodeString(), ShouldEqual, state.MakeVString("value of c").EncodeString())
+			has, _ := dst.Has(state.Key("stale"))
+			So(has, ShouldBeFalse)
+
+			msgs = d.step(now)
+			count = countByPeer(msgs, ReqDownloadBlock)
+			So(count["a"]+count["b"], ShouldEqual, 2*MaxBlocksPerPeer)
+			So(d.bodyTasks[1001], ShouldNotBeNil)
+		})
+
+		Convey("refuses chunks not matching the snapshot", func() {
+			d.handleManifest("a", m)
+			msgs = d.step(now)
+			for _, msg := range msgs {
+				if msg.ReqType == int32(ReqSnapshotChunk) {
+					var q message.SnapshotChunkQuery
+					q.Unmarshal(msg.Body)
+					d.handleChunk(msg.To, &message.SnapshotChunk{Root: q.Root, Index: q.Index, Data: []byte("forged")})
+					So(d.snap.tasks[uint64(q.Index)].peer, ShouldEqual, "")
+					break
+				}
+			}
+			So(penalized["a"], ShouldEqual, PenaltyInvalidBlock)
+			So(d.progress().ChunksDone, ShouldEqual, 0)
+		})
+
+		Convey("downloads every block without a committed snapshot", func() {
+			other := newMemState()
+			other.Put(state.Key("a"), state.MakeVString("forged"))
+			forged, _, _ := takeSnapshot(&other, 1000, heads[1000].Hash())
+			d.handleManifest("a", forged)
+			d.handleManifest("b", forged)
+			d.step(now)
+			So(d.progress().Snapshot, ShouldEqual, 0)
+
+			msgs = d.step(now.Add(SnapshotWait + time.Second))
+			So(d.snap, ShouldBeNil)
+			So(d.bodyTasks[1], ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSnapshots(t *testing.T) {
+	Convey("Test of snapshots", t, func() {
+		ctl := NewController(t)
+		defer ctl.Finish()
+		state.SnapshotChunkSize = 64
+		defer func() { state.SnapshotChunkSize = 128 << 10 }()
+
+		src := newMemState()
+		for _, k := range []string{"a", "b", "c", "d", "e", "f"} {
+			src.Put(state.Key(k), state.MakeVString("value of "+k))
+		}
+		m, chunks, _ := takeSnapshot(&src, 1000, nil)
+		heads := testHeads(1000)
+		mockChain := core_mock.NewMockChain(ctl)
+		mockChain.EXPECT().Length().AnyTimes().Return(uint64(1001))
+		top := &block.Block{Head: *heads[1000]}
+		mockChain.EXPECT().Top().AnyTimes().Return(top)
+
+		defer os.RemoveAll("snapshots_test")
+		s := &snapshots{db: &src, dir: "snapshots_test"}
+
+		Convey("are written in the background and read from disk", func() {
+			So(s.take(mockChain), ShouldBeNil)
+			// the state written meanwhile is not in the snapshot
+			src.Put(state.Key("a"), state.MakeVString("changed"))
+			for i := 0; i < 100 && s.latest() == nil; i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			latest := s.latest()
+			So(latest, ShouldNotBeNil)
+			So(latest.Chunks, ShouldResemble, m.Chunks)
+			root := latest.Root()
+			for i := range chunks {
+				So(s.chunk(root, i), ShouldResemble, chunks[i])
+			}
+			So(s.chunk(root, len(chunks)), ShouldBeNil)
+			So(s.take(mockChain), ShouldBeNil)
+			So(len(s.taken), ShouldEqual, 1)
+		})
+	})
+}
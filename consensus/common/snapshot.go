@@ -0,0 +1,294 @@
+package consensus_common
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	sy "sync"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/block"
+	"github.com/iost-official/Go-IOS-Protocol/core/message"
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/log"
+	. "github.com/iost-official/Go-IOS-Protocol/network"
+)
+
+var (
+	// SnapshotInterval is the number of confirmed blocks between two snapshots of the state, 0
+	// disables snapshots
+	SnapshotInterval uint64 = 1000
+	// SnapshotsKept is the number of the latest snapshots served to peers
+	SnapshotsKept = 2
+	// SnapshotSyncDistance is how far behind a node restores the state from a snapshot rather than
+	// downloading every block
+	SnapshotSyncDistance uint64 = 2000
+	// SnapshotWait is how long a sync waits for a committed snapshot, or for a chunk of it, before
+	// it downloads every block
+	SnapshotWait = 30 * time.Second
+	// SnapshotDir is the directory the chunks of the snapshots kept are written to
+	SnapshotDir = "snapshots"
+)
+
+// snapshot is a snapshot of the state taken at a confirmed block, its chunks are in a file.
+type snapshot struct {
+	manifest *state.SnapshotManifest
+	root     []byte
+	path     string
+	offsets  []int64 // where every chunk begins in the file, then its size
+}
+
+// snapshots are the latest snapshots of the state, the newest last.
+type snapshots struct {
+	mu     sy.Mutex
+	db     *state.Database
+	dir    string
+	taken  []*snapshot
+	taking int64 // the height of the snapshot being taken, 0 if none
+	log    *log.Logger
+}
+
+// take snapshots the state if the confirmed chain of length is at a multiple of
+// SnapshotInterval. Every node snapshots at the same heights so that their roots can be
+// compared. The state is viewed as it is now, the snapshot is written in the background.
+func (s *snapshots) take(chain block.Chain) error {
+	length := chain.Length()
+	if s.db == nil || SnapshotInterval == 0 || length <= 1 || (length-1)%SnapshotInterval != 0 {
+		return nil
+	}
+	top := chain.Top()
+	if top == nil || uint64(top.Head.Number) != length-1 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.taken); s.taking != 0 || n > 0 && s.taken[n-1].manifest.Height >= top.Head.Number {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	v, err := s.db.View()
+	if err != nil {
+		return err
+	}
+	s.taking = top.Head.Number
+	go s.write(v, top.Head.Number, top.HeadHash())
+	return nil
+}
+
+// write writes the snapshot of the state seen by v to a file, then keeps it.
+func (s *snapshots) write(v *state.View, height int64, blockHash []byte) {
+	start := time.Now()
+	snap, err := s.writeFile(v, height, blockHash)
+	v.Release()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.taking = 0
+	if err != nil {
+		if s.log != nil {
+			s.log.E("take snapshot at %v failed:%v", height, err)
+		}
+		return
+	}
+	s.taken = append(s.taken, snap)
+	if len(s.taken) > SnapshotsKept {
+		for _, old := range s.taken[:len(s.taken)-SnapshotsKept] {
+			os.Remove(old.path)
+		}
+		s.taken = s.taken[len(s.taken)-SnapshotsKept:]
+	}
+	if s.log != nil {
+		s.log.I("snapshot at %v taken in %v, %v chunks", height, time.Since(start), len(snap.manifest.Chunks))
+	}
+}
+
+func (s *snapshots) writeFile(v *state.View, height int64, blockHash []byte) (*snapshot, error) {
+	path := filepath.Join(s.dir, fmt.Sprintf("snapshot-%v", height))
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	snap := &snapshot{path: path, offsets: []int64{0}}
+	w := bufio.NewWriter(f)
+	m, err := v.Snapshot(height, blockHash, func(chunk []byte) error {
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+		snap.offsets = append(snap.offsets, snap.offsets[len(snap.offsets)-1]+int64(len(chunk)))
+		return nil
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	snap.manifest, snap.root = m, m.Root()
+	return snap, nil
+}
+
+// latest returns the manifest of the newest snapshot, nil if none was taken.
+func (s *snapshots) latest() *state.SnapshotManifest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.taken) == 0 {
+		return nil
+	}
+	return s.taken[len(s.taken)-1].manifest
+}
+
+// chunk reads the chunk i of the snapshot of root, nil if it is not kept.
+func (s *snapshots) chunk(root []byte, i int) []byte {
+	s.mu.Lock()
+	var snap *snapshot
+	for _, t := range s.taken {
+		if bytes.Equal(t.root, root) && i >= 0 && i < len(t.manifest.Chunks) {
+			snap = t
+		}
+	}
+	s.mu.Unlock()
+	if snap == nil {
+		return nil
+	}
+	f, err := os.Open(snap.path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	data := make([]byte, snap.offsets[i+1]-snap.offsets[i])
+	if _, err := f.ReadAt(data, snap.offsets[i]); err != nil {
+		return nil
+	}
+	return data
+}
+
+// commit returns the commitment to the newest snapshot put in the heads of the blocks generated.
+func (s *snapshots) commit() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.taken) == 0 {
+		return nil
+	}
+	snap := s.taken[len(s.taken)-1]
+	c := &state.SnapshotCommit{Height: snap.manifest.Height, Root: snap.root}
+	b, err := c.Marshal(nil)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// unmarshal decodes b into v, gencode panics on some malformed input.
+func unmarshal(v interface {
+	Unmarshal([]byte) (uint64, error)
+}, b []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid message: %v", r)
+		}
+	}()
+	_, err = v.Unmarshal(b)
+	return err
+}
+
+// TakeSnapshot snapshots the state if the confirmed chain reached the next snapshot height, it is
+// called where blocks are added to the block cache so that the state viewed is the one of the
+// confirmed chain. The snapshot is written in the background.
+func (sync *SyncImpl) TakeSnapshot() {
+	if err := sync.snapshots.take(sync.blockCache.BlockChain()); err != nil {
+		sync.log.E("take snapshot failed:%v", err)
+	}
+}
+
+// SnapshotCommit returns the commitment to the latest snapshot, blocks generated carry it in their
+// head so that nodes syncing can check the snapshots they download.
+func (sync *SyncImpl) SnapshotCommit() []byte {
+	return sync.snapshots.commit()
+}
+
+// handleSnapshotQuery answers the manifest of the latest snapshot and the chunks of the snapshots
+// kept.
+func (sync *SyncImpl) handleSnapshotQuery() {
+	for {
+		select {
+		case req, ok := <-sync.snapQueryChan:
+			if !ok {
+				return
+			}
+			var resp message.Message
+			switch req.ReqType {
+			case int32(ReqSnapshotManifest):
+				m := sync.snapshots.latest()
+				if m == nil {
+					continue
+				}
+				b, err := m.Marshal(nil)
+				if err != nil {
+					sync.log.E("marshal SnapshotManifest failed:%v", err)
+					continue
+				}
+				resp = message.Message{ReqType: int32(RecvSnapshotManifest), Body: b}
+			case int32(ReqSnapshotChunk):
+				var q message.SnapshotChunkQuery
+				if err := unmarshal(&q, req.Body); err != nil {
+					sync.router.Penalize(req.From, PenaltyBadMsg)
+					continue
+				}
+				data := sync.snapshots.chunk(q.Root, int(q.Index))
+				if data == nil {
+					continue
+				}
+				c := &message.SnapshotChunk{Root: q.Root, Index: q.Index, Data: data}
+				b, err := c.Marshal(nil)
+				if err != nil {
+					sync.log.E("marshal SnapshotChunk failed:%v", err)
+					continue
+				}
+				resp = message.Message{ReqType: int32(RecvSnapshotChunk), Body: b}
+			default:
+				continue
+			}
+			resp.Time, resp.From, resp.To = time.Now().UnixNano(), req.To, req.From
+			sync.router.Send(resp)
+		case <-sync.exitSignal:
+			return
+		}
+	}
+}
+
+// handleSnapshotResp passes the manifests and chunks answered by peers to the downloader.
+func (sync *SyncImpl) handleSnapshotResp() {
+	for {
+		select {
+		case req, ok := <-sync.snapRespChan:
+			if !ok {
+				return
+			}
+			switch req.ReqType {
+			case int32(RecvSnapshotManifest):
+				m, err := state.DecodeManifest(req.Body)
+				if err != nil {
+					sync.router.Penalize(req.From, PenaltyBadMsg)
+					continue
+				}
+				sync.downloader.handleManifest(req.From, m)
+			case int32(RecvSnapshotChunk):
+				var c message.SnapshotChunk
+				if err := unmarshal(&c, req.Body); err != nil {
+					sync.router.Penalize(req.From, PenaltyBadMsg)
+					continue
+				}
+				sync.downloader.handleChunk(req.From, &c)
+			}
+		case <-sync.exitSignal:
+			return
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package consensus_common
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/block"
+	"github.com/iost-official/Go-IOS-Protocol/core/message"
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	. "github.com/iost-official/Go-IOS-Protocol/network"
+)
+
+// snapSync is the restoring of the state from a snapshot, done by a sync far behind instead of
+// downloading the blocks up to the snapshot. The snapshot is taken only if the validated headers
+// commit to its root, its chunks are then downloaded in parallel and checked against the root.
+type snapSync struct {
+	deadline  time.Time                          // the snapshot is given up after it
+	asked     map[string]bool                    // peers asked for their manifest
+	offers    map[string]string                  // peer -> root of the snapshot it offers
+	manifests map[string]*state.SnapshotManifest // root -> manifest
+	commits   map[string]map[string]bool         // height and root -> witnesses committing to them
+
+	manifest *state.SnapshotManifest // the snapshot chosen
+	root     []byte
+	chunks   [][]byte
+	done     int
+	tasks    map[uint64]*syncTask // chunk requests by index
+}
+
+func newSnapSync(now time.Time) *snapSync {
+	return &snapSync{
+		deadline:  now.Add(SnapshotWait),
+		asked:     make(map[string]bool),
+		offers:    make(map[string]string),
+		manifests: make(map[string]*state.SnapshotManifest),
+		commits:   make(map[string]map[string]bool),
+		tasks:     make(map[uint64]*syncTask),
+	}
+}
+
+func commitKey(height int64, root []byte) string {
+	return fmt.Sprintf("%v/%x", height, root)
+}
+
+// noteCommit records the snapshot commitment of a validated header.
+func (s *snapSync) noteCommit(head *block.BlockHead) {
+	if len(head.Info) == 0 {
+		return
+	}
+	c, err := state.DecodeCommit(head.Info)
+	if err != nil || c.Height >= head.Number {
+		return
+	}
+	key := commitKey(c.Height, c.Root)
+	if s.commits[key] == nil {
+		s.commits[key] = make(map[string]bool)
+	}
+	s.commits[key][head.Witness] = true
+}
+
+// chooseSnapshot picks the highest snapshot offered whose block is validated and whose root is
+// committed to by confirmNumber witnesses.
+func (d *downloader) chooseSnapshot() {
+	s := d.snap
+	quorum := d.sync.confirmNumber
+	if quorum < 1 {
+		quorum = 1
+	}
+	var best *state.SnapshotManifest
+	for root, m := range s.manifests {
+		head, ok := d.heads[uint64(m.Height)]
+		if !ok || m.Height <= int64(d.current) || !bytes.Equal(head.Hash(), m.BlockHash) {
+			continue
+		}
+		if len(s.commits[commitKey(m.Height, []byte(root))]) < quorum {
+			continue
+		}
+		if best == nil || m.Height > best.Height {
+			best = m
+		}
+	}
+	if best == nil {
+		return
+	}
+	s.manifest, s.root = best, best.Root()
+	s.chunks = make([][]byte, len(best.Chunks))
+	d.sync.log.I("restore state from snapshot at %v, %v chunks", best.Height, len(best.Chunks))
+}
+
+// leaveSnapshot gives up the snapshot, the blocks are all downloaded instead.
+func (d *downloader) leaveSnapshot(reason string) {
+	d.sync.log.I("sync without snapshot: %v", reason)
+	for _, t := range d.snap.tasks {
+		if t.peer != "" {
+			d.release(t.peer)
+		}
+	}
+	if d.snap.manifest != nil {
+		n := uint64(d.snap.manifest.Height)
+		if t, ok := d.bodyTasks[n]; ok {
+			if t.peer != "" {
+				d.release(t.peer)
+			}
+			delete(d.bodyTasks, n)
+		}
+	}
+	d.snap = nil
+}
+
+// assignSnapshot asks the peers for their manifest, then for the chunks of the snapshot chosen
+// and at last for its block. It returns the requests to send.
+func (d *downloader) assignSnapshot(now time.Time) []message.Message {
+	s := d.snap
+	msgs := make([]message.Message, 0)
+	for id := range d.peers {
+		if !s.asked[id] {
+			s.asked[id] = true
+			msgs = append(msgs, message.Message{
+				Time:    now.UnixNano(),
+				To:      id,
+				ReqType: int32(ReqSnapshotManifest),
+			})
+		}
+	}
+	if s.manifest == nil {
+		d.chooseSnapshot()
+	}
+	if now.After(s.deadline) {
+		d.leaveSnapshot("no committed snapshot could be downloaded")
+		return msgs
+	}
+	if s.manifest == nil {
+		return msgs
+	}
+	offered := func(p *syncPeer) bool {
+		return s.offers[p.id] == string(s.root)
+	}
+	for i, data := range s.chunks {
+		if data != nil {
+			continue
+		}
+		t, ok := s.tasks[uint64(i)]
+		if !ok {
+			t = &syncTask{start: uint64(i), end: uint64(i), tried: make(map[string]bool)}
+			s.tasks[uint64(i)] = t
+		}
+		if t.peer != "" {
+			continue
+		}
+		p := d.pick(func(p *syncPeer) bool { return offered(p) && !t.tried[p.id] })
+		if p == nil {
+			p = d.pick(offered)
+		}
+		if p == nil {
+			continue
+		}
+		t.peer, t.deadline = p.id, now.Add(SyncRequestTimeout)
+		t.tried[p.id] = true
+		p.inflight++
+		q := &message.SnapshotChunkQuery{Root: s.root, Index: uint32(i)}
+		body, _ := q.Marshal(nil)
+		msgs = append(msgs, message.Message{
+			Time:    now.UnixNano(),
+			To:      p.id,
+			ReqType: int32(ReqSnapshotChunk),
+			Body:    body,
+		})
+	}
+	if s.done == len(s.chunks) {
+		if msg, ok := d.assignBody(uint64(s.manifest.Height), now); ok {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// handleManifest takes the manifest of the latest snapshot of a peer.
+func (d *downloader) handleManifest(from string, m *state.SnapshotManifest) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running || d.snap == nil || !d.snap.asked[from] {
+		return
+	}
+	root := string(m.Root())
+	d.snap.offers[from] = root
+	d.snap.manifests[root] = m
+}
+
+// handleChunk takes a chunk answered by a peer, chunks not matching the snapshot are asked from
+// another peer.
+func (d *downloader) handleChunk(from string, c *message.SnapshotChunk) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.snap
+	if !d.running || s == nil || s.manifest == nil || !bytes.Equal(c.Root, s.root) {
+		return
+	}
+	t, ok := s.tasks[uint64(c.Index)]
+	if !ok || t.peer != from {
+		return
+	}
+	d.release(from)
+	if err := s.manifest.VerifyChunk(int(c.Index), c.Data); err != nil {
+		d.sync.log.I("invalid snapshot chunk %v from %v: %v", c.Index, from, err)
+		d.sync.router.Penalize(from, PenaltyInvalidBlock)
+		d.fail(from)
+		t.peer = ""
+		return
+	}
+	delete(s.tasks, uint64(c.Index))
+	s.chunks[c.Index] = c.Data
+	s.done++
+	s.deadline = time.Now().Add(SnapshotWait)
+}
+
+// restore replaces the state by the snapshot downloaded and makes blk, its block, the root of the
+// block cache. The sync goes on from blk.
+func (d *downloader) restore(blk *block.Block) {
+	s := d.snap
+	d.snap = nil
+	if err := d.sync.stateDB.Restore(s.manifest, s.chunks); err != nil {
+		d.sync.log.E("restore snapshot at %v failed:%v", s.manifest.Height, err)
+		d.running = false
+		return
+	}
+	if err := d.sync.blockCache.Restore(blk); err != nil {
+		d.sync.log.E("restore block cache at %v failed:%v", s.manifest.Height, err)
+		d.running = false
+		return
+	}
+	n := uint64(blk.Head.Number)
+	for k := range d.heads {
+		if k < n {
+			delete(d.heads, k)
+		}
+	}
+	for k := range d.done {
+		if k <= n {
+			delete(d.done, k)
+		}
+	}
+	d.current = n
+	d.snapshot = n
+	d.sync.log.I("state restored from snapshot at %v in %v", n, time.Since(d.startTime))
+	d.advance()
+}
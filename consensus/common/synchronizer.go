@@ -1,22 +1,20 @@
 package consensus_common
 
 import (
-	sy "sync"
+	"fmt"
 	"time"
 
+	"github.com/iost-official/Go-IOS-Protocol/core/block"
 	"github.com/iost-official/Go-IOS-Protocol/core/blockcache"
 	"github.com/iost-official/Go-IOS-Protocol/core/message"
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
 	"github.com/iost-official/Go-IOS-Protocol/log"
 	. "github.com/iost-official/Go-IOS-Protocol/network"
 )
 
 var (
-	SyncNumber                    = 2
-	MaxBlockHashQueryNumber       = 10
-	RetryTime                     = 5
-	blockDownloadTimeout    int64 = 10
-	cleanInterval                 = 5 * time.Second
-	MaxAcceptableLength     int64 = 100
+	SyncNumber                = 2
+	MaxAcceptableLength int64 = 100
 )
 
 type Synchronizer interface {
@@ -24,32 +22,44 @@ type Synchronizer interface {
 	StopListen() error
 	NeedSync(maxHeight uint64) (bool, uint64, uint64)
 	SyncBlocks(startNumber uint64, endNumber uint64) error
-	BlockConfirmed(num int64)
+	BlockDownloaded(blk *block.Block, from string, valid bool)
+	Progress() SyncProgress
+	TakeSnapshot()
+	SnapshotCommit() []byte
 }
 
 type SyncImpl struct {
-	blockCache        blockcache.BlockCache
-	router            Router
-	confirmNumber     int
-	heightChan        chan message.Message
-	blkSyncChan       chan message.Message
-	blkHashQueryChan  chan message.Message
-	blkHashRespChan   chan message.Message
-	exitSignal        chan struct{}
-	requestMap        *sy.Map
-	recentAskedBlocks *sy.Map
+	blockCache       blockcache.BlockCache
+	router           Router
+	confirmNumber    int
+	heightChan       chan message.Message
+	blkSyncChan      chan message.Message
+	blkHashQueryChan chan message.Message
+	headerQueryChan  chan message.Message
+	headerRespChan   chan message.Message
+	snapQueryChan    chan message.Message
+	snapRespChan     chan message.Message
+	exitSignal       chan struct{}
+	downloader       *downloader
+	stateDB          *state.Database
+	snapshots        *snapshots
 
 	log *log.Logger
 }
 
-func NewSynchronizer(bc blockcache.BlockCache, router Router, confirmNumber int) *SyncImpl {
+// NewSynchronizer returns a synchronizer of bc, headers of blocks synchronized are checked by
+// verify before their bodies are downloaded. The state in state.StdDatabase is snapshotted for
+// peers, and restored from their snapshots if far behind.
+func NewSynchronizer(bc blockcache.BlockCache, router Router, confirmNumber int, verify HeadVerifier) *SyncImpl {
 	sync := &SyncImpl{
-		blockCache:        bc,
-		router:            router,
-		requestMap:        new(sy.Map),
-		confirmNumber:     confirmNumber,
-		recentAskedBlocks: new(sy.Map),
+		blockCache:    bc,
+		router:        router,
+		confirmNumber: confirmNumber,
+		exitSignal:    make(chan struct{}),
+		stateDB:       state.StdDatabase,
+		snapshots:     &snapshots{db: state.StdDatabase, dir: SnapshotDir},
 	}
+	sync.downloader = newDownloader(sync, verify)
 	var err error
 	sync.heightChan, err = sync.router.FilteredChan(Filter{
 		AcceptType: []ReqType{
@@ -75,9 +85,35 @@ func NewSynchronizer(bc blockcache.BlockCache, router Router, confirmNumber int)
 		return nil
 	}
 
-	sync.blkHashRespChan, err = sync.router.FilteredChan(Filter{
+	sync.headerQueryChan, err = sync.router.FilteredChan(Filter{
 		AcceptType: []ReqType{
-			BlockHashResponse,
+			ReqBlockHeaders,
+		}})
+	if err != nil {
+		return nil
+	}
+
+	sync.headerRespChan, err = sync.router.FilteredChan(Filter{
+		AcceptType: []ReqType{
+			RecvBlockHeaders,
+		}})
+	if err != nil {
+		return nil
+	}
+
+	sync.snapQueryChan, err = sync.router.FilteredChan(Filter{
+		AcceptType: []ReqType{
+			ReqSnapshotManifest,
+			ReqSnapshotChunk,
+		}})
+	if err != nil {
+		return nil
+	}
+
+	sync.snapRespChan, err = sync.router.FilteredChan(Filter{
+		AcceptType: []ReqType{
+			RecvSnapshotManifest,
+			RecvSnapshotChunk,
 		}})
 	if err != nil {
 		return nil
@@ -89,16 +125,19 @@ func NewSynchronizer(bc blockcache.BlockCache, router Router, confirmNumber int)
 	}
 
 	sync.log.NeedPrint = false
+	sync.snapshots.log = sync.log
 
 	return sync
 }
 
 func (sync *SyncImpl) StartListen() error {
 	go sync.requestBlockLoop()
-	go sync.retryDownloadLoop()
 	go sync.handleHashQuery()
-	go sync.handleHashResp()
-	go sync.recentAskedBlocksClean()
+	go sync.handleHeaderQuery()
+	go sync.handleHeaderResp()
+	go sync.handleSnapshotQuery()
+	go sync.handleSnapshotResp()
+	go sync.downloader.loop(sync.exitSignal)
 	return nil
 }
 
@@ -106,7 +145,10 @@ func (sync *SyncImpl) StopListen() error {
 	close(sync.blkSyncChan)
 	close(sync.exitSignal)
 	close(sync.blkHashQueryChan)
-	close(sync.blkHashRespChan)
+	close(sync.headerQueryChan)
+	close(sync.headerRespChan)
+	close(sync.snapQueryChan)
+	close(sync.snapRespChan)
 	return nil
 }
 
@@ -136,40 +178,24 @@ func (sync *SyncImpl) NeedSync(netHeight uint64) (bool, uint64, uint64) {
 	return false, 0, 0
 }
 
+// SyncBlocks synchronizes the blocks from startNumber to endNumber from the peers, if a sync is
+// running its target is raised to endNumber.
 func (sync *SyncImpl) SyncBlocks(startNumber uint64, endNumber uint64) error {
-	var syncNum int
-	for endNumber > startNumber+uint64(MaxBlockHashQueryNumber)-1 {
-		need := false
-		for i := startNumber; i < startNumber+uint64(MaxBlockHashQueryNumber); i++ {
-			_, ok := sync.requestMap.LoadOrStore(i, true)
-			if !ok {
-				need = true
-			}
-		}
-		if need {
-			syncNum++
-			sync.router.QueryBlockHash(startNumber, startNumber+uint64(MaxBlockHashQueryNumber)-1)
-		}
-		startNumber += uint64(MaxBlockHashQueryNumber)
-		if syncNum%10 == 0 {
-			time.Sleep(time.Second)
-		}
-	}
-	if startNumber <= endNumber {
-		need := false
-		for i := startNumber; i < endNumber; i++ {
-			_, ok := sync.requestMap.LoadOrStore(i, true)
-			if !ok {
-				need = true
-			}
-		}
-		if need {
-			sync.router.QueryBlockHash(startNumber, endNumber)
-		}
-	}
+	sync.downloader.begin(startNumber, endNumber)
 	return nil
 }
 
+// BlockDownloaded tells the synchronizer about a block answered by from, valid is false if the
+// block cache refused it. The sender is penalized only if blk does not match its header.
+func (sync *SyncImpl) BlockDownloaded(blk *block.Block, from string, valid bool) {
+	sync.downloader.blockDownloaded(blk, from, valid)
+}
+
+// Progress returns the progress of the running sync, or of the last one.
+func (sync *SyncImpl) Progress() SyncProgress {
+	return sync.downloader.progress()
+}
+
 func (sync *SyncImpl) requestBlockLoop() {
 
 	for {
@@ -218,36 +244,6 @@ func (sync *SyncImpl) requestBlockLoop() {
 	}
 }
 
-func (sync *SyncImpl) BlockConfirmed(num int64) {
-	sync.requestMap.Delete(uint64(num))
-}
-
-func (sync *SyncImpl) retryDownloadLoop() {
-	for {
-		select {
-		case <-time.After(time.Second * time.Duration(RetryTime)):
-			delList := make([]uint64, 0)
-			sync.requestMap.Range(func(k, v interface{}) bool {
-				num, ok := k.(uint64)
-				if !ok {
-					return false
-				}
-				if num < sync.blockCache.ConfirmedLength() {
-					delList = append(delList, num)
-				} else {
-					sync.router.QueryBlockHash(num, num)
-				}
-				return true
-			})
-			for _, num := range delList {
-				sync.requestMap.Delete(num)
-			}
-		case <-sync.exitSignal:
-			return
-		}
-	}
-}
-
 func (sync *SyncImpl) handleHashQuery() {
 	for {
 		select {
@@ -305,68 +301,89 @@ func (sync *SyncImpl) handleHashQuery() {
 	}
 }
 
-func (sync *SyncImpl) handleHashResp() {
-
+// handleHeaderQuery answers the headers of a range of the longest chain, MaxHeadersPerRequest at most.
+func (sync *SyncImpl) handleHeaderQuery() {
 	for {
 		select {
-		case req, ok := <-sync.blkHashRespChan:
+		case req, ok := <-sync.headerQueryChan:
 			if !ok {
-				break
+				return
 			}
-			var rh message.BlockHashResponse
-			_, err := rh.Unmarshal(req.Body)
-			if err != nil {
-				sync.log.E("unmarshal BlockHashResponse failed:%v", err)
-				break
+			var q message.BlockHashQuery
+			if _, err := q.Unmarshal(req.Body); err != nil || q.End < q.Start {
+				sync.router.Penalize(req.From, PenaltyBadMsg)
+				continue
 			}
-
-			sync.log.I("receive block hashes: len=%v", len(rh.BlockHashes))
-			for _, blkHash := range rh.BlockHashes {
-				if _, exist := sync.recentAskedBlocks.Load(string(blkHash.Hash)); exist {
-					continue
-				}
-				if !sync.blockCache.CheckBlock(blkHash.Hash) {
-					blkReq := &message.RequestBlock{
-						BlockHash:   blkHash.Hash,
-						BlockNumber: blkHash.Height,
-					}
-					if err != nil {
-						sync.log.E("marshal BlockHashResponse failed:struct=%v, err=%v", blkReq, err)
-						break
-					}
-					reqMsg := message.Message{
-						Time:    time.Now().Unix(),
-						From:    req.To,
-						To:      req.From,
-						ReqType: int32(ReqDownloadBlock),
-						Body:    blkReq.Encode(),
-					}
-					sync.router.Send(reqMsg)
-					sync.recentAskedBlocks.Store(string(blkHash.Hash), time.Now().Unix())
+			if q.End-q.Start+1 > MaxHeadersPerRequest {
+				q.End = q.Start + MaxHeadersPerRequest - 1
+			}
+			chain := sync.blockCache.LongestChain()
+			resp := &message.BlockHeaders{Heads: make([][]byte, 0, q.End-q.Start+1)}
+			for i := q.Start; i <= q.End; i++ {
+				blk := chain.GetBlockByNumber(i)
+				if blk == nil {
+					break
 				}
+				resp.Heads = append(resp.Heads, blk.Head.Encode())
 			}
-
+			if len(resp.Heads) == 0 {
+				continue
+			}
+			b, err := resp.Marshal(nil)
+			if err != nil {
+				sync.log.E("marshal BlockHeaders failed:%v", err)
+				continue
+			}
+			sync.router.Send(message.Message{
+				Time:    time.Now().UnixNano(),
+				From:    req.To,
+				To:      req.From,
+				ReqType: int32(RecvBlockHeaders),
+				Body:    b,
+			})
 		case <-sync.exitSignal:
 			return
 		}
 	}
 }
 
-func (sync *SyncImpl) recentAskedBlocksClean() {
+// decodeHeaders decodes the headers answered by a peer.
+func decodeHeaders(b []byte) (heads []*block.BlockHead, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid headers: %v", r)
+		}
+	}()
+	var resp message.BlockHeaders
+	if _, err = resp.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	heads = make([]*block.BlockHead, 0, len(resp.Heads))
+	for _, h := range resp.Heads {
+		head := &block.BlockHead{}
+		if err = head.Decode(h); err != nil {
+			return nil, err
+		}
+		heads = append(heads, head)
+	}
+	return heads, nil
+}
+
+func (sync *SyncImpl) handleHeaderResp() {
 	for {
 		select {
-		case <-time.After(cleanInterval):
-			sync.recentAskedBlocks.Range(func(k, v interface{}) bool {
-				t, ok := v.(int64)
-				if !ok {
-					sync.recentAskedBlocks.Delete(k)
-					return true
-				}
-				if time.Now().Unix()-t > blockDownloadTimeout {
-					sync.recentAskedBlocks.Delete(k)
-				}
-				return true
-			})
+		case req, ok := <-sync.headerRespChan:
+			if !ok {
+				return
+			}
+			heads, err := decodeHeaders(req.Body)
+			if err != nil {
+				sync.log.E("decode BlockHeaders failed:%v", err)
+				sync.router.Penalize(req.From, PenaltyBadMsg)
+				continue
+			}
+			sync.log.I("receive block headers: len=%v", len(heads))
+			sync.downloader.handleHeaders(req.From, heads)
 		case <-sync.exitSignal:
 			return
 		}
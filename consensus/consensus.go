@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/iost-official/Go-IOS-Protocol/account"
+	"github.com/iost-official/Go-IOS-Protocol/consensus/common"
 	"github.com/iost-official/Go-IOS-Protocol/consensus/pob"
 	"github.com/iost-official/Go-IOS-Protocol/core/block"
 	"github.com/iost-official/Go-IOS-Protocol/core/blockcache"
@@ -19,6 +20,7 @@ type Consensus interface {
 	BlockCache() blockcache.BlockCache
 	StatePool() state.Pool
 	CachedStatePool() state.Pool
+	SyncProgress() consensus_common.SyncProgress
 }
 
 const (
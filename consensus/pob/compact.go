@@ -0,0 +1,217 @@
+package pob
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/iost-official/Go-IOS-Protocol/core/tx"
+	. "github.com/iost-official/Go-IOS-Protocol/network"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/block"
+	"github.com/iost-official/Go-IOS-Protocol/core/message"
+	"github.com/iost-official/Go-IOS-Protocol/core/txpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// CompactBlockRelay makes produced blocks broadcast as compact blocks
+	CompactBlockRelay = true
+	// CompactBlockTimeout is how long missing txs are waited for before the full block is fetched
+	CompactBlockTimeout = 2 * time.Second
+
+	compactBlockReceivedCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "compact_block_received_count",
+			Help: "Count of received compact block by current node",
+		},
+	)
+	compactBlockRebuiltCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "compact_block_rebuilt_count",
+			Help: "Count of compact block rebuilt by current node",
+		},
+	)
+	compactBlockMissingTxCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "compact_block_missing_tx_count",
+			Help: "Count of compact block tx missing in the tx pool of current node",
+		},
+	)
+	compactBlockFallbackCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "compact_block_fallback_count",
+			Help: "Count of compact block fetched in full by current node",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(compactBlockReceivedCount)
+	prometheus.MustRegister(compactBlockRebuiltCount)
+	prometheus.MustRegister(compactBlockMissingTxCount)
+	prometheus.MustRegister(compactBlockFallbackCount)
+}
+
+// compactPending is a compact block waiting for its missing txs
+type compactPending struct {
+	blk     *block.Block
+	missing []int
+	req     message.Message
+	time    time.Time
+}
+
+// broadcastBlock sends blk to the neighbours, as a compact block if CompactBlockRelay is set
+func (p *PoB) broadcastBlock(blk *block.Block, bb []byte) {
+	msg := message.Message{ReqType: int32(ReqNewBlock), Body: bb}
+	if CompactBlockRelay {
+		msg = message.Message{ReqType: int32(ReqCompactBlock), Body: block.NewCompactBlock(blk).Encode()}
+	}
+	p.router.Broadcast(msg)
+}
+
+// handleCompactBlock rebuilds a compact block from the tx pool and asks the peer it came from for
+// missing txs
+func (p *PoB) handleCompactBlock(req message.Message) {
+	var cb block.CompactBlock
+	if err := cb.Decode(req.Body); err != nil {
+		p.router.PenalizeSender(req, PenaltyBadMsg)
+		return
+	}
+	compactBlockReceivedCount.Inc()
+
+	hash := cb.Head.Hash()
+	if _, ok := p.compactBlocks[string(hash)]; ok || p.blockCache.CheckBlock(hash) {
+		return
+	}
+
+	var index map[string]*Tx
+	if txpool.TxPoolS != nil {
+		index = txpool.TxPoolS.TransactionsByShortID()
+	}
+	blk, missing := cb.Rebuild(func(id string) *Tx {
+		return index[id]
+	})
+	if len(missing) == 0 {
+		p.completeCompactBlock(blk, req)
+		return
+	}
+	compactBlockMissingTxCount.Add(float64(len(missing)))
+
+	indexes := make([]int32, 0, len(missing))
+	for _, i := range missing {
+		indexes = append(indexes, int32(i))
+	}
+	p.compactBlocks[string(hash)] = &compactPending{blk: blk, missing: missing, req: req, time: time.Now()}
+	btr := message.BlockTxsRequest{BlockHash: hash, Indexes: indexes}
+	p.router.Send(message.Message{
+		Time:    time.Now().Unix(),
+		From:    req.To,
+		To:      p.router.Sender(req),
+		ReqType: int32(ReqBlockTxs),
+		Body:    btr.Encode(),
+	})
+}
+
+// handleBlockTxsRequest answers the txs of a block by their indexes
+func (p *PoB) handleBlockTxsRequest(req message.Message) {
+	var btr message.BlockTxsRequest
+	if err := btr.Decode(req.Body); err != nil {
+		p.router.Penalize(req.From, PenaltyBadMsg)
+		return
+	}
+	blk, err := p.blockCache.FindBlockInCache(btr.BlockHash)
+	if err != nil {
+		blk = p.blockCache.BlockChain().GetBlockByHash(btr.BlockHash)
+		if blk == nil {
+			return
+		}
+	}
+
+	resp := message.BlockTxs{BlockHash: btr.BlockHash, Indexes: make([]int32, 0), Txs: make([][]byte, 0)}
+	for _, i := range btr.Indexes {
+		if i < 0 || int(i) >= len(blk.Content) {
+			continue
+		}
+		resp.Indexes = append(resp.Indexes, i)
+		resp.Txs = append(resp.Txs, blk.Content[i].Encode())
+	}
+	p.router.Send(message.Message{
+		Time:    time.Now().Unix(),
+		From:    req.To,
+		To:      req.From,
+		ReqType: int32(RecvBlockTxs),
+		Body:    resp.Encode(),
+	})
+}
+
+// handleBlockTxs fills a pending compact block with the txs answered by the peer it came from
+func (p *PoB) handleBlockTxs(req message.Message) {
+	var resp message.BlockTxs
+	if err := resp.Decode(req.Body); err != nil {
+		p.router.Penalize(req.From, PenaltyBadMsg)
+		return
+	}
+	pending, ok := p.compactBlocks[string(resp.BlockHash)]
+	if !ok {
+		return
+	}
+	delete(p.compactBlocks, string(resp.BlockHash))
+
+	filled := make(map[int32]bool)
+	for i, index := range resp.Indexes {
+		if i >= len(resp.Txs) || index < 0 || int(index) >= len(pending.blk.Content) {
+			break
+		}
+		var t Tx
+		if err := t.Decode(resp.Txs[i]); err != nil {
+			break
+		}
+		pending.blk.Content[index] = t
+		filled[index] = true
+	}
+	for _, i := range pending.missing {
+		if !filled[int32(i)] {
+			p.fetchFullBlock(pending.blk, pending.req)
+			return
+		}
+	}
+	p.completeCompactBlock(pending.blk, pending.req)
+}
+
+// completeCompactBlock hands a rebuilt block to the block handling, a block that does not match its
+// tree hash is fetched in full
+func (p *PoB) completeCompactBlock(blk *block.Block, req message.Message) {
+	if !bytes.Equal(blk.CalculateTreeHash(), blk.Head.TreeHash) {
+		p.fetchFullBlock(blk, req)
+		return
+	}
+	compactBlockRebuiltCount.Inc()
+	compact := req
+	req.ReqType = int32(ReqNewBlock)
+	if p.handleBlock(blk, req) == nil {
+		p.router.Relay(compact)
+	}
+}
+
+// fetchFullBlock downloads blk from the peer its compact block came from
+func (p *PoB) fetchFullBlock(blk *block.Block, req message.Message) {
+	compactBlockFallbackCount.Inc()
+	rb := message.RequestBlock{BlockNumber: uint64(blk.Head.Number), BlockHash: blk.HeadHash()}
+	p.router.Send(message.Message{
+		Time:    time.Now().Unix(),
+		From:    req.To,
+		To:      p.router.Sender(req),
+		ReqType: int32(ReqDownloadBlock),
+		Body:    rb.Encode(),
+	})
+}
+
+// expireCompactBlocks fetches in full the compact blocks whose missing txs did not arrive in time
+func (p *PoB) expireCompactBlocks() {
+	for hash, pending := range p.compactBlocks {
+		if time.Since(pending.time) >= CompactBlockTimeout {
+			delete(p.compactBlocks, hash)
+			p.fetchFullBlock(pending.blk, pending.req)
+		}
+	}
+}
@@ -17,8 +17,6 @@ import (
 	"github.com/iost-official/Go-IOS-Protocol/core/blockcache"
 	"github.com/iost-official/Go-IOS-Protocol/core/message"
 
-	"math/rand"
-
 	"github.com/iost-official/Go-IOS-Protocol/core/state"
 	"github.com/iost-official/Go-IOS-Protocol/core/txpool"
 	"github.com/iost-official/Go-IOS-Protocol/log"
@@ -62,6 +60,8 @@ func init() {
 	prometheus.MustRegister(txPoolSize)
 }
 
+// TxPerBlk caps the number of pending txs considered for one block, blocks are bounded by the
+// gas and byte limits in chain parameters
 var TxPerBlk int
 
 type PoB struct {
@@ -72,8 +72,9 @@ type PoB struct {
 	globalStaticProperty
 	globalDynamicProperty
 
-	exitSignal chan struct{}
-	chBlock    chan message.Message
+	exitSignal    chan struct{}
+	chBlock       chan message.Message
+	compactBlocks map[string]*compactPending
 
 	log *log.Logger
 }
@@ -81,7 +82,8 @@ type PoB struct {
 func NewPoB(acc Account, bc block.Chain, pool state.Pool, witnessList []string /*, network core.Network*/) (*PoB, error) {
 	TxPerBlk = 800
 	p := PoB{
-		account: acc,
+		account:       acc,
+		compactBlocks: make(map[string]*compactPending),
 	}
 
 	p.blockCache = blockcache.NewBlockCache(bc, pool, len(witnessList)*2/3)
@@ -100,13 +102,13 @@ func NewPoB(acc Account, bc block.Chain, pool state.Pool, witnessList []string /
 		return nil, fmt.Errorf("failed to network.Route is nil")
 	}
 
-	p.synchronizer = NewSynchronizer(p.blockCache, p.router, len(witnessList)*2/3)
+	p.synchronizer = NewSynchronizer(p.blockCache, p.router, len(witnessList)*2/3, p.headVerify)
 	if p.synchronizer == nil {
 		return nil, err
 	}
 
 	p.chBlock, err = p.router.FilteredChan(Filter{
-		AcceptType: []ReqType{ReqNewBlock, ReqSyncBlock}})
+		AcceptType: []ReqType{ReqNewBlock, ReqSyncBlock, ReqCompactBlock, ReqBlockTxs, RecvBlockTxs}})
 	if err != nil {
 		return nil, err
 	}
@@ -161,11 +163,24 @@ func (p *PoB) CachedStatePool() state.Pool {
 	return p.blockCache.LongestPool()
 }
 
+// SyncProgress returns the progress of the block synchronization.
+func (p *PoB) SyncProgress() SyncProgress {
+	return p.synchronizer.Progress()
+}
+
 func (p *PoB) genesis(initTime int64) error {
 
 	main := lua.NewMethod(vm.Public, "", 0, 0)
 
-	var code string
+	code := fmt.Sprintf("@Put %v i%v\n", ChainIDKey, ChainID)
+	code += fmt.Sprintf("@Put %v i%v\n", verifier.MaxBlockGasKey, verifier.MaxBlockGas)
+	code += fmt.Sprintf("@Put %v i%v\n", verifier.MaxBlockSizeKey, verifier.MaxBlockSize)
+	code += fmt.Sprintf("@Put %v f%v\n", verifier.BlockRewardKey, verifier.BlockReward)
+	code += fmt.Sprintf("@Put %v i%v\n", verifier.RewardHalvingKey, verifier.RewardHalving)
+	code += fmt.Sprintf("@Put %v f%v\n", verifier.ServiRewardKey, verifier.ServiReward)
+	code += fmt.Sprintf("@Put %v i%v\n", verifier.ServiPeriodKey, verifier.ServiPeriod)
+	code += fmt.Sprintf("@Put %v i%v\n", vm.MaxDeferredGasKey, vm.MaxDeferredGas)
+	code += fmt.Sprintf("@Put %v i%v\n", vm.MaxDeferredPerBlockKey, vm.MaxDeferredPerBlock)
 	for k, v := range GenesisAccount {
 		code += fmt.Sprintf("@PutHM iost %v f%v\n", k, v)
 	}
@@ -206,52 +221,93 @@ func (p *PoB) genesis(initTime int64) error {
 
 func (p *PoB) blockLoop() {
 	p.log.I("Start to listen block")
+	compactTicker := time.NewTicker(CompactBlockTimeout / 2)
+	defer compactTicker.Stop()
 	for {
 		select {
 		case req, ok := <-p.chBlock:
 			if !ok {
 				return
 			}
-			var blk block.Block
-			err := blk.Decode(req.Body)
-			if err != nil {
+			switch req.ReqType {
+			case int32(ReqCompactBlock):
+				p.handleCompactBlock(req)
+				continue
+			case int32(ReqBlockTxs):
+				p.handleBlockTxsRequest(req)
+				continue
+			case int32(RecvBlockTxs):
+				p.handleBlockTxs(req)
 				continue
 			}
 
-			p.log.I("Received block:%v ,from=%v, timestamp: %v, Witness: %v, trNum: %v", blk.Head.Number, req.From, blk.Head.Time, blk.Head.Witness, len(blk.Content))
-			localLength := p.blockCache.ConfirmedLength()
-			if blk.Head.Number > int64(localLength)+MaxAcceptableLength {
-				if req.ReqType == int32(ReqNewBlock) {
-					go p.synchronizer.SyncBlocks(localLength, localLength+uint64(MaxAcceptableLength))
-				}
+			var blk block.Block
+			err := blk.Decode(req.Body)
+			if err != nil {
+				p.router.PenalizeSender(req, PenaltyBadMsg)
 				continue
 			}
-			err = p.blockCache.Add(&blk, p.blockVerify)
-			if err == nil {
-				p.log.I("Link it onto cached chain")
-				p.blockCache.SendOnBlock(&blk)
-				receivedBlockCount.Inc()
-			} else {
-				p.log.I("Error: %v", err)
-			}
-			if err != blockcache.ErrBlock && err != blockcache.ErrTooOld {
-				go p.synchronizer.BlockConfirmed(blk.Head.Number)
-				if err == nil {
-					p.globalDynamicProperty.update(&blk.Head)
-				} else if err == blockcache.ErrNotFound && req.ReqType == int32(ReqNewBlock) {
-					// New block is a single block
-					need, start, end := p.synchronizer.NeedSync(uint64(blk.Head.Number))
-					if need {
-						go p.synchronizer.SyncBlocks(start, end)
-					}
-				}
+			if p.handleBlock(&blk, req) == nil {
+				p.router.Relay(req)
 			}
+		case <-compactTicker.C:
+			p.expireCompactBlocks()
 		case <-p.exitSignal:
 			return
 		}
 	}
 }
 
+// handleBlock adds blk, received in req, to the block cache. It returns nil if blk was valid and
+// linked, then a broadcast block is relayed.
+func (p *PoB) handleBlock(blk *block.Block, req message.Message) error {
+	p.log.I("Received block:%v ,from=%v, timestamp: %v, Witness: %v, trNum: %v", blk.Head.Number, req.From, blk.Head.Time, blk.Head.Witness, len(blk.Content))
+	// the signature depends on no state, a block failing it was forged by whoever sent it. Blocks
+	// failing against the state only tell that the sender's state diverges, they are not penalized
+	if err := signatureVerify(&blk.Head); err != nil {
+		p.log.I("Error: %v", err)
+		p.router.PenalizeSender(req, PenaltyInvalidBlock)
+		if req.ReqType == int32(ReqSyncBlock) {
+			p.synchronizer.BlockDownloaded(blk, req.From, false)
+		}
+		return blockcache.ErrBlock
+	}
+	localLength := p.blockCache.ConfirmedLength()
+	if blk.Head.Number > int64(localLength)+MaxAcceptableLength {
+		if req.ReqType == int32(ReqNewBlock) {
+			go p.synchronizer.SyncBlocks(localLength, localLength+uint64(MaxAcceptableLength))
+		} else if req.ReqType == int32(ReqSyncBlock) {
+			// the block of a snapshot the state is restored from
+			p.synchronizer.BlockDownloaded(blk, req.From, true)
+		}
+		return blockcache.ErrNotFound
+	}
+	err := p.blockCache.Add(blk, p.blockVerify)
+	if req.ReqType == int32(ReqSyncBlock) {
+		p.synchronizer.BlockDownloaded(blk, req.From, err != blockcache.ErrBlock)
+	}
+	if err == nil {
+		p.log.I("Link it onto cached chain")
+		p.synchronizer.TakeSnapshot()
+		p.blockCache.SendOnBlock(blk)
+		receivedBlockCount.Inc()
+	} else {
+		p.log.I("Error: %v", err)
+	}
+	if err != blockcache.ErrBlock && err != blockcache.ErrTooOld {
+		if err == nil {
+			p.globalDynamicProperty.update(&blk.Head)
+		} else if err == blockcache.ErrNotFound && req.ReqType == int32(ReqNewBlock) {
+			// New block is a single block
+			need, start, end := p.synchronizer.NeedSync(uint64(blk.Head.Number))
+			if need {
+				go p.synchronizer.SyncBlocks(start, end)
+			}
+		}
+	}
+	return err
+}
+
 func (p *PoB) scheduleLoop() {
 	var nextSchedule int64
 	nextSchedule = 0
@@ -286,7 +342,7 @@ func (p *PoB) scheduleLoop() {
 				bb := blk.Encode()
 				msg := message.Message{ReqType: int32(ReqNewBlock), Body: bb}
 				log.Log.I("Block size: %v, TrNum: %v", len(bb), len(blk.Content))
-				go p.router.Broadcast(msg)
+				go p.broadcastBlock(blk, bb)
 				p.chBlock <- msg
 				p.log.I("Broadcasted block, current timestamp: %v number: %v", currentTimestamp, blk.Head.Number)
 			}
@@ -296,7 +352,7 @@ func (p *PoB) scheduleLoop() {
 }
 
 func (p *PoB) genBlock(acc Account, bc block.Chain, pool state.Pool) *block.Block {
-	limitTime := time.NewTicker(((SlotLength/3 - 1) + 1) * time.Second)
+	limitTime := time.After((SlotLength / 3) * time.Second)
 	lastBlk := bc.Top()
 	blk := block.Block{Content: []Tx{}, Head: block.BlockHead{
 		Version:    0,
@@ -304,6 +360,7 @@ func (p *PoB) genBlock(acc Account, bc block.Chain, pool state.Pool) *block.Bloc
 		Number:     lastBlk.Head.Number + 1,
 		Witness:    acc.ID,
 		Time:       GetCurrentTimestamp().Slot,
+		Info:       p.synchronizer.SnapshotCommit(),
 	}}
 	spool1 := pool.Copy()
 
@@ -312,8 +369,11 @@ func (p *PoB) genBlock(acc Account, bc block.Chain, pool state.Pool) *block.Bloc
 	vc.ParentHash = blk.Head.ParentHash
 	vc.BlockHeight = blk.Head.Number
 	vc.Witness = vm.IOSTAccount(acc.ID)
+	deferredGas := blockcache.StdDeferredVerifier(spool1, vc)
 
-	txCnt := TxPerBlk + rand.Intn(500)
+	maxGas, maxSize := verifier.BlockLimits(pool)
+	txCnt := TxPerBlk
+	// pending txs come ordered by fee per gas
 	var tx TransactionsList
 	if txpool.TxPoolS != nil {
 		p.log.I("PendingTransactions Begin...")
@@ -323,11 +383,13 @@ func (p *PoB) genBlock(acc Account, bc block.Chain, pool state.Pool) *block.Bloc
 		p.log.I("PendingTransactions Size: %v.", txpool.TxPoolS.PendingTransactionNum())
 	}
 
+	// deferred calls take their gas first
+	gas, size := deferredGas, uint64(0)
 	if len(tx) != 0 {
 	ForEnd:
 		for _, t := range tx {
 			select {
-			case <-limitTime.C:
+			case <-limitTime:
 				p.log.I("Gen Block Time Limit.")
 				break ForEnd
 			default:
@@ -335,23 +397,32 @@ func (p *PoB) genBlock(acc Account, bc block.Chain, pool state.Pool) *block.Bloc
 					p.log.I("Gen Block Tx Number Limit.")
 					break ForEnd
 				}
+				if gas+uint64(txpool.MinGasLimit) > maxGas {
+					p.log.I("Gen Block Gas Limit.")
+					break ForEnd
+				}
+				txGas, txSize := blockcache.TxGas(t), blockcache.TxSize(t)
+				if gas+txGas > maxGas || size+txSize > maxSize {
+					// a smaller tx may still fit
+					continue
+				}
 				if err := blockcache.StdCacheVerifier(t, spool1, vc); err == nil {
 					blk.Content = append(blk.Content, *t)
+					gas += txGas
+					size += txSize
 				}
 			}
 		}
 	}
 	blk.Head.TreeHash = blk.CalculateTreeHash()
 	headInfo := generateHeadInfo(blk.Head)
-	sig, _ := common.Sign(common.Secp256k1, headInfo, acc.Seckey)
+	sig, _ := common.Sign(acc.Algorithm, headInfo, acc.Seckey)
 	blk.Head.Signature = sig.Encode()
 
 	blockcache.CleanStdVerifier()
 
 	generatedBlockCount.Inc()
 
-	Data.ClearServi(blk.Head.Witness)
-
 	return &blk
 }
 
@@ -371,29 +442,45 @@ func generateHeadInfo(head block.BlockHead) []byte {
 	return common.Sha256(info)
 }
 
-func (p *PoB) blockVerify(blk *block.Block, parent *block.Block, pool state.Pool) (state.Pool, error) {
-	// verify block head
-	if err := blockcache.VerifyBlockHead(blk, parent); err != nil {
-		return nil, err
-	}
-
+// headVerify checks that head is signed by the witness of its slot.
+func (p *PoB) headVerify(head *block.BlockHead) error {
 	// verify block witness
-	if witnessOfTime(&p.globalStaticProperty, &p.globalDynamicProperty, Timestamp{Slot: blk.Head.Time}) != blk.Head.Witness {
-		return nil, errors.New("wrong witness")
+	if witnessOfTime(&p.globalStaticProperty, &p.globalDynamicProperty, Timestamp{Slot: head.Time}) != head.Witness {
+		return errors.New("wrong witness")
 
 	}
+	return signatureVerify(head)
+}
 
-	headInfo := generateHeadInfo(blk.Head)
+// signatureVerify checks that head is signed by its witness.
+func signatureVerify(head *block.BlockHead) error {
+	headInfo := generateHeadInfo(*head)
 	var signature common.Signature
-	signature.Decode(blk.Head.Signature)
+	signature.Decode(head.Signature)
 
-	if blk.Head.Witness != common.Base58Encode(signature.Pubkey) {
-		return nil, errors.New("wrong pubkey")
+	if head.Witness != common.Base58Encode(signature.Pubkey) {
+		return errors.New("wrong pubkey")
 	}
 
 	// verify block witness signature
 	if !common.VerifySignature(headInfo, signature) {
-		return nil, errors.New("wrong signature")
+		return errors.New("wrong signature")
+	}
+	return nil
+}
+
+func (p *PoB) blockVerify(blk *block.Block, parent *block.Block, pool state.Pool) (state.Pool, error) {
+	// verify block head
+	if err := blockcache.VerifyBlockHead(blk, parent); err != nil {
+		return nil, err
+	}
+
+	if err := p.headVerify(&blk.Head); err != nil {
+		return nil, err
+	}
+	// verify block limits against the chain parameters of the parent state
+	if err := blockcache.VerifyBlockLimits(blk, pool); err != nil {
+		return nil, err
 	}
 	newPool, err := blockcache.StdBlockVerifier(blk, pool)
 	if err != nil {
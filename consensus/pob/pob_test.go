@@ -37,6 +37,7 @@ func TestNewPoB(t *testing.T) {
 		mockPool := core_mock.NewMockPool(mockCtr)
 		mockPool.EXPECT().Copy().Return(mockPool).AnyTimes()
 		mockPool.EXPECT().PutHM(Any(), Any(), Any()).AnyTimes().Return(nil)
+		mockPool.EXPECT().Put(Any(), Any()).AnyTimes()
 		mockPool.EXPECT().Flush().AnyTimes().Return(nil)
 
 		network.Route = mockRouter
@@ -57,6 +58,10 @@ func TestNewPoB(t *testing.T) {
 		mockRouter.EXPECT().FilteredChan(Any()).Return(blockChan, nil)
 
 		mockRouter.EXPECT().FilteredChan(Any()).Return(blockChan, nil).AnyTimes()
+		mockRouter.EXPECT().Penalize(Any(), Any()).AnyTimes()
+		mockRouter.EXPECT().PenalizeSender(Any(), Any()).AnyTimes()
+		mockRouter.EXPECT().Relay(Any()).AnyTimes()
+		mockRouter.EXPECT().PeerHeights().AnyTimes()
 
 		blk := block.Block{Content: []tx.Tx{}, Head: block.BlockHead{
 			Version:    0,
@@ -175,6 +180,10 @@ func envinit(t *testing.T) (*PoB, []account.Account, []string, *txpool.TxPoolSer
 	blkChan := make(chan message.Message, 1)
 	mockRouter.EXPECT().FilteredChan(Any()).Return(blkChan, nil)
 	mockRouter.EXPECT().FilteredChan(Any()).Return(blkChan, nil).AnyTimes()
+	mockRouter.EXPECT().Penalize(Any(), Any()).AnyTimes()
+	mockRouter.EXPECT().PenalizeSender(Any(), Any()).AnyTimes()
+	mockRouter.EXPECT().Relay(Any()).AnyTimes()
+	mockRouter.EXPECT().PeerHeights().AnyTimes()
 
 	defer guard.Unpatch()
 
@@ -193,10 +202,7 @@ func envinit(t *testing.T) (*PoB, []account.Account, []string, *txpool.TxPoolSer
 		panic("state.PoolInstance error")
 	}
 
-	sp, e := tx.NewServiPool(len(account.GenesisAccount), 1000)
-	So(e, ShouldBeNil)
-
-	tx.Data = tx.NewHolder(_account, state.StdPool, sp)
+	tx.Data = tx.NewHolder(_account, state.StdPool)
 
 	blockChain.Top()
 	p, err := NewPoB(accountList[0], blockChain, state.StdPool, witnessList)
@@ -476,6 +482,10 @@ func envInit(b *testing.B) (*PoB, []account.Account, []string, *txpool.TxPoolSer
 	blkChan := make(chan message.Message, 1)
 	mockRouter.EXPECT().FilteredChan(Any()).Return(blkChan, nil)
 	mockRouter.EXPECT().FilteredChan(Any()).Return(blkChan, nil).AnyTimes()
+	mockRouter.EXPECT().Penalize(Any(), Any()).AnyTimes()
+	mockRouter.EXPECT().PenalizeSender(Any(), Any()).AnyTimes()
+	mockRouter.EXPECT().Relay(Any()).AnyTimes()
+	mockRouter.EXPECT().PeerHeights().AnyTimes()
 	defer guard.Unpatch()
 
 	txDb := tx.TxDbInstance()
@@ -35,7 +35,7 @@ func (p *RecorderImpl) Record(tx2 tx.Tx) error {
 	p.isListening.Lock()
 	defer p.isListening.Unlock()
 	if blockcache.VerifyTxSig(tx2) {
-		tx.RecordTx(tx2, tx.Data.Self())
+		tx2, _ = tx.RecordTx(tx2, tx.Data.Self())
 		p.storage[string(tx2.Hash())] = true
 		p.Push(tx2)
 		return nil
@@ -33,10 +33,13 @@ func (d *Block) String() string {
 	return str
 }
 
+// CalculateTreeHash hashes the publisher and recorder signatures of the txs, the witness signs it
+// with the head so that the recorders credited by the block can not be swapped
 func (d *Block) CalculateTreeHash() []byte {
 	treeHash := make([]byte, 0)
 	for _, tx := range d.Content {
 		treeHash = append(treeHash, tx.Publisher.Sig...)
+		treeHash = append(treeHash, tx.Recorder.Sig...)
 	}
 	return common.Sha256(treeHash)
 }
@@ -51,11 +51,9 @@ func TestChainImpl(t *testing.T) {
 			panic("state.PoolInstance error")
 		}
 
-		sp, e := tx.NewServiPool(len(account.GenesisAccount), 1000)
-		So(e, ShouldBeNil)
 		acc, err := account.NewAccount(common.Base58Decode("4PpkMbuJauTeqX1VZw4qeYrc9jNbdAbBUi3q6dVR7sMC"))
 		So(err, ShouldBeNil)
-		tx.Data = tx.NewHolder(acc, state.StdPool, sp)
+		tx.Data = tx.NewHolder(acc, state.StdPool)
 
 		Convey("Push", func() {
 			length := bc.Length()
@@ -106,3 +104,36 @@ func TestChainImpl(t *testing.T) {
 		})
 	})
 }
+
+func TestCompactBlock(t *testing.T) {
+	Convey("test CompactBlock", t, func() {
+		blk := Block{Head: BlockHead{Number: 3, Time: 201222}, Content: make([]tx.Tx, 0)}
+		for i := 0; i < 3; i++ {
+			trx := tx.Tx{Nonce: int64(i), Time: int64(i)}
+			trx.Publisher.Sig = []byte{byte(i), 'p'}
+			blk.Content = append(blk.Content, trx)
+		}
+		blk.Head.TreeHash = blk.CalculateTreeHash()
+
+		var cb CompactBlock
+		So(cb.Decode(NewCompactBlock(&blk).Encode()), ShouldBeNil)
+		So(len(cb.ShortIDs), ShouldEqual, 3)
+
+		pool := map[string]*tx.Tx{
+			ShortTxID(&blk.Content[0]): &blk.Content[0],
+			ShortTxID(&blk.Content[2]): &blk.Content[2],
+		}
+		rebuilt, missing := cb.Rebuild(func(id string) *tx.Tx { return pool[id] })
+		So(missing, ShouldResemble, []int{1})
+
+		rebuilt.Content[1] = blk.Content[1]
+		So(rebuilt.CalculateTreeHash(), ShouldResemble, blk.Head.TreeHash)
+		So(rebuilt.Content[2].Nonce, ShouldEqual, 2)
+
+		recorded := blk.Content[2]
+		recorded.Recorder.Sig = []byte{'r'}
+		So(ShortTxID(&recorded), ShouldNotEqual, ShortTxID(&blk.Content[2]))
+		rebuilt.Content[2] = recorded
+		So(rebuilt.CalculateTreeHash(), ShouldNotResemble, blk.Head.TreeHash)
+	})
+}
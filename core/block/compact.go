@@ -0,0 +1,52 @@
+package block
+
+import (
+	"github.com/iost-official/Go-IOS-Protocol/common"
+	"github.com/iost-official/Go-IOS-Protocol/core/tx"
+)
+
+// ShortIDLength is the length of tx ids carried by compact blocks
+const ShortIDLength = 8
+
+// ShortTxID identifies t in compact blocks, it is derived from the publisher and recorder signatures
+// like the tree hash, so a tx recorded by another node is fetched rather than rebuilt from the pool
+func ShortTxID(t *tx.Tx) string {
+	return string(common.Sha256(append(append([]byte{}, t.Publisher.Sig...), t.Recorder.Sig...))[:ShortIDLength])
+}
+
+// NewCompactBlock replaces txs of blk by their short ids
+func NewCompactBlock(blk *Block) *CompactBlock {
+	ids := make([][]byte, 0, len(blk.Content))
+	for i := range blk.Content {
+		ids = append(ids, []byte(ShortTxID(&blk.Content[i])))
+	}
+	return &CompactBlock{Head: blk.Head, ShortIDs: ids}
+}
+
+func (c *CompactBlock) Encode() []byte {
+	b, err := c.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (c *CompactBlock) Decode(bin []byte) error {
+	_, err := c.Unmarshal(bin)
+	return err
+}
+
+// Rebuild fills the block with txs found by lookup, the indexes of txs not found are returned
+func (c *CompactBlock) Rebuild(lookup func(shortID string) *tx.Tx) (*Block, []int) {
+	blk := &Block{Head: c.Head, Content: make([]tx.Tx, len(c.ShortIDs))}
+	missing := make([]int, 0)
+	for i, id := range c.ShortIDs {
+		t := lookup(string(id))
+		if t == nil {
+			missing = append(missing, i)
+			continue
+		}
+		blk.Content[i] = *t
+	}
+	return blk, missing
+}
@@ -582,3 +582,176 @@ func (d *BlockRaw) Unmarshal(buf []byte) (uint64, error) {
 	}
 	return i + 0, nil
 }
+
+type CompactBlock struct {
+	Head     BlockHead
+	ShortIDs [][]byte
+}
+
+func (d *CompactBlock) Size() (s uint64) {
+
+	{
+		s += d.Head.Size()
+	}
+	{
+		l := uint64(len(d.ShortIDs))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.ShortIDs {
+
+			{
+				l := uint64(len(d.ShortIDs[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	return
+}
+func (d *CompactBlock) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		nbuf, err := d.Head.Marshal(buf[0:])
+		if err != nil {
+			return nil, err
+		}
+		i += uint64(len(nbuf))
+	}
+	{
+		l := uint64(len(d.ShortIDs))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.ShortIDs {
+
+			{
+				l := uint64(len(d.ShortIDs[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+0] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+0] = byte(t)
+					i++
+
+				}
+				copy(buf[i+0:], d.ShortIDs[k0])
+				i += l
+			}
+
+		}
+	}
+	return buf[:i+0], nil
+}
+
+func (d *CompactBlock) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		ni, err := d.Head.Unmarshal(buf[i+0:])
+		if err != nil {
+			return 0, err
+		}
+		i += ni
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.ShortIDs)) >= l {
+			d.ShortIDs = d.ShortIDs[:l]
+		} else {
+			d.ShortIDs = make([][]byte, l)
+		}
+		for k0 := range d.ShortIDs {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+0] & 0x7F)
+					for buf[i+0]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+0]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				if uint64(cap(d.ShortIDs[k0])) >= l {
+					d.ShortIDs[k0] = d.ShortIDs[k0][:l]
+				} else {
+					d.ShortIDs[k0] = make([]byte, l)
+				}
+				copy(d.ShortIDs[k0], buf[i+0:])
+				i += l
+			}
+
+		}
+	}
+	return i + 0, nil
+}
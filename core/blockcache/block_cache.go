@@ -119,6 +119,7 @@ type BlockCache interface {
 	BlockConfirmChan() chan uint64
 	OnBlockChan() chan *block.Block
 	SendOnBlock(blk *block.Block)
+	Restore(blk *block.Block) error
 }
 
 type BlockCacheImpl struct {
@@ -413,6 +414,31 @@ func (h *BlockCacheImpl) LongestPool() state.Pool {
 	}
 }
 
+// Restore makes blk the confirmed root of the cache, the base pool must hold the state after blk,
+// restored from a snapshot. The blocks cached are dropped.
+func (h *BlockCacheImpl) Restore(blk *block.Block) error {
+	err := h.bc.Push(blk)
+	if err != nil {
+		return err
+	}
+	h.cachedRoot = &BlockCacheTree{
+		bc:       NewCBC(h.bc),
+		children: make([]*BlockCacheTree, 0),
+		super:    nil,
+		pool:     h.cachedRoot.pool,
+		bctType:  OnCache,
+	}
+	h.singleBlockRoot = &BlockCacheTree{
+		bc:       NewCBC(h.bc),
+		children: make([]*BlockCacheTree, 0),
+		super:    nil,
+		bctType:  Singles,
+	}
+	h.hashMap = new(sync.Map)
+	h.hashMap.Store(string(blk.HeadHash()), h.cachedRoot)
+	return nil
+}
+
 func (h *BlockCacheImpl) BlockConfirmChan() chan uint64 {
 	return h.blkConfirmChan
 }
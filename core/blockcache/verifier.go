@@ -36,6 +36,44 @@ func VerifyBlockHead(blk *block.Block, parentBlk *block.Block) error {
 	return nil
 }
 
+var (
+	ErrBlockGas  = errors.New("block gas limit exceeded")
+	ErrBlockSize = errors.New("block size limit exceeded")
+)
+
+// TxGas is what a tx counts against the gas limit of blocks, its gas limit, so that blocks
+// can be checked before execution
+func TxGas(t *tx.Tx) uint64 {
+	return uint64(t.Contract.Info().GasLimit)
+}
+
+// TxSize is what a tx counts against the byte limit of blocks
+func TxSize(t *tx.Tx) uint64 {
+	return uint64(len(t.Encode()))
+}
+
+// VerifyBlockLimits checks txs of blk against the block limits recorded in the chain parameters of pool
+func VerifyBlockLimits(blk *block.Block, pool state.Pool) error {
+	return verifyBlockLimits(blk, pool, 0)
+}
+
+// verifyBlockLimits is VerifyBlockLimits for a block whose deferred calls were given deferredGas
+func verifyBlockLimits(blk *block.Block, pool state.Pool, deferredGas uint64) error {
+	maxGas, maxSize := verifier.BlockLimits(pool)
+	gas, size := deferredGas, uint64(0)
+	for i := range blk.Content {
+		gas += TxGas(&blk.Content[i])
+		size += TxSize(&blk.Content[i])
+	}
+	if gas > maxGas {
+		return ErrBlockGas
+	}
+	if size > maxSize {
+		return ErrBlockSize
+	}
+	return nil
+}
+
 var ver *verifier.CacheVerifier
 var verb *verifier.CacheVerifier
 
@@ -49,29 +87,48 @@ func StdBlockVerifier(block *block.Block, pool state.Pool) (state.Pool, error) {
 	ver.Context.Timestamp = block.Head.Time
 	ver.Context.BlockHeight = block.Head.Number
 	ver.Context.Witness = vm.IOSTAccount(block.Head.Witness)
+	ver.Fees = 0
 
 	txs := block.Content
 	ptxs := make([]*tx.Tx, 0)
 	for i := range txs {
 		ptxs = append(ptxs, &(txs[i]))
 	}
-	pool2, _, err := StdTxsVerifier(ptxs, pool.Copy())
+	pool1 := pool.Copy()
+	deferredGas := ver.RunDeferred(pool1)
+	if err := verifyBlockLimits(block, pool, deferredGas); err != nil {
+		return pool, err
+	}
+	pool2, _, err := StdTxsVerifier(ptxs, pool1)
 	if err != nil {
 		return pool, err
 	}
+	// the witness collects the fees of the block and the block reward
+	verifier.PayBlockReward(pool2, vm.IOSTAccount(block.Head.Witness), block.Head.Number, ver.Fees)
+	recordings := make([]verifier.Recording, 0)
+	for _, t := range ptxs {
+		if r, ok := t.RecordedBy(); ok {
+			recordings = append(recordings, verifier.Recording{Recorder: r, Publisher: vm.PubkeyToIOSTAccount(t.Publisher.Pubkey)})
+		}
+	}
+	verifier.RecordServi(pool2, recordings, block.Head.Number)
 	return pool2.MergeParent()
 }
 
 func StdTxsVerifier(txs []*tx.Tx, pool state.Pool) (state.Pool, int, error) {
 	pool2 := pool.Copy()
 	for i, txx := range txs {
-		var err error
+		sender := txx.Contract.Info().Publisher
+		err := verifier.CheckNonce(sender, txx.Nonce, pool2)
+		if err != nil {
+			return pool2, i, err
+		}
 		pool2, err = ver.VerifyContract(txx.Contract, pool2)
 
 		if err != nil {
 			return pool2, i, err
 		}
-
+		verifier.SetNonce(sender, pool2, txx.Nonce)
 	}
 
 	return pool2, len(txs), nil
@@ -89,6 +146,11 @@ func StdCacheVerifier(txx *tx.Tx, pool state.Pool, context *vm.Context) error {
 
 	verb.Context = context
 
+	sender := txx.Contract.Info().Publisher
+	if err := verifier.CheckNonce(sender, txx.Nonce, pool); err != nil {
+		return err
+	}
+
 	var p2 state.Pool = nil
 	var err error = nil
 
@@ -104,6 +166,7 @@ func StdCacheVerifier(txx *tx.Tx, pool state.Pool, context *vm.Context) error {
 			host.Log(err.Error(), txx.Contract.Info().Prefix)
 			return err
 		}
+		verifier.SetNonce(sender, p2, txx.Nonce)
 		p2.MergeParent()
 		return nil
 	} else {
@@ -111,6 +174,17 @@ func StdCacheVerifier(txx *tx.Tx, pool state.Pool, context *vm.Context) error {
 	}
 }
 
+// StdDeferredVerifier applies to pool the deferred calls due at the block of context and returns the
+// gas they were given. Producers run it before packing txs so that txs are checked against the state
+// they will be applied to, and fit in the gas left
+func StdDeferredVerifier(pool state.Pool, context *vm.Context) uint64 {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+
+	verb.Context = context
+	return verb.RunDeferred(pool)
+}
+
 type VerifyContext struct {
 	VParentHash []byte
 }
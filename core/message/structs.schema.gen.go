@@ -810,3 +810,1052 @@ func (d *BlockHashResponse) Unmarshal(buf []byte) (uint64, error) {
 	}
 	return i + 0, nil
 }
+
+type TxHashes struct {
+	Hashes []string
+}
+
+func (d *TxHashes) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Hashes))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Hashes {
+
+			{
+				l := uint64(len(d.Hashes[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	return
+}
+func (d *TxHashes) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Hashes))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Hashes {
+
+			{
+				l := uint64(len(d.Hashes[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+0] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+0] = byte(t)
+					i++
+
+				}
+				copy(buf[i+0:], d.Hashes[k0])
+				i += l
+			}
+
+		}
+	}
+	return buf[:i+0], nil
+}
+
+func (d *TxHashes) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Hashes)) >= l {
+			d.Hashes = d.Hashes[:l]
+		} else {
+			d.Hashes = make([]string, l)
+		}
+		for k0 := range d.Hashes {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+0] & 0x7F)
+					for buf[i+0]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+0]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.Hashes[k0] = string(buf[i+0 : i+0+l])
+				i += l
+			}
+
+		}
+	}
+	return i + 0, nil
+}
+
+type BlockTxsRequest struct {
+	BlockHash []byte
+	Indexes   []int32
+}
+
+func (d *BlockTxsRequest) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.BlockHash))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Indexes))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		s += 4 * l
+
+	}
+	return
+}
+func (d *BlockTxsRequest) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.BlockHash))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.BlockHash)
+		i += l
+	}
+	{
+		l := uint64(len(d.Indexes))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Indexes {
+
+			{
+
+				buf[i+0+0] = byte(d.Indexes[k0] >> 0)
+
+				buf[i+1+0] = byte(d.Indexes[k0] >> 8)
+
+				buf[i+2+0] = byte(d.Indexes[k0] >> 16)
+
+				buf[i+3+0] = byte(d.Indexes[k0] >> 24)
+
+			}
+
+			i += 4
+
+		}
+	}
+	return buf[:i+0], nil
+}
+
+func (d *BlockTxsRequest) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.BlockHash)) >= l {
+			d.BlockHash = d.BlockHash[:l]
+		} else {
+			d.BlockHash = make([]byte, l)
+		}
+		copy(d.BlockHash, buf[i+0:])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Indexes)) >= l {
+			d.Indexes = d.Indexes[:l]
+		} else {
+			d.Indexes = make([]int32, l)
+		}
+		for k0 := range d.Indexes {
+
+			{
+
+				d.Indexes[k0] = 0 | (int32(buf[i+0+0]) << 0) | (int32(buf[i+1+0]) << 8) | (int32(buf[i+2+0]) << 16) | (int32(buf[i+3+0]) << 24)
+
+			}
+
+			i += 4
+
+		}
+	}
+	return i + 0, nil
+}
+
+type BlockTxs struct {
+	BlockHash []byte
+	Indexes   []int32
+	Txs       [][]byte
+}
+
+func (d *BlockTxs) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.BlockHash))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Indexes))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		s += 4 * l
+
+	}
+	{
+		l := uint64(len(d.Txs))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Txs {
+
+			{
+				l := uint64(len(d.Txs[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	return
+}
+func (d *BlockTxs) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.BlockHash))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.BlockHash)
+		i += l
+	}
+	{
+		l := uint64(len(d.Indexes))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Indexes {
+
+			{
+
+				buf[i+0+0] = byte(d.Indexes[k0] >> 0)
+
+				buf[i+1+0] = byte(d.Indexes[k0] >> 8)
+
+				buf[i+2+0] = byte(d.Indexes[k0] >> 16)
+
+				buf[i+3+0] = byte(d.Indexes[k0] >> 24)
+
+			}
+
+			i += 4
+
+		}
+	}
+	{
+		l := uint64(len(d.Txs))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Txs {
+
+			{
+				l := uint64(len(d.Txs[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+0] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+0] = byte(t)
+					i++
+
+				}
+				copy(buf[i+0:], d.Txs[k0])
+				i += l
+			}
+
+		}
+	}
+	return buf[:i+0], nil
+}
+
+func (d *BlockTxs) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.BlockHash)) >= l {
+			d.BlockHash = d.BlockHash[:l]
+		} else {
+			d.BlockHash = make([]byte, l)
+		}
+		copy(d.BlockHash, buf[i+0:])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Indexes)) >= l {
+			d.Indexes = d.Indexes[:l]
+		} else {
+			d.Indexes = make([]int32, l)
+		}
+		for k0 := range d.Indexes {
+
+			{
+
+				d.Indexes[k0] = 0 | (int32(buf[i+0+0]) << 0) | (int32(buf[i+1+0]) << 8) | (int32(buf[i+2+0]) << 16) | (int32(buf[i+3+0]) << 24)
+
+			}
+
+			i += 4
+
+		}
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Txs)) >= l {
+			d.Txs = d.Txs[:l]
+		} else {
+			d.Txs = make([][]byte, l)
+		}
+		for k0 := range d.Txs {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+0] & 0x7F)
+					for buf[i+0]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+0]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				if uint64(cap(d.Txs[k0])) >= l {
+					d.Txs[k0] = d.Txs[k0][:l]
+				} else {
+					d.Txs[k0] = make([]byte, l)
+				}
+				copy(d.Txs[k0], buf[i+0:])
+				i += l
+			}
+
+		}
+	}
+	return i + 0, nil
+}
+
+type BlockHeaders struct {
+	Heads [][]byte
+}
+
+func (d *BlockHeaders) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Heads))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Heads {
+
+			{
+				l := uint64(len(d.Heads[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	return
+}
+func (d *BlockHeaders) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Heads))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Heads {
+
+			{
+				l := uint64(len(d.Heads[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+0] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+0] = byte(t)
+					i++
+
+				}
+				copy(buf[i+0:], d.Heads[k0])
+				i += l
+			}
+
+		}
+	}
+	return buf[:i+0], nil
+}
+
+func (d *BlockHeaders) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Heads)) >= l {
+			d.Heads = d.Heads[:l]
+		} else {
+			d.Heads = make([][]byte, l)
+		}
+		for k0 := range d.Heads {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+0] & 0x7F)
+					for buf[i+0]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+0]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				if uint64(cap(d.Heads[k0])) >= l {
+					d.Heads[k0] = d.Heads[k0][:l]
+				} else {
+					d.Heads[k0] = make([]byte, l)
+				}
+				copy(d.Heads[k0], buf[i+0:])
+				i += l
+			}
+
+		}
+	}
+	return i + 0, nil
+}
+
+type SnapshotChunkQuery struct {
+	Root  []byte
+	Index uint32
+}
+
+func (d *SnapshotChunkQuery) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Root))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 4
+	return
+}
+func (d *SnapshotChunkQuery) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Root))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Root)
+		i += l
+	}
+	{
+
+		buf[i+0+0] = byte(d.Index >> 0)
+
+		buf[i+1+0] = byte(d.Index >> 8)
+
+		buf[i+2+0] = byte(d.Index >> 16)
+
+		buf[i+3+0] = byte(d.Index >> 24)
+
+	}
+	return buf[:i+4], nil
+}
+
+func (d *SnapshotChunkQuery) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Root)) >= l {
+			d.Root = d.Root[:l]
+		} else {
+			d.Root = make([]byte, l)
+		}
+		copy(d.Root, buf[i+0:])
+		i += l
+	}
+	{
+
+		d.Index = 0 | (uint32(buf[i+0+0]) << 0) | (uint32(buf[i+1+0]) << 8) | (uint32(buf[i+2+0]) << 16) | (uint32(buf[i+3+0]) << 24)
+
+	}
+	return i + 4, nil
+}
+
+type SnapshotChunk struct {
+	Root  []byte
+	Index uint32
+	Data  []byte
+}
+
+func (d *SnapshotChunk) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Root))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Data))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 4
+	return
+}
+func (d *SnapshotChunk) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Root))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Root)
+		i += l
+	}
+	{
+
+		buf[i+0+0] = byte(d.Index >> 0)
+
+		buf[i+1+0] = byte(d.Index >> 8)
+
+		buf[i+2+0] = byte(d.Index >> 16)
+
+		buf[i+3+0] = byte(d.Index >> 24)
+
+	}
+	{
+		l := uint64(len(d.Data))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+4] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+4] = byte(t)
+			i++
+
+		}
+		copy(buf[i+4:], d.Data)
+		i += l
+	}
+	return buf[:i+4], nil
+}
+
+func (d *SnapshotChunk) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Root)) >= l {
+			d.Root = d.Root[:l]
+		} else {
+			d.Root = make([]byte, l)
+		}
+		copy(d.Root, buf[i+0:])
+		i += l
+	}
+	{
+
+		d.Index = 0 | (uint32(buf[i+0+0]) << 0) | (uint32(buf[i+1+0]) << 8) | (uint32(buf[i+2+0]) << 16) | (uint32(buf[i+3+0]) << 24)
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+4] & 0x7F)
+			for buf[i+4]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+4]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Data)) >= l {
+			d.Data = d.Data[:l]
+		} else {
+			d.Data = make([]byte, l)
+		}
+		copy(d.Data, buf[i+4:])
+		i += l
+	}
+	return i + 4, nil
+}
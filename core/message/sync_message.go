@@ -53,3 +53,57 @@ func (d *RequestBlock) Decode(bin []byte) error {
 
 	return nil
 }
+
+func (d *TxHashes) Encode() []byte {
+	b, err := d.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+func (d *TxHashes) Decode(bin []byte) error {
+	_, err := d.Unmarshal(bin)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d *BlockTxsRequest) Encode() []byte {
+	b, err := d.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+func (d *BlockTxsRequest) Decode(bin []byte) error {
+	_, err := d.Unmarshal(bin)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d *BlockTxs) Encode() []byte {
+	b, err := d.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+func (d *BlockTxs) Decode(bin []byte) error {
+	_, err := d.Unmarshal(bin)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -152,6 +152,9 @@ func (v *VString) Type() Type {
 func (v *VString) EncodeString() string {
 	return "s" + v.string
 }
+func (v *VString) ToString() string {
+	return v.string
+}
 
 type VInt struct {
 	int
@@ -188,6 +191,9 @@ func (v *VBytes) Type() Type {
 func (v *VBytes) EncodeString() string {
 	return "b" + base64.StdEncoding.EncodeToString(v.val)
 }
+func (v *VBytes) ToBytes() []byte {
+	return v.val
+}
 
 type VFloat struct {
 	float64
@@ -0,0 +1,346 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/iost-official/Go-IOS-Protocol/common"
+	"github.com/iost-official/Go-IOS-Protocol/db"
+)
+
+// SnapshotChunkSize bounds the encoded entries of a snapshot chunk, a chunk is sent to a peer in
+// one message.
+var SnapshotChunkSize uint64 = 128 << 10
+
+var (
+	ErrKeysUnsupported = errors.New("keys of the database can not be listed")
+	ErrChunkIndex      = errors.New("chunk index out of range")
+	ErrChunkHash       = errors.New("chunk does not match the snapshot")
+	ErrViewOpen        = errors.New("a view of the state is open")
+)
+
+// KeysDatabase is a db.Database whose keys can be listed, only the state in one can be
+// snapshotted.
+type KeysDatabase interface {
+	db.Database
+	Keys(prefix []byte) ([][]byte, error)
+}
+
+// StdDatabase is the database of StdPool.
+var StdDatabase *Database
+
+// keys returns the keys of the state sorted, out of the namespace.
+func (d *Database) keys() ([][]byte, error) {
+	kdb, ok := d.db.(KeysDatabase)
+	if !ok {
+		return nil, ErrKeysUnsupported
+	}
+	keys, err := kdb.Keys([]byte(Namespace))
+	if err != nil {
+		return nil, err
+	}
+	for i, k := range keys {
+		keys[i] = k[len(Namespace):]
+	}
+	return sortKeys(keys), nil
+}
+
+// sortKeys sorts keys and drops the duplicates.
+func sortKeys(keys [][]byte) [][]byte {
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	// a key may be listed twice if the database changed meanwhile
+	uniq := keys[:0]
+	for i, k := range keys {
+		if i == 0 || !bytes.Equal(k, keys[i-1]) {
+			uniq = append(uniq, k)
+		}
+	}
+	return uniq
+}
+
+// entries returns the entries of key, one per field with fields sorted if it is a hash.
+func (d *Database) entries(key []byte) ([]StateEntry, error) {
+	if hdb, ok := d.db.(HashDatabase); ok {
+		t, err := hdb.Type(string(encode(Key(key))))
+		if err != nil {
+			return nil, err
+		}
+		if t == "hash" {
+			m, err := hdb.GetAll(string(encode(Key(key))))
+			if err != nil {
+				return nil, err
+			}
+			fields := make([]string, 0, len(m))
+			for f := range m {
+				fields = append(fields, f)
+			}
+			sort.Strings(fields)
+			entries := make([]StateEntry, 0, len(fields))
+			for _, f := range fields {
+				entries = append(entries, StateEntry{Key: key, Field: []byte(f), Hash: true, Val: []byte(m[f])})
+			}
+			return entries, nil
+		}
+	}
+	raw, err := d.db.Get(encode(Key(key)))
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	return []StateEntry{{Key: key, Val: raw}}, nil
+}
+
+// views tracks the view open on a Database.
+type views struct {
+	mu   sync.Mutex
+	open *View
+}
+
+// View is the state of a Database as it was when the view was opened, whatever is written
+// meanwhile: the keys written are kept as they were before their first write.
+type View struct {
+	d   *Database
+	old map[string][]StateEntry // no entries if the key was absent, guarded by d.views.mu
+	err error                   // set if a key could not be kept
+}
+
+// View opens a view of the state as it is now, the state can be read through it in the
+// background while blocks are written. One view is open at a time, it must be released.
+func (d *Database) View() (*View, error) {
+	if _, ok := d.db.(KeysDatabase); !ok {
+		return nil, ErrKeysUnsupported
+	}
+	d.views.mu.Lock()
+	defer d.views.mu.Unlock()
+	if d.views.open != nil {
+		return nil, ErrViewOpen
+	}
+	v := &View{d: d, old: make(map[string][]StateEntry)}
+	d.views.open = v
+	return v, nil
+}
+
+// Release closes v, the keys written are no longer kept.
+func (v *View) Release() {
+	v.d.views.mu.Lock()
+	defer v.d.views.mu.Unlock()
+	if v.d.views.open == v {
+		v.d.views.open = nil
+	}
+}
+
+// preserve keeps key as it is for the view open, it is called before key is written.
+func (d *Database) preserve(key Key) {
+	if d.views == nil {
+		return
+	}
+	d.views.mu.Lock()
+	defer d.views.mu.Unlock()
+	v := d.views.open
+	if v == nil {
+		return
+	}
+	if _, ok := v.old[string(key)]; ok {
+		return
+	}
+	entries, err := d.entries([]byte(key))
+	if err != nil {
+		v.err = err
+		return
+	}
+	v.old[string(key)] = entries
+}
+
+// entries returns the entries key had when v was opened.
+func (v *View) entries(key []byte) ([]StateEntry, error) {
+	v.d.views.mu.Lock()
+	defer v.d.views.mu.Unlock()
+	if v.err != nil {
+		return nil, v.err
+	}
+	if old, ok := v.old[string(key)]; ok {
+		return old, nil
+	}
+	return v.d.entries(key)
+}
+
+// keys returns the keys of the state when v was opened, sorted.
+func (v *View) keys() ([][]byte, error) {
+	keys, err := v.d.keys()
+	if err != nil {
+		return nil, err
+	}
+	// the keys deleted meanwhile may not be listed, those created meanwhile have no entries
+	v.d.views.mu.Lock()
+	for k, old := range v.old {
+		if len(old) > 0 {
+			keys = append(keys, []byte(k))
+		}
+	}
+	v.d.views.mu.Unlock()
+	return sortKeys(keys), nil
+}
+
+// Snapshot takes a snapshot of the state seen by v, which must be the state after the block
+// blockHash of height. The entries are cut into chunks of SnapshotChunkSize at most, which are
+// passed to emit in order. The manifest holding their hashes is returned.
+func (v *View) Snapshot(height int64, blockHash []byte, emit func(chunk []byte) error) (*SnapshotManifest, error) {
+	keys, err := v.keys()
+	if err != nil {
+		return nil, err
+	}
+	m := &SnapshotManifest{Height: height, BlockHash: blockHash, Chunks: make([][]byte, 0)}
+	chunk := &StateChunk{}
+	flush := func() error {
+		if len(chunk.Entries) == 0 {
+			return nil
+		}
+		data, err := chunk.Marshal(nil)
+		if err != nil {
+			return err
+		}
+		if err := emit(data); err != nil {
+			return err
+		}
+		m.Chunks = append(m.Chunks, common.Sha256(data))
+		chunk = &StateChunk{}
+		return nil
+	}
+	for _, key := range keys {
+		entries, err := v.entries(key)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if len(chunk.Entries) > 0 && chunk.Size()+e.Size() > SnapshotChunkSize {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+			chunk.Entries = append(chunk.Entries, e)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Snapshot takes a snapshot of the state as it is now, see View.Snapshot.
+func (d *Database) Snapshot(height int64, blockHash []byte, emit func(chunk []byte) error) (*SnapshotManifest, error) {
+	v, err := d.View()
+	if err != nil {
+		return nil, err
+	}
+	defer v.Release()
+	return v.Snapshot(height, blockHash, emit)
+}
+
+// Root is the hash a snapshot is committed to by.
+func (m *SnapshotManifest) Root() []byte {
+	buf := make([]byte, 8, 8+len(m.BlockHash)+32*len(m.Chunks))
+	binary.BigEndian.PutUint64(buf, uint64(m.Height))
+	buf = append(buf, m.BlockHash...)
+	for _, h := range m.Chunks {
+		buf = append(buf, h...)
+	}
+	return common.Sha256(buf)
+}
+
+// VerifyChunk checks that data is the chunk i of the snapshot.
+func (m *SnapshotManifest) VerifyChunk(i int, data []byte) error {
+	if i < 0 || i >= len(m.Chunks) {
+		return ErrChunkIndex
+	}
+	if !bytes.Equal(common.Sha256(data), m.Chunks[i]) {
+		return ErrChunkHash
+	}
+	return nil
+}
+
+// DecodeManifest decodes a manifest received from a peer.
+func DecodeManifest(b []byte) (m *SnapshotManifest, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid snapshot manifest: %v", r)
+		}
+	}()
+	m = &SnapshotManifest{}
+	_, err = m.Unmarshal(b)
+	return m, err
+}
+
+// DecodeCommit decodes the snapshot commitment in a block head.
+func DecodeCommit(b []byte) (c *SnapshotCommit, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid snapshot commit: %v", r)
+		}
+	}()
+	c = &SnapshotCommit{}
+	_, err = c.Unmarshal(b)
+	return c, err
+}
+
+func decodeChunk(b []byte) (c *StateChunk, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid snapshot chunk: %v", r)
+		}
+	}()
+	c = &StateChunk{}
+	_, err = c.Unmarshal(b)
+	return c, err
+}
+
+// Restore replaces the state by the snapshot of m, chunks are checked against m before the
+// state is touched. The keys out of the namespace are left.
+func (d *Database) Restore(m *SnapshotManifest, chunks [][]byte) error {
+	if len(chunks) != len(m.Chunks) {
+		return ErrChunkIndex
+	}
+	decoded := make([]*StateChunk, 0, len(chunks))
+	for i, data := range chunks {
+		if err := m.VerifyChunk(i, data); err != nil {
+			return err
+		}
+		c, err := decodeChunk(data)
+		if err != nil {
+			return err
+		}
+		decoded = append(decoded, c)
+	}
+	d.views.mu.Lock()
+	open := d.views.open != nil
+	d.views.mu.Unlock()
+	if open {
+		return ErrViewOpen
+	}
+	keys, err := d.keys()
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := d.db.Delete(encode(Key(k))); err != nil {
+			return err
+		}
+	}
+	for _, c := range decoded {
+		for _, e := range c.Entries {
+			if e.Hash {
+				err = d.db.PutHM(encode(Key(e.Key)), e.Field, e.Val)
+			} else {
+				err = d.db.Put(encode(Key(e.Key)), e.Val)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
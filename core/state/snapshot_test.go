@@ -0,0 +1,207 @@
+package state
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/iost-official/Go-IOS-Protocol/db"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// hashDB is a database with hashes, like redis.
+type hashDB struct {
+	kv     map[string][]byte
+	hashes map[string]map[string]string
+}
+
+func newHashDB() *hashDB {
+	return &hashDB{kv: make(map[string][]byte), hashes: make(map[string]map[string]string)}
+}
+
+func (h *hashDB) Put(key []byte, value []byte) error {
+	delete(h.hashes, string(key))
+	h.kv[string(key)] = value
+	return nil
+}
+
+func (h *hashDB) PutHM(key []byte, args ...[]byte) error {
+	delete(h.kv, string(key))
+	m, ok := h.hashes[string(key)]
+	if !ok {
+		m = make(map[string]string)
+		h.hashes[string(key)] = m
+	}
+	for i := 0; i+1 < len(args); i += 2 {
+		m[string(args[i])] = string(args[i+1])
+	}
+	return nil
+}
+
+func (h *hashDB) Get(key []byte) ([]byte, error) {
+	if _, ok := h.hashes[string(key)]; ok {
+		return nil, errors.New("wrong type")
+	}
+	return h.kv[string(key)], nil
+}
+
+func (h *hashDB) GetHM(key []byte, args ...[]byte) ([][]byte, error) {
+	vals := make([][]byte, 0, len(args))
+	for _, f := range args {
+		v, ok := h.hashes[string(key)][string(f)]
+		if !ok {
+			vals = append(vals, nil)
+			continue
+		}
+		vals = append(vals, []byte(v))
+	}
+	return vals, nil
+}
+
+func (h *hashDB) Has(key []byte) (bool, error) {
+	_, ok := h.kv[string(key)]
+	_, okh := h.hashes[string(key)]
+	return ok || okh, nil
+}
+
+func (h *hashDB) Delete(key []byte) error {
+	delete(h.kv, string(key))
+	delete(h.hashes, string(key))
+	return nil
+}
+
+func (h *hashDB) Close() {}
+
+func (h *hashDB) Type(key string) (string, error) {
+	if _, ok := h.hashes[key]; ok {
+		return "hash", nil
+	}
+	return "string", nil
+}
+
+func (h *hashDB) GetAll(key string) (map[string]string, error) {
+	return h.hashes[key], nil
+}
+
+func (h *hashDB) Keys(prefix []byte) ([][]byte, error) {
+	keys := make([][]byte, 0)
+	for k := range h.kv {
+		if strings.HasPrefix(k, string(prefix)) {
+			keys = append(keys, []byte(k))
+		}
+	}
+	for k := range h.hashes {
+		if strings.HasPrefix(k, string(prefix)) {
+			keys = append(keys, []byte(k))
+		}
+	}
+	return keys, nil
+}
+
+// snapshot takes a snapshot of the state in d, with its chunks.
+func snapshot(d *Database, height int64, blockHash []byte) (*SnapshotManifest, [][]byte, error) {
+	chunks := make([][]byte, 0)
+	m, err := d.Snapshot(height, blockHash, func(chunk []byte) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	return m, chunks, err
+}
+
+func TestSnapshot(t *testing.T) {
+	Convey("Test of state snapshot", t, func() {
+		src := newHashDB()
+		sdb := NewDatabase(src)
+		sdb.Put(Key("BlockNum"), MakeVInt(100))
+		sdb.Put(Key("name"), MakeVString("iost"))
+		for _, acc := range []string{"a", "b", "c", "d"} {
+			sdb.PutHM(Key("iost"), Key(acc), MakeVFloat(10))
+		}
+
+		Convey("is the same for the same state", func() {
+			m1, chunks1, err := snapshot(&sdb, 100, []byte("hash"))
+			So(err, ShouldBeNil)
+			m2, _, _ := snapshot(&sdb, 100, []byte("hash"))
+			So(string(m1.Root()), ShouldEqual, string(m2.Root()))
+			So(len(chunks1), ShouldEqual, len(m1.Chunks))
+
+			sdb.PutHM(Key("iost"), Key("a"), MakeVFloat(9))
+			m3, _, _ := snapshot(&sdb, 100, []byte("hash"))
+			So(string(m3.Root()), ShouldNotEqual, string(m1.Root()))
+		})
+
+		Convey("is restored into another database", func() {
+			SnapshotChunkSize = 64
+			defer func() { SnapshotChunkSize = 128 << 10 }()
+			m, chunks, err := snapshot(&sdb, 100, []byte("hash"))
+			So(err, ShouldBeNil)
+			So(len(chunks), ShouldBeGreaterThan, 1)
+
+			dst := newHashDB()
+			dst.Put([]byte("other"), []byte("o1"))
+			ddb := NewDatabase(dst)
+			ddb.Put(Key("stale"), MakeVString("s1"))
+			So(ddb.Restore(m, chunks), ShouldBeNil)
+			has, _ := ddb.Has(Key("stale"))
+			So(has, ShouldBeFalse)
+			So(string(dst.kv["other"]), ShouldEqual, "o1")
+			v, _ := ddb.Get(Key("name"))
+			So(v.EncodeString(), ShouldEqual, MakeVString("iost").EncodeString())
+			v, _ = ddb.GetHM(Key("iost"), Key("c"))
+			So(v.EncodeString(), ShouldEqual, MakeVFloat(10).EncodeString())
+
+			m2, _, _ := snapshot(&ddb, 100, []byte("hash"))
+			So(string(m2.Root()), ShouldEqual, string(m.Root()))
+		})
+
+		Convey("is taken over the state when it was viewed", func() {
+			m1, _, _ := snapshot(&sdb, 100, []byte("hash"))
+			v, err := sdb.View()
+			So(err, ShouldBeNil)
+			_, err = sdb.View()
+			So(err, ShouldEqual, ErrViewOpen)
+
+			sdb.PutHM(Key("iost"), Key("a"), MakeVFloat(9))
+			sdb.Delete(Key("name"))
+			sdb.Put(Key("new"), MakeVInt(1))
+			chunks := make([][]byte, 0)
+			m2, err := v.Snapshot(100, []byte("hash"), func(chunk []byte) error {
+				chunks = append(chunks, chunk)
+				return nil
+			})
+			v.Release()
+			So(err, ShouldBeNil)
+			So(string(m2.Root()), ShouldEqual, string(m1.Root()))
+			m3, _, _ := snapshot(&sdb, 100, []byte("hash"))
+			So(string(m3.Root()), ShouldNotEqual, string(m1.Root()))
+
+			ddb := NewDatabase(newHashDB())
+			So(ddb.Restore(m2, chunks), ShouldBeNil)
+			v1, _ := ddb.Get(Key("name"))
+			So(v1.EncodeString(), ShouldEqual, MakeVString("iost").EncodeString())
+			v1, _ = ddb.GetHM(Key("iost"), Key("a"))
+			So(v1.EncodeString(), ShouldEqual, MakeVFloat(10).EncodeString())
+		})
+
+		Convey("refuses chunks not matching the manifest", func() {
+			m, chunks, _ := snapshot(&sdb, 100, []byte("hash"))
+			chunks[0] = append([]byte{}, chunks[0]...)
+			chunks[0][len(chunks[0])-1] ^= 1
+			So(m.VerifyChunk(0, chunks[0]), ShouldEqual, ErrChunkHash)
+			So(m.VerifyChunk(len(chunks), chunks[0]), ShouldEqual, ErrChunkIndex)
+
+			ddb := NewDatabase(newHashDB())
+			ddb.Put(Key("kept"), MakeVString("k"))
+			So(ddb.Restore(m, chunks), ShouldEqual, ErrChunkHash)
+			has, _ := ddb.Has(Key("kept"))
+			So(has, ShouldBeTrue)
+		})
+
+		Convey("needs keys to be listed", func() {
+			mem, _ := db.NewMemDatabase()
+			mdb := NewDatabase(mem)
+			_, _, err := snapshot(&mdb, 1, nil)
+			So(err, ShouldEqual, ErrKeysUnsupported)
+		})
+	})
+}
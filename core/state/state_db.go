@@ -4,8 +4,12 @@ import (
 	"github.com/iost-official/Go-IOS-Protocol/db"
 )
 
+// Namespace prefixes the keys of the state in the database, which may hold other keys.
+var Namespace = "state/"
+
 type Database struct {
-	db db.Database
+	db    db.Database
+	views *views
 }
 
 type HashDatabase interface {
@@ -16,33 +20,40 @@ type HashDatabase interface {
 
 func NewDatabase(db db.Database) Database {
 	return Database{
-		db: db,
+		db:    db,
+		views: &views{},
 	}
 }
 
+// encode returns the key of the database key is stored at.
+func encode(key Key) []byte {
+	return append([]byte(Namespace), key...)
+}
+
 func (d *Database) Put(key Key, value Value) error {
+	d.preserve(key)
 	switch value.Type() {
 	case Map:
 		vi, ok := value.(*VMap)
 		if !ok {
-			d.db.Put(key.Encode(), []byte(value.EncodeString()))
+			d.db.Put(encode(key), []byte(value.EncodeString()))
 		}
 		for k, v := range vi.m {
-			d.db.PutHM(key.Encode(), k.Encode(), []byte(v.EncodeString()))
+			d.db.PutHM(encode(key), k.Encode(), []byte(v.EncodeString()))
 		}
 	}
-	return d.db.Put(key.Encode(), []byte(value.EncodeString()))
+	return d.db.Put(encode(key), []byte(value.EncodeString()))
 }
 func (d *Database) Get(key Key) (Value, error) {
 	rdb, ok := d.db.(HashDatabase)
 	if ok {
-		t, err := rdb.Type(string(key))
+		t, err := rdb.Type(string(encode(key)))
 		if err != nil {
 			return nil, err
 		}
 		//fmt.Println(t)
 		if t == "hash" {
-			ms, err := rdb.GetAll(string(key))
+			ms, err := rdb.GetAll(string(encode(key)))
 			if err != nil {
 				return nil, err
 			}
@@ -57,7 +68,7 @@ func (d *Database) Get(key Key) (Value, error) {
 			return m, nil
 		}
 	}
-	raw, err := d.db.Get(key.Encode())
+	raw, err := d.db.Get(encode(key))
 	if err != nil {
 		return nil, err
 	}
@@ -67,13 +78,14 @@ func (d *Database) Get(key Key) (Value, error) {
 	return ParseValue(string(raw))
 }
 func (d *Database) Has(key Key) (bool, error) {
-	return d.db.Has(key.Encode())
+	return d.db.Has(encode(key))
 }
 func (d *Database) Delete(key Key) error {
-	return d.db.Delete(key.Encode())
+	d.preserve(key)
+	return d.db.Delete(encode(key))
 }
 func (d *Database) GetHM(key, field Key) (Value, error) {
-	raw, err := d.db.GetHM(key.Encode(), field.Encode())
+	raw, err := d.db.GetHM(encode(key), field.Encode())
 	if err != nil {
 		return nil, err
 	}
@@ -83,5 +95,6 @@ func (d *Database) GetHM(key, field Key) (Value, error) {
 	return ParseValue(string(raw[0]))
 }
 func (d *Database) PutHM(key, field Key, value Value) error {
-	return d.db.PutHM(key.Encode(), field.Encode(), []byte(value.EncodeString()))
+	d.preserve(key)
+	return d.db.PutHM(encode(key), field.Encode(), []byte(value.EncodeString()))
 }
@@ -207,6 +207,7 @@ func PoolInstance() error {
 	if StdPool == nil {
 		once.Do(func() {
 			StdPool = NewPool(mdb)
+			StdDatabase = &mdb
 		})
 	}
 
@@ -388,3 +388,689 @@ func (d *PatchRaw) Unmarshal(buf []byte) (uint64, error) {
 	}
 	return i + 0, nil
 }
+
+type StateEntry struct {
+	Key   []byte
+	Field []byte
+	Hash  bool
+	Val   []byte
+}
+
+func (d *StateEntry) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Key))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Field))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Val))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 1
+	return
+}
+func (d *StateEntry) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Key))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Key)
+		i += l
+	}
+	{
+		l := uint64(len(d.Field))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Field)
+		i += l
+	}
+	{
+		if d.Hash {
+			buf[i+0] = 1
+		} else {
+			buf[i+0] = 0
+		}
+	}
+	{
+		l := uint64(len(d.Val))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.Val)
+		i += l
+	}
+	return buf[:i+1], nil
+}
+
+func (d *StateEntry) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Key)) >= l {
+			d.Key = d.Key[:l]
+		} else {
+			d.Key = make([]byte, l)
+		}
+		copy(d.Key, buf[i+0:])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Field)) >= l {
+			d.Field = d.Field[:l]
+		} else {
+			d.Field = make([]byte, l)
+		}
+		copy(d.Field, buf[i+0:])
+		i += l
+	}
+	{
+		d.Hash = buf[i+0] == 1
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Val)) >= l {
+			d.Val = d.Val[:l]
+		} else {
+			d.Val = make([]byte, l)
+		}
+		copy(d.Val, buf[i+1:])
+		i += l
+	}
+	return i + 1, nil
+}
+
+type StateChunk struct {
+	Entries []StateEntry
+}
+
+func (d *StateChunk) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Entries))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Entries {
+
+			{
+				s += d.Entries[k0].Size()
+			}
+
+		}
+
+	}
+	return
+}
+func (d *StateChunk) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Entries))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Entries {
+
+			{
+				nbuf, err := d.Entries[k0].Marshal(buf[i+0:])
+				if err != nil {
+					return nil, err
+				}
+				i += uint64(len(nbuf))
+			}
+
+		}
+	}
+	return buf[:i+0], nil
+}
+
+func (d *StateChunk) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Entries)) >= l {
+			d.Entries = d.Entries[:l]
+		} else {
+			d.Entries = make([]StateEntry, l)
+		}
+		for k0 := range d.Entries {
+
+			{
+				ni, err := d.Entries[k0].Unmarshal(buf[i+0:])
+				if err != nil {
+					return 0, err
+				}
+				i += ni
+			}
+
+		}
+	}
+	return i + 0, nil
+}
+
+type SnapshotManifest struct {
+	Height    int64
+	BlockHash []byte
+	Chunks    [][]byte
+}
+
+func (d *SnapshotManifest) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.BlockHash))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Chunks))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Chunks {
+
+			{
+				l := uint64(len(d.Chunks[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	s += 8
+	return
+}
+func (d *SnapshotManifest) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+
+		buf[0+0] = byte(d.Height >> 0)
+
+		buf[1+0] = byte(d.Height >> 8)
+
+		buf[2+0] = byte(d.Height >> 16)
+
+		buf[3+0] = byte(d.Height >> 24)
+
+		buf[4+0] = byte(d.Height >> 32)
+
+		buf[5+0] = byte(d.Height >> 40)
+
+		buf[6+0] = byte(d.Height >> 48)
+
+		buf[7+0] = byte(d.Height >> 56)
+
+	}
+	{
+		l := uint64(len(d.BlockHash))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+8] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+8] = byte(t)
+			i++
+
+		}
+		copy(buf[i+8:], d.BlockHash)
+		i += l
+	}
+	{
+		l := uint64(len(d.Chunks))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+8] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+8] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Chunks {
+
+			{
+				l := uint64(len(d.Chunks[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+8] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+8] = byte(t)
+					i++
+
+				}
+				copy(buf[i+8:], d.Chunks[k0])
+				i += l
+			}
+
+		}
+	}
+	return buf[:i+8], nil
+}
+
+func (d *SnapshotManifest) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+
+		d.Height = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+8] & 0x7F)
+			for buf[i+8]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+8]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.BlockHash)) >= l {
+			d.BlockHash = d.BlockHash[:l]
+		} else {
+			d.BlockHash = make([]byte, l)
+		}
+		copy(d.BlockHash, buf[i+8:])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+8] & 0x7F)
+			for buf[i+8]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+8]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Chunks)) >= l {
+			d.Chunks = d.Chunks[:l]
+		} else {
+			d.Chunks = make([][]byte, l)
+		}
+		for k0 := range d.Chunks {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+8] & 0x7F)
+					for buf[i+8]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+8]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				if uint64(cap(d.Chunks[k0])) >= l {
+					d.Chunks[k0] = d.Chunks[k0][:l]
+				} else {
+					d.Chunks[k0] = make([]byte, l)
+				}
+				copy(d.Chunks[k0], buf[i+8:])
+				i += l
+			}
+
+		}
+	}
+	return i + 8, nil
+}
+
+type SnapshotCommit struct {
+	Height int64
+	Root   []byte
+}
+
+func (d *SnapshotCommit) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Root))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 8
+	return
+}
+func (d *SnapshotCommit) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+
+		buf[0+0] = byte(d.Height >> 0)
+
+		buf[1+0] = byte(d.Height >> 8)
+
+		buf[2+0] = byte(d.Height >> 16)
+
+		buf[3+0] = byte(d.Height >> 24)
+
+		buf[4+0] = byte(d.Height >> 32)
+
+		buf[5+0] = byte(d.Height >> 40)
+
+		buf[6+0] = byte(d.Height >> 48)
+
+		buf[7+0] = byte(d.Height >> 56)
+
+	}
+	{
+		l := uint64(len(d.Root))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+8] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+8] = byte(t)
+			i++
+
+		}
+		copy(buf[i+8:], d.Root)
+		i += l
+	}
+	return buf[:i+8], nil
+}
+
+func (d *SnapshotCommit) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+
+		d.Height = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+8] & 0x7F)
+			for buf[i+8]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+8]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Root)) >= l {
+			d.Root = d.Root[:l]
+		} else {
+			d.Root = make([]byte, l)
+		}
+		copy(d.Root, buf[i+8:])
+		i += l
+	}
+	return i + 8, nil
+}
@@ -0,0 +1,68 @@
+package tx
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+	"github.com/iost-official/Go-IOS-Protocol/vm/lua"
+)
+
+// keyIDRegexp matches ids derived from public keys, they are base58
+var keyIDRegexp = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]+$`)
+
+// NewKeyRotationTx makes a tx replacing permission level of named account name with perm.
+// It must be signed by enough owner keys of the account before publishing, the rotation
+// fails when the tx runs otherwise
+func NewKeyRotationTx(nonce int64, info vm.ContractInfo, name string, level vm.PermissionLevel, perm vm.Permission) (Tx, error) {
+	if !vm.IsAccountName(name) {
+		return Tx{}, vm.ErrInvalidAccountName
+	}
+	if err := perm.Validate(); err != nil {
+		return Tx{}, err
+	}
+	lv := "active"
+	if level == vm.Owner {
+		lv = "owner"
+	}
+	keys := make([]string, 0, len(perm.Keys))
+	for _, k := range perm.Keys {
+		if !keyIDRegexp.MatchString(string(k.ID)) {
+			return Tx{}, vm.ErrInvalidPermission
+		}
+		keys = append(keys, fmt.Sprintf(`["%v"]=%v`, k.ID, k.Weight))
+	}
+	sort.Strings(keys)
+	code := fmt.Sprintf(`function main()
+	return SetAccountKeys("%v", "%v", %v, {%v})
+end`, name, lv, perm.Threshold, strings.Join(keys, ", "))
+
+	main := lua.NewMethod(vm.Public, "main", 0, 1)
+	lc := lua.NewContract(info, code, main)
+	return NewTx(nonce, &lc), nil
+}
+
+// VerifyAccount verifies signatures of t like VerifySelf, then checks that the keys
+// which signed t satisfy level of named account name
+func (t *Tx) VerifyAccount(pool state.Pool, name string, level vm.PermissionLevel) error {
+	err := t.VerifySelf()
+	if err != nil {
+		return err
+	}
+	acc, err := vm.GetNamedAccount(pool, name)
+	if err != nil {
+		return err
+	}
+	signed := make(map[vm.IOSTAccount]bool)
+	signed[vm.PubkeyToIOSTAccount(t.Publisher.Pubkey)] = true
+	for _, sign := range t.Signs {
+		signed[vm.PubkeyToIOSTAccount(sign.Pubkey)] = true
+	}
+	if !acc.Satisfied(level, func(id vm.IOSTAccount) bool { return signed[id] }) {
+		return fmt.Errorf("permission of %v not satisfied", name)
+	}
+	return nil
+}
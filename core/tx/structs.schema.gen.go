@@ -13,6 +13,7 @@ var (
 )
 
 type TxBaseRaw struct {
+	ChainID  int64
 	Time     int64
 	Nonce    int64
 	Contract []byte
@@ -35,7 +36,7 @@ func (d *TxBaseRaw) Size() (s uint64) {
 		}
 		s += l
 	}
-	s += 16
+	s += 24
 	return
 }
 func (d *TxBaseRaw) Marshal(buf []byte) ([]byte, error) {
@@ -51,40 +52,59 @@ func (d *TxBaseRaw) Marshal(buf []byte) ([]byte, error) {
 
 	{
 
-		buf[0+0] = byte(d.Time >> 0)
+		buf[0+0] = byte(d.ChainID >> 0)
 
-		buf[1+0] = byte(d.Time >> 8)
+		buf[1+0] = byte(d.ChainID >> 8)
 
-		buf[2+0] = byte(d.Time >> 16)
+		buf[2+0] = byte(d.ChainID >> 16)
 
-		buf[3+0] = byte(d.Time >> 24)
+		buf[3+0] = byte(d.ChainID >> 24)
 
-		buf[4+0] = byte(d.Time >> 32)
+		buf[4+0] = byte(d.ChainID >> 32)
 
-		buf[5+0] = byte(d.Time >> 40)
+		buf[5+0] = byte(d.ChainID >> 40)
 
-		buf[6+0] = byte(d.Time >> 48)
+		buf[6+0] = byte(d.ChainID >> 48)
 
-		buf[7+0] = byte(d.Time >> 56)
+		buf[7+0] = byte(d.ChainID >> 56)
 
 	}
 	{
 
-		buf[0+8] = byte(d.Nonce >> 0)
+		buf[0+8] = byte(d.Time >> 0)
 
-		buf[1+8] = byte(d.Nonce >> 8)
+		buf[1+8] = byte(d.Time >> 8)
 
-		buf[2+8] = byte(d.Nonce >> 16)
+		buf[2+8] = byte(d.Time >> 16)
 
-		buf[3+8] = byte(d.Nonce >> 24)
+		buf[3+8] = byte(d.Time >> 24)
 
-		buf[4+8] = byte(d.Nonce >> 32)
+		buf[4+8] = byte(d.Time >> 32)
 
-		buf[5+8] = byte(d.Nonce >> 40)
+		buf[5+8] = byte(d.Time >> 40)
 
-		buf[6+8] = byte(d.Nonce >> 48)
+		buf[6+8] = byte(d.Time >> 48)
 
-		buf[7+8] = byte(d.Nonce >> 56)
+		buf[7+8] = byte(d.Time >> 56)
+
+	}
+	{
+
+		buf[0+16] = byte(d.Nonce >> 0)
+
+		buf[1+16] = byte(d.Nonce >> 8)
+
+		buf[2+16] = byte(d.Nonce >> 16)
+
+		buf[3+16] = byte(d.Nonce >> 24)
+
+		buf[4+16] = byte(d.Nonce >> 32)
+
+		buf[5+16] = byte(d.Nonce >> 40)
+
+		buf[6+16] = byte(d.Nonce >> 48)
+
+		buf[7+16] = byte(d.Nonce >> 56)
 
 	}
 	{
@@ -95,18 +115,18 @@ func (d *TxBaseRaw) Marshal(buf []byte) ([]byte, error) {
 			t := uint64(l)
 
 			for t >= 0x80 {
-				buf[i+16] = byte(t) | 0x80
+				buf[i+24] = byte(t) | 0x80
 				t >>= 7
 				i++
 			}
-			buf[i+16] = byte(t)
+			buf[i+24] = byte(t)
 			i++
 
 		}
-		copy(buf[i+16:], d.Contract)
+		copy(buf[i+24:], d.Contract)
 		i += l
 	}
-	return buf[:i+16], nil
+	return buf[:i+24], nil
 }
 
 func (d *TxBaseRaw) Unmarshal(buf []byte) (uint64, error) {
@@ -114,12 +134,17 @@ func (d *TxBaseRaw) Unmarshal(buf []byte) (uint64, error) {
 
 	{
 
-		d.Time = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+		d.ChainID = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
 
 	}
 	{
 
-		d.Nonce = 0 | (int64(buf[i+0+8]) << 0) | (int64(buf[i+1+8]) << 8) | (int64(buf[i+2+8]) << 16) | (int64(buf[i+3+8]) << 24) | (int64(buf[i+4+8]) << 32) | (int64(buf[i+5+8]) << 40) | (int64(buf[i+6+8]) << 48) | (int64(buf[i+7+8]) << 56)
+		d.Time = 0 | (int64(buf[i+0+8]) << 0) | (int64(buf[i+1+8]) << 8) | (int64(buf[i+2+8]) << 16) | (int64(buf[i+3+8]) << 24) | (int64(buf[i+4+8]) << 32) | (int64(buf[i+5+8]) << 40) | (int64(buf[i+6+8]) << 48) | (int64(buf[i+7+8]) << 56)
+
+	}
+	{
+
+		d.Nonce = 0 | (int64(buf[i+0+16]) << 0) | (int64(buf[i+1+16]) << 8) | (int64(buf[i+2+16]) << 16) | (int64(buf[i+3+16]) << 24) | (int64(buf[i+4+16]) << 32) | (int64(buf[i+5+16]) << 40) | (int64(buf[i+6+16]) << 48) | (int64(buf[i+7+16]) << 56)
 
 	}
 	{
@@ -128,10 +153,10 @@ func (d *TxBaseRaw) Unmarshal(buf []byte) (uint64, error) {
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+16] & 0x7F)
-			for buf[i+16]&0x80 == 0x80 {
+			t := uint64(buf[i+24] & 0x7F)
+			for buf[i+24]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+16]&0x7F) << bs
+				t |= uint64(buf[i+24]&0x7F) << bs
 				bs += 7
 			}
 			i++
@@ -144,13 +169,14 @@ func (d *TxBaseRaw) Unmarshal(buf []byte) (uint64, error) {
 		} else {
 			d.Contract = make([]byte, l)
 		}
-		copy(d.Contract, buf[i+16:])
+		copy(d.Contract, buf[i+24:])
 		i += l
 	}
-	return i + 16, nil
+	return i + 24, nil
 }
 
 type TxPublishRaw struct {
+	ChainID  int64
 	Time     int64
 	Nonce    int64
 	Contract []byte
@@ -209,7 +235,7 @@ func (d *TxPublishRaw) Size() (s uint64) {
 		}
 
 	}
-	s += 16
+	s += 24
 	return
 }
 func (d *TxPublishRaw) Marshal(buf []byte) ([]byte, error) {
@@ -225,40 +251,59 @@ func (d *TxPublishRaw) Marshal(buf []byte) ([]byte, error) {
 
 	{
 
-		buf[0+0] = byte(d.Time >> 0)
+		buf[0+0] = byte(d.ChainID >> 0)
 
-		buf[1+0] = byte(d.Time >> 8)
+		buf[1+0] = byte(d.ChainID >> 8)
 
-		buf[2+0] = byte(d.Time >> 16)
+		buf[2+0] = byte(d.ChainID >> 16)
 
-		buf[3+0] = byte(d.Time >> 24)
+		buf[3+0] = byte(d.ChainID >> 24)
 
-		buf[4+0] = byte(d.Time >> 32)
+		buf[4+0] = byte(d.ChainID >> 32)
 
-		buf[5+0] = byte(d.Time >> 40)
+		buf[5+0] = byte(d.ChainID >> 40)
 
-		buf[6+0] = byte(d.Time >> 48)
+		buf[6+0] = byte(d.ChainID >> 48)
 
-		buf[7+0] = byte(d.Time >> 56)
+		buf[7+0] = byte(d.ChainID >> 56)
 
 	}
 	{
 
-		buf[0+8] = byte(d.Nonce >> 0)
+		buf[0+8] = byte(d.Time >> 0)
 
-		buf[1+8] = byte(d.Nonce >> 8)
+		buf[1+8] = byte(d.Time >> 8)
 
-		buf[2+8] = byte(d.Nonce >> 16)
+		buf[2+8] = byte(d.Time >> 16)
 
-		buf[3+8] = byte(d.Nonce >> 24)
+		buf[3+8] = byte(d.Time >> 24)
 
-		buf[4+8] = byte(d.Nonce >> 32)
+		buf[4+8] = byte(d.Time >> 32)
 
-		buf[5+8] = byte(d.Nonce >> 40)
+		buf[5+8] = byte(d.Time >> 40)
 
-		buf[6+8] = byte(d.Nonce >> 48)
+		buf[6+8] = byte(d.Time >> 48)
 
-		buf[7+8] = byte(d.Nonce >> 56)
+		buf[7+8] = byte(d.Time >> 56)
+
+	}
+	{
+
+		buf[0+16] = byte(d.Nonce >> 0)
+
+		buf[1+16] = byte(d.Nonce >> 8)
+
+		buf[2+16] = byte(d.Nonce >> 16)
+
+		buf[3+16] = byte(d.Nonce >> 24)
+
+		buf[4+16] = byte(d.Nonce >> 32)
+
+		buf[5+16] = byte(d.Nonce >> 40)
+
+		buf[6+16] = byte(d.Nonce >> 48)
+
+		buf[7+16] = byte(d.Nonce >> 56)
 
 	}
 	{
@@ -269,15 +314,15 @@ func (d *TxPublishRaw) Marshal(buf []byte) ([]byte, error) {
 			t := uint64(l)
 
 			for t >= 0x80 {
-				buf[i+16] = byte(t) | 0x80
+				buf[i+24] = byte(t) | 0x80
 				t >>= 7
 				i++
 			}
-			buf[i+16] = byte(t)
+			buf[i+24] = byte(t)
 			i++
 
 		}
-		copy(buf[i+16:], d.Contract)
+		copy(buf[i+24:], d.Contract)
 		i += l
 	}
 	{
@@ -288,11 +333,11 @@ func (d *TxPublishRaw) Marshal(buf []byte) ([]byte, error) {
 			t := uint64(l)
 
 			for t >= 0x80 {
-				buf[i+16] = byte(t) | 0x80
+				buf[i+24] = byte(t) | 0x80
 				t >>= 7
 				i++
 			}
-			buf[i+16] = byte(t)
+			buf[i+24] = byte(t)
 			i++
 
 		}
@@ -306,21 +351,21 @@ func (d *TxPublishRaw) Marshal(buf []byte) ([]byte, error) {
 					t := uint64(l)
 
 					for t >= 0x80 {
-						buf[i+16] = byte(t) | 0x80
+						buf[i+24] = byte(t) | 0x80
 						t >>= 7
 						i++
 					}
-					buf[i+16] = byte(t)
+					buf[i+24] = byte(t)
 					i++
 
 				}
-				copy(buf[i+16:], d.Signs[k0])
+				copy(buf[i+24:], d.Signs[k0])
 				i += l
 			}
 
 		}
 	}
-	return buf[:i+16], nil
+	return buf[:i+24], nil
 }
 
 func (d *TxPublishRaw) Unmarshal(buf []byte) (uint64, error) {
@@ -328,12 +373,17 @@ func (d *TxPublishRaw) Unmarshal(buf []byte) (uint64, error) {
 
 	{
 
-		d.Time = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+		d.ChainID = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
 
 	}
 	{
 
-		d.Nonce = 0 | (int64(buf[i+0+8]) << 0) | (int64(buf[i+1+8]) << 8) | (int64(buf[i+2+8]) << 16) | (int64(buf[i+3+8]) << 24) | (int64(buf[i+4+8]) << 32) | (int64(buf[i+5+8]) << 40) | (int64(buf[i+6+8]) << 48) | (int64(buf[i+7+8]) << 56)
+		d.Time = 0 | (int64(buf[i+0+8]) << 0) | (int64(buf[i+1+8]) << 8) | (int64(buf[i+2+8]) << 16) | (int64(buf[i+3+8]) << 24) | (int64(buf[i+4+8]) << 32) | (int64(buf[i+5+8]) << 40) | (int64(buf[i+6+8]) << 48) | (int64(buf[i+7+8]) << 56)
+
+	}
+	{
+
+		d.Nonce = 0 | (int64(buf[i+0+16]) << 0) | (int64(buf[i+1+16]) << 8) | (int64(buf[i+2+16]) << 16) | (int64(buf[i+3+16]) << 24) | (int64(buf[i+4+16]) << 32) | (int64(buf[i+5+16]) << 40) | (int64(buf[i+6+16]) << 48) | (int64(buf[i+7+16]) << 56)
 
 	}
 	{
@@ -342,10 +392,10 @@ func (d *TxPublishRaw) Unmarshal(buf []byte) (uint64, error) {
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+16] & 0x7F)
-			for buf[i+16]&0x80 == 0x80 {
+			t := uint64(buf[i+24] & 0x7F)
+			for buf[i+24]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+16]&0x7F) << bs
+				t |= uint64(buf[i+24]&0x7F) << bs
 				bs += 7
 			}
 			i++
@@ -358,7 +408,7 @@ func (d *TxPublishRaw) Unmarshal(buf []byte) (uint64, error) {
 		} else {
 			d.Contract = make([]byte, l)
 		}
-		copy(d.Contract, buf[i+16:])
+		copy(d.Contract, buf[i+24:])
 		i += l
 	}
 	{
@@ -367,10 +417,10 @@ func (d *TxPublishRaw) Unmarshal(buf []byte) (uint64, error) {
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+16] & 0x7F)
-			for buf[i+16]&0x80 == 0x80 {
+			t := uint64(buf[i+24] & 0x7F)
+			for buf[i+24]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+16]&0x7F) << bs
+				t |= uint64(buf[i+24]&0x7F) << bs
 				bs += 7
 			}
 			i++
@@ -391,10 +441,10 @@ func (d *TxPublishRaw) Unmarshal(buf []byte) (uint64, error) {
 				{
 
 					bs := uint8(7)
-					t := uint64(buf[i+16] & 0x7F)
-					for buf[i+16]&0x80 == 0x80 {
+					t := uint64(buf[i+24] & 0x7F)
+					for buf[i+24]&0x80 == 0x80 {
 						i++
-						t |= uint64(buf[i+16]&0x7F) << bs
+						t |= uint64(buf[i+24]&0x7F) << bs
 						bs += 7
 					}
 					i++
@@ -407,13 +457,13 @@ func (d *TxPublishRaw) Unmarshal(buf []byte) (uint64, error) {
 				} else {
 					d.Signs[k0] = make([]byte, l)
 				}
-				copy(d.Signs[k0], buf[i+16:])
+				copy(d.Signs[k0], buf[i+24:])
 				i += l
 			}
 
 		}
 	}
-	return i + 16, nil
+	return i + 24, nil
 }
 
 type TxRaw struct {
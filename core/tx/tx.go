@@ -1,18 +1,26 @@
 package tx
 
 import (
+	"bytes"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/iost-official/Go-IOS-Protocol/account"
 	"github.com/iost-official/Go-IOS-Protocol/common"
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
 	"github.com/iost-official/Go-IOS-Protocol/vm"
 	"github.com/iost-official/Go-IOS-Protocol/vm/lua"
 )
 
 //go:generate gencode go -schema=structs.schema -package=tx
 
+// ChainID identifies the network, it is recorded in genesis under ChainIDKey and mixed into
+// every signed hash of txs, so a tx signed for one network is invalid on the others
+var ChainID int64 = 1024
+
+const ChainIDKey state.Key = "chain-id"
+
 type Tx struct {
 	Time      int64
 	Nonce     int64
@@ -31,7 +39,7 @@ func NewTx(nonce int64, contract vm.Contract) Tx {
 }
 
 func SignContract(tx Tx, account account.Account) (common.Signature, error) {
-	sign, err := common.Sign(common.Secp256k1, tx.BaseHash(), account.Seckey)
+	sign, err := common.Sign(account.Algorithm, tx.BaseHash(), account.Seckey)
 	if err != nil {
 		return sign, err
 	}
@@ -40,7 +48,7 @@ func SignContract(tx Tx, account account.Account) (common.Signature, error) {
 
 func SignTx(tx Tx, account account.Account, signs ...common.Signature) (Tx, error) {
 	tx.Signs = append(tx.Signs, signs...)
-	sign, err := common.Sign(common.Secp256k1, tx.publishHash(), account.Seckey)
+	sign, err := common.Sign(account.Algorithm, tx.publishHash(), account.Seckey)
 	if err != nil {
 		return tx, err
 	}
@@ -48,8 +56,10 @@ func SignTx(tx Tx, account account.Account, signs ...common.Signature) (Tx, erro
 	return tx, nil
 }
 
+// RecordTx signs the published tx with the key of the recording node, the recorder signature covers
+// the publisher signature so it can not be moved to another tx
 func RecordTx(tx Tx, account account.Account) (Tx, error) {
-	sign, err := common.Sign(common.Secp256k1, tx.BaseHash(), account.Seckey)
+	sign, err := common.Sign(account.Algorithm, tx.Hash(), account.Seckey)
 	if err != nil {
 		return tx, err
 	}
@@ -57,6 +67,23 @@ func RecordTx(tx Tx, account account.Account) (Tx, error) {
 	return tx, nil
 }
 
+// RecordedBy returns the recorder of t, false if t carries no valid recorder signature.
+// Keys which published or signed t are not recorders of it
+func (t *Tx) RecordedBy() (vm.IOSTAccount, bool) {
+	if len(t.Recorder.Pubkey) == 0 || bytes.Equal(t.Recorder.Pubkey, t.Publisher.Pubkey) {
+		return "", false
+	}
+	for _, sign := range t.Signs {
+		if bytes.Equal(t.Recorder.Pubkey, sign.Pubkey) {
+			return "", false
+		}
+	}
+	if !common.VerifySignature(t.Hash(), t.Recorder) {
+		return "", false
+	}
+	return vm.PubkeyToIOSTAccount(t.Recorder.Pubkey), true
+}
+
 func (t *Tx) String() string {
 	str := "Tx{\n"
 	str += "	Time: " + strconv.FormatInt(t.Time, 10) + ",\n"
@@ -68,7 +95,7 @@ func (t *Tx) String() string {
 }
 
 func (t *Tx) BaseHash() []byte {
-	tbr := TxBaseRaw{t.Time, t.Nonce, t.Contract.Encode()}
+	tbr := TxBaseRaw{ChainID, t.Time, t.Nonce, t.Contract.Encode()}
 	b, err := tbr.Marshal(nil)
 	if err != nil {
 		panic(err)
@@ -81,7 +108,7 @@ func (t *Tx) publishHash() []byte {
 	for _, sign := range t.Signs {
 		s = append(s, sign.Encode())
 	}
-	tpr := TxPublishRaw{t.Time, t.Nonce, t.Contract.Encode(), s}
+	tpr := TxPublishRaw{ChainID, t.Time, t.Nonce, t.Contract.Encode(), s}
 	b, err := tpr.Marshal(nil)
 	if err != nil {
 		panic(err)
@@ -94,7 +121,12 @@ func (t *Tx) Encode() []byte {
 	for _, sign := range t.Signs {
 		s = append(s, sign.Encode())
 	}
-	tr := TxRaw{t.Time, t.Nonce, t.Contract.Encode(), s, t.Publisher.Encode(), []byte{}}
+	// txs not recorded keep an empty recorder, so that they encode as before
+	recorder := []byte{}
+	if len(t.Recorder.Pubkey) > 0 {
+		recorder = t.Recorder.Encode()
+	}
+	tr := TxRaw{t.Time, t.Nonce, t.Contract.Encode(), s, t.Publisher.Encode(), recorder}
 	b, err := tr.Marshal(nil)
 	if err != nil {
 		panic(err)
@@ -123,6 +155,11 @@ func (t *Tx) Decode(b []byte) (err error) {
 		}
 		t.Signs = append(t.Signs, sign)
 	}
+	if len(tr.Recorder) > 0 {
+		if err = t.Recorder.Decode(tr.Recorder); err != nil {
+			return err
+		}
+	}
 	if t.Contract == nil {
 		switch tr.Contract[0] {
 		case 0:
@@ -148,12 +185,16 @@ func (t *Tx) Decode(b []byte) (err error) {
 	return nil
 }
 
+// Hash identifies t as published, the recorder is left out so that the prefix of the contract
+// and the keys of stored txs do not depend on who recorded t
 func (t *Tx) Hash() []byte {
-	return common.Sha256(t.Encode())
+	published := *t
+	published.Recorder = common.Signature{}
+	return common.Sha256(published.Encode())
 }
 
 func (t *Tx) TxID() string {
-	hash := string(t.Publisher.Pubkey) + strconv.FormatInt(t.Nonce, 10) + strconv.FormatInt(t.Time, 10)
+	hash := string(t.Publisher.Pubkey) + strconv.FormatInt(ChainID, 10) + "." + strconv.FormatInt(t.Nonce, 10) + "." + strconv.FormatInt(t.Time, 10)
 	return hash
 }
 
@@ -6,6 +6,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/iost-official/Go-IOS-Protocol/account"
 	"github.com/iost-official/Go-IOS-Protocol/common"
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/db"
 	"github.com/iost-official/Go-IOS-Protocol/vm"
 	"github.com/iost-official/Go-IOS-Protocol/vm/lua"
 
@@ -63,6 +65,70 @@ func TestTx(t *testing.T) {
 			So(err.Error(), ShouldEqual, "signer error")
 		})
 
+		Convey("signature bound to chain id", func() {
+			tx := NewTx(int64(1), mockContract)
+			sig1, _ := SignContract(tx, a1)
+			tx.Signs = append(tx.Signs, sig1)
+			tx3, err := SignTx(tx, a3)
+			So(err, ShouldBeNil)
+			So(tx3.VerifySelf(), ShouldBeNil)
+
+			origin := ChainID
+			ChainID = origin + 1
+			So(tx3.VerifySelf(), ShouldNotBeNil)
+			So(tx3.VerifySigner(sig1), ShouldBeFalse)
+			ChainID = origin
+			So(tx3.VerifySelf(), ShouldBeNil)
+		})
+
+		Convey("recorder survives encoding", func() {
+			tx := gentx()
+			stx, err := SignTx(tx, a1)
+			So(err, ShouldBeNil)
+			var plain Tx
+			So(plain.Decode(stx.Encode()), ShouldBeNil)
+			_, ok := plain.RecordedBy()
+			So(ok, ShouldBeFalse)
+
+			rtx, err := RecordTx(stx, a2)
+			So(err, ShouldBeNil)
+			var decoded Tx
+			So(decoded.Decode(rtx.Encode()), ShouldBeNil)
+			recorder, ok := decoded.RecordedBy()
+			So(ok, ShouldBeTrue)
+			So(recorder, ShouldEqual, vm.PubkeyToIOSTAccount(a2.Pubkey))
+			So(string(decoded.Hash()), ShouldEqual, string(rtx.Hash()))
+			So(string(decoded.Hash()), ShouldEqual, string(stx.Hash()))
+			So(decoded.Contract.Info().Prefix, ShouldEqual, plain.Contract.Info().Prefix)
+		})
+
+		Convey("publishers and signers do not record", func() {
+			tx := gentx()
+			sig, err := SignContract(tx, a2)
+			So(err, ShouldBeNil)
+			stx, err := SignTx(tx, a1, sig)
+			So(err, ShouldBeNil)
+
+			self, err := RecordTx(stx, a1)
+			So(err, ShouldBeNil)
+			_, ok := self.RecordedBy()
+			So(ok, ShouldBeFalse)
+
+			signer, err := RecordTx(stx, a2)
+			So(err, ShouldBeNil)
+			_, ok = signer.RecordedBy()
+			So(ok, ShouldBeFalse)
+
+			rtx, err := RecordTx(stx, a3)
+			So(err, ShouldBeNil)
+			moved := gentx()
+			moved, err = SignTx(moved, a1)
+			So(err, ShouldBeNil)
+			moved.Recorder = rtx.Recorder
+			_, ok = moved.RecordedBy()
+			So(ok, ShouldBeFalse)
+		})
+
 	})
 }
 
@@ -251,3 +317,40 @@ end--f`
 		So(err, ShouldBeNil)
 	})
 }
+
+func TestKeyRotationTx(t *testing.T) {
+	Convey("Test of key rotation tx", t, func() {
+		mdb, _ := db.DatabaseFactory("redis")
+		pool := state.NewPool(state.NewDatabase(mdb))
+
+		a1, _ := account.NewAccount(nil)
+		a2, _ := account.NewAccount(nil)
+		So(vm.PutNamedAccount(pool, vm.NewNamedAccount("alice", vm.IOSTAccount(a1.ID), vm.IOSTAccount(a2.ID))), ShouldBeNil)
+
+		perm := vm.Permission{Threshold: 1, Keys: []vm.KeyWeight{{ID: vm.IOSTAccount(a1.ID), Weight: 1}}}
+		tx, err := NewKeyRotationTx(1, vm.ContractInfo{GasLimit: 10000, Price: 1}, "alice", vm.Active, perm)
+		So(err, ShouldBeNil)
+		So(tx.Contract.Code(), ShouldContainSubstring, `SetAccountKeys("alice", "active", 1,`)
+
+		_, err = NewKeyRotationTx(1, vm.ContractInfo{GasLimit: 10000, Price: 1}, `alice", "owner`, vm.Active, perm)
+		So(err, ShouldEqual, vm.ErrInvalidAccountName)
+		bad := vm.Permission{Threshold: 1, Keys: []vm.KeyWeight{{ID: `x"]=1}) Transfer("a", "b", 1) --`, Weight: 1}}}
+		_, err = NewKeyRotationTx(1, vm.ContractInfo{GasLimit: 10000, Price: 1}, "alice", vm.Active, bad)
+		So(err, ShouldEqual, vm.ErrInvalidPermission)
+
+		stx, err := SignTx(tx, a2)
+		So(err, ShouldBeNil)
+		So(stx.VerifyAccount(pool, "alice", vm.Active), ShouldBeNil)
+		So(stx.VerifyAccount(pool, "alice", vm.Owner), ShouldNotBeNil)
+
+		sig, err := SignContract(tx, a1)
+		So(err, ShouldBeNil)
+		stx, err = SignTx(tx, a2, sig)
+		So(err, ShouldBeNil)
+		So(stx.VerifyAccount(pool, "alice", vm.Owner), ShouldBeNil)
+
+		var tx2 Tx
+		So(tx2.Decode(stx.Encode()), ShouldBeNil)
+		So(tx2.VerifyAccount(pool, "alice", vm.Owner), ShouldBeNil)
+	})
+}
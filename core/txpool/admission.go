@@ -0,0 +1,155 @@
+package txpool
+
+import (
+	"errors"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/tx"
+	"github.com/iost-official/Go-IOS-Protocol/verifier"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+	"github.com/iost-official/Go-IOS-Protocol/vm/lua"
+)
+
+var (
+	// MinGasLimit is the least gas limit the lua vm accepts to start a contract
+	MinGasLimit = vm.MinGasLimit
+	MinGasPrice = vm.MinGasPrice
+
+	ErrTxExpired    = errors.New("tx expired")
+	ErrTxSignature  = errors.New("tx signature invalid")
+	ErrTxContract   = errors.New("tx contract invalid")
+	ErrGasLimit     = errors.New("gas limit out of bounds")
+	ErrGasPrice     = errors.New("gas price too low")
+	ErrNonceTooLow  = errors.New("nonce too low")
+	ErrNonceTooHigh = errors.New("nonce too high")
+	ErrBalance      = errors.New("balance not enough to pay gas")
+	ErrSponsor      = errors.New("sponsor refused to pay gas")
+)
+
+// Codes reported in rpc PublishRet, 0 means the tx is accepted
+var rejectCodes = map[error]int32{
+	ErrTxSignature:        -2,
+	ErrTxContract:         -3,
+	ErrGasLimit:           -4,
+	ErrGasPrice:           -5,
+	ErrNonceTooLow:        -6,
+	ErrNonceTooHigh:       -7,
+	ErrBalance:            -8,
+	ErrTxExpired:          -9,
+	ErrTxKnown:            -10,
+	ErrReplaceUnderpriced: -11,
+	ErrSenderFull:         -12,
+	ErrPoolUnderpriced:    -13,
+	ErrSponsor:            -14,
+}
+
+// RejectCode maps an error of AddTx to the code of PublishRet, -1 for unknown errors
+func RejectCode(err error) int32 {
+	if err == nil {
+		return 0
+	}
+	if code, ok := rejectCodes[err]; ok {
+		return code
+	}
+	return -1
+}
+
+// RejectReason is the inverse of RejectCode
+func RejectReason(code int32) string {
+	if code == 0 {
+		return "accepted"
+	}
+	for err, c := range rejectCodes {
+		if c == code {
+			return err.Error()
+		}
+	}
+	return "tx rejected"
+}
+
+func txCost(t *tx.Tx) float64 {
+	return float64(t.Contract.Info().GasLimit)*t.Contract.Info().Price + verifier.TxBaseFee
+}
+
+// CheckTx tells whether t could be packed on top of the longest chain: signatures, contract
+// syntax, gas bounds, nonce and balance of the gas payer including txs the sender already queued
+func (pool *TxPoolServer) CheckTx(t *tx.Tx) error {
+	if t.Contract == nil {
+		return ErrTxContract
+	}
+	if err := t.VerifySelf(); err != nil {
+		return ErrTxSignature
+	}
+	if lc, ok := t.Contract.(*lua.Contract); ok {
+		if err := lc.CheckSyntax(); err != nil {
+			return ErrTxContract
+		}
+	}
+	if _, err := t.Contract.API("main"); err != nil {
+		return ErrTxContract
+	}
+
+	// a tx may use at most the gas limit of a whole block
+	sp := pool.chain.LongestPool()
+	maxGas, _ := verifier.BlockLimits(sp)
+	info := t.Contract.Info()
+	if info.GasLimit < MinGasLimit || uint64(info.GasLimit) > maxGas {
+		return ErrGasLimit
+	}
+	if info.Price < MinGasPrice {
+		return ErrGasPrice
+	}
+
+	if sp == nil {
+		return nil
+	}
+
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	nonce := verifier.NonceOf(info.Publisher, sp)
+	if t.Nonce <= nonce {
+		return ErrNonceTooLow
+	}
+	if t.Nonce > nonce+int64(AccountSlots) {
+		return ErrNonceTooHigh
+	}
+
+	cost := txCost(t)
+	if err := vm.CheckSponsor(sp, t.Contract); err != nil {
+		return ErrSponsor
+	}
+	payer, sponsored := verifier.GasPayer(sp, info)
+	for n, queued := range pool.memPool.senders[info.Publisher] {
+		p, s := verifier.GasPayer(sp, queued.Contract.Info())
+		if n < t.Nonce && p == payer && s == sponsored {
+			cost += txCost(queued)
+		}
+	}
+	if verifier.GasBalanceOf(info, sp) < cost {
+		return ErrBalance
+	}
+	return nil
+}
+
+// AddTx admits t into the pool synchronously, the error tells why t is rejected
+func (pool *TxPoolServer) AddTx(t *tx.Tx) error {
+	if pool.txTimeOut(t) {
+		rejectedTransactionCount.Inc()
+		return ErrTxExpired
+	}
+	return pool.admitTx(t)
+}
+
+// admitTx admits t like AddTx whatever its age
+func (pool *TxPoolServer) admitTx(t *tx.Tx) error {
+	if err := pool.CheckTx(t); err != nil {
+		rejectedTransactionCount.Inc()
+		return err
+	}
+	if err := pool.addListTx(t); err != nil {
+		rejectedTransactionCount.Inc()
+		return err
+	}
+	receivedTransactionCount.Inc()
+	return nil
+}
@@ -0,0 +1,205 @@
+package txpool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/message"
+	"github.com/iost-official/Go-IOS-Protocol/core/tx"
+	"github.com/iost-official/Go-IOS-Protocol/network"
+)
+
+var (
+	// FullPushThreshold is the number of neighbours below which txs are still broadcast in full,
+	// in small networks the extra round trip of announcing costs more than it saves
+	FullPushThreshold = 8
+	// MaxAnnounceHashes is the max number of hashes carried by one announcement or request
+	MaxAnnounceHashes = 256
+	// SeenExpiry is how long hashes of received txs are remembered
+	SeenExpiry = 2 * time.Minute
+	// RequestTimeout is how long a requested hash waits for its body before it may be requested again
+	RequestTimeout = 5 * time.Second
+
+	announceInterval = 200 * time.Millisecond
+)
+
+// hashCache remembers hashes until they expire
+type hashCache struct {
+	mu     sync.Mutex
+	hashes map[string]time.Time
+	expiry time.Duration
+}
+
+func newHashCache(expiry time.Duration) *hashCache {
+	return &hashCache{
+		hashes: make(map[string]time.Time),
+		expiry: expiry,
+	}
+}
+
+// Add remembers hash, returns false if hash is already remembered
+func (c *hashCache) Add(hash string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if t, ok := c.hashes[hash]; ok && now.Sub(t) < c.expiry {
+		return false
+	}
+	c.hashes[hash] = now
+	return true
+}
+
+func (c *hashCache) Has(hash string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.hashes[hash]
+	return ok && time.Since(t) < c.expiry
+}
+
+func (c *hashCache) Del(hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.hashes, hash)
+}
+
+func (c *hashCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.hashes)
+}
+
+// Expire forgets expired hashes
+func (c *hashCache) Expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for hash, t := range c.hashes {
+		if now.Sub(t) >= c.expiry {
+			delete(c.hashes, hash)
+		}
+	}
+}
+
+// Relay spreads t, which the pool has accepted, to the neighbours. t is broadcast in full when
+// the network is small, otherwise its hash is queued for the next announcement
+func (pool *TxPoolServer) Relay(t *tx.Tx) {
+	if pool.router.NeighbourNum() < FullPushThreshold {
+		pool.router.Broadcast(message.Message{
+			Body:    t.Encode(),
+			ReqType: int32(network.ReqPublishTx),
+		})
+		return
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.announceQueue = append(pool.announceQueue, t.TxID())
+}
+
+// announce broadcasts the queued hashes to the neighbours
+func (pool *TxPoolServer) announce() {
+	pool.mu.Lock()
+	queue := pool.announceQueue
+	pool.announceQueue = nil
+	pool.mu.Unlock()
+
+	for len(queue) > 0 {
+		n := len(queue)
+		if n > MaxAnnounceHashes {
+			n = MaxAnnounceHashes
+		}
+		hashes := message.TxHashes{Hashes: queue[:n]}
+		pool.router.Broadcast(message.Message{
+			Time:    time.Now().UnixNano(),
+			Body:    hashes.Encode(),
+			ReqType: int32(network.ReqTxHashes),
+		})
+		announcedTransactionCount.Add(float64(n))
+		queue = queue[n:]
+	}
+}
+
+// handleAnnounce requests from the announcer the txs neither known nor already requested
+func (pool *TxPoolServer) handleAnnounce(req message.Message) {
+	var hashes message.TxHashes
+	if err := hashes.Decode(req.Body); err != nil {
+		return
+	}
+
+	want := make([]string, 0)
+	for _, hash := range hashes.Hashes {
+		if len(want) >= MaxAnnounceHashes {
+			break
+		}
+		if pool.seenTx.Has(hash) || pool.ExistTransaction(hash) || !pool.requestedTx.Add(hash) {
+			continue
+		}
+		want = append(want, hash)
+	}
+	if len(want) == 0 {
+		return
+	}
+
+	body := message.TxHashes{Hashes: want}
+	pool.router.Send(message.Message{
+		Time:    time.Now().UnixNano(),
+		From:    req.To,
+		To:      req.From,
+		ReqType: int32(network.ReqTxByHash),
+		Body:    body.Encode(),
+	})
+	requestedTransactionCount.Add(float64(len(want)))
+}
+
+// handleTxRequest sends back the requested txs the pool has
+func (pool *TxPoolServer) handleTxRequest(req message.Message) {
+	var hashes message.TxHashes
+	if err := hashes.Decode(req.Body); err != nil {
+		return
+	}
+
+	for i, hash := range hashes.Hashes {
+		if i >= MaxAnnounceHashes {
+			break
+		}
+		t := pool.Transaction(hash)
+		if t == nil {
+			continue
+		}
+		pool.router.Send(message.Message{
+			Time:    time.Now().UnixNano(),
+			From:    req.To,
+			To:      req.From,
+			ReqType: int32(network.ReqPublishTx),
+			Body:    t.Encode(),
+		})
+	}
+}
+
+// receiveTx admits a tx from the network, carried by req. Txs are relayed once accepted: pushed
+// txs as the message they came in, txs the pool requested like the local ones
+func (pool *TxPoolServer) receiveTx(t *tx.Tx, req message.Message) {
+	hash := t.TxID()
+	requested := pool.requestedTx.Has(hash)
+	pool.requestedTx.Del(hash)
+	pool.seenTx.Add(hash)
+
+	if err := pool.AddTx(t); err != nil {
+		// txs are relayed only once verified, so the peer which sent an invalid one is not honest
+		if err == ErrTxSignature || err == ErrTxContract {
+			pool.router.PenalizeSender(req, network.PenaltyInvalidTx)
+		}
+		return
+	}
+	if requested {
+		pool.Relay(t)
+	} else {
+		pool.router.Relay(req)
+	}
+}
@@ -0,0 +1,90 @@
+package txpool
+
+import (
+	"sort"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/tx"
+	"github.com/iost-official/Go-IOS-Protocol/db"
+	"github.com/iost-official/Go-IOS-Protocol/log"
+)
+
+var journalPrefix = []byte("j") //journalPrefix+tx id -> tx data
+
+// txJournal persists txs admitted into the pool, so that they survive restarts of the node
+type txJournal struct {
+	db *db.LDBDatabase
+}
+
+func newTxJournal(path string) (*txJournal, error) {
+	ldb, err := db.NewLDBDatabase(path+"txJournal", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &txJournal{db: ldb}, nil
+}
+
+func (j *txJournal) insert(t *tx.Tx) error {
+	return j.db.Put(append(journalPrefix, []byte(t.TxID())...), t.Encode())
+}
+
+func (j *txJournal) remove(id string) error {
+	return j.db.Delete(append(journalPrefix, []byte(id)...))
+}
+
+func (j *txJournal) close() {
+	j.db.Close()
+}
+
+// load decodes every journaled tx, ordered by nonce so that queues of senders are rebuilt in order
+func (j *txJournal) load() []*tx.Tx {
+	txs := make([]*tx.Tx, 0)
+	iter := j.db.NewIterator()
+	defer iter.Release()
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) == 0 || key[0] != journalPrefix[0] {
+			continue
+		}
+		var t tx.Tx
+		if err := t.Decode(append([]byte{}, iter.Value()...)); err != nil {
+			if err := j.db.Delete(append([]byte{}, key...)); err != nil {
+				log.Log.E("TxPoolServer journal remove undecodable tx failed: %v", err)
+			}
+			continue
+		}
+		txs = append(txs, &t)
+	}
+	sort.Slice(txs, func(a, b int) bool {
+		if txs[a].Nonce == txs[b].Nonce {
+			return txs[a].Time < txs[b].Time
+		}
+		return txs[a].Nonce < txs[b].Nonce
+	})
+	return txs
+}
+
+// loadJournal readmits journaled txs through the admission checks, those no longer valid
+// against the longest chain are dropped from the journal. Txs were checked for expiry when first
+// admitted, so they are readmitted whatever their age and expire counting from now
+func (pool *TxPoolServer) loadJournal() {
+	if pool.journal == nil {
+		return
+	}
+	var loaded, dropped int
+	now := time.Now().Unix()
+	for _, t := range pool.journal.load() {
+		if err := pool.admitTx(t); err != nil {
+			if err := pool.journal.remove(t.TxID()); err != nil {
+				log.Log.E("TxPoolServer journal remove %v failed: %v", t.TxID(), err)
+			}
+			dropped++
+			continue
+		}
+		pool.mu.Lock()
+		pool.memPool.reloaded[t.TxID()] = now
+		pool.mu.Unlock()
+		loaded++
+	}
+	log.Log.I("TxPoolServer journal loaded: %v, dropped: %v", loaded, dropped)
+}
@@ -0,0 +1,260 @@
+package txpool
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/tx"
+	"github.com/iost-official/Go-IOS-Protocol/log"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+)
+
+var (
+	// GlobalSlots is the max number of txs kept in the pool
+	GlobalSlots = 10000
+	// AccountSlots is the max number of txs kept for one sender
+	AccountSlots = 64
+	// PriceBump is the percentage a tx must raise the price by to replace another with the same nonce
+	PriceBump = 10.0
+
+	ErrTxKnown            = errors.New("tx already known")
+	ErrReplaceUnderpriced = errors.New("replacement tx underpriced")
+	ErrSenderFull         = errors.New("sender queue full")
+	ErrPoolUnderpriced    = errors.New("pool full and tx underpriced")
+)
+
+// memPool keeps txs queued by sender and nonce. Txs whose nonces follow the state nonce of
+// their sender without gaps are pending and can be packed, the others are future
+type memPool struct {
+	all     listTx
+	senders map[vm.IOSTAccount]map[int64]*tx.Tx
+	journal *txJournal
+	// reloaded keeps the time txs were readmitted from the journal, they age from then
+	reloaded map[string]int64
+
+	evicted  int64
+	replaced int64
+}
+
+func newMemPool() *memPool {
+	return &memPool{
+		all:      listTx{list: make(map[string]*tx.Tx)},
+		senders:  make(map[vm.IOSTAccount]map[int64]*tx.Tx),
+		reloaded: make(map[string]int64),
+	}
+}
+
+func txSender(t *tx.Tx) vm.IOSTAccount {
+	return t.Contract.Info().Publisher
+}
+
+func txPrice(t *tx.Tx) float64 {
+	return t.Contract.Info().Price
+}
+
+func (m *memPool) Len() int {
+	return m.all.Len()
+}
+
+func (m *memPool) Exist(id string) bool {
+	return m.all.Exist(id)
+}
+
+func (m *memPool) Get(id string) *tx.Tx {
+	return m.all.Get(id)
+}
+
+func (m *memPool) GetList() map[string]*tx.Tx {
+	return m.all.GetList()
+}
+
+// Add puts t into the pool. A tx with the same sender and nonce is replaced if t pays at
+// least PriceBump percent more. When the pool is full the cheapest tx is evicted for t
+func (m *memPool) Add(t *tx.Tx) error {
+	if m.all.Exist(t.TxID()) {
+		return ErrTxKnown
+	}
+	sender := txSender(t)
+	queue := m.senders[sender]
+	if old, ok := queue[t.Nonce]; ok {
+		if txPrice(t) < txPrice(old)*(1+PriceBump/100) || txPrice(t) <= txPrice(old) {
+			return ErrReplaceUnderpriced
+		}
+		m.Del(old.TxID())
+		m.replaced++
+		replacedTransactionCount.Inc()
+		m.put(t)
+		return nil
+	}
+	if len(queue) >= AccountSlots {
+		return ErrSenderFull
+	}
+	if m.all.Len() >= GlobalSlots {
+		cheapest := m.cheapestTail(txSender(t))
+		if cheapest == nil || txPrice(cheapest) >= txPrice(t) {
+			return ErrPoolUnderpriced
+		}
+		m.Del(cheapest.TxID())
+		m.evicted++
+		evictedTransactionCount.Inc()
+	}
+	m.put(t)
+	return nil
+}
+
+func (m *memPool) put(t *tx.Tx) {
+	m.all.Add(t)
+	if m.journal != nil {
+		if err := m.journal.insert(t); err != nil {
+			log.Log.E("TxPoolServer journal insert %v failed: %v", t.TxID(), err)
+		}
+	}
+	sender := txSender(t)
+	if _, ok := m.senders[sender]; !ok {
+		m.senders[sender] = make(map[int64]*tx.Tx)
+	}
+	m.senders[sender][t.Nonce] = m.all.Get(t.TxID())
+}
+
+func (m *memPool) Del(id string) {
+	t := m.all.Get(id)
+	if t == nil {
+		return
+	}
+	m.all.Del(id)
+	delete(m.reloaded, id)
+	if m.journal != nil {
+		if err := m.journal.remove(id); err != nil {
+			log.Log.E("TxPoolServer journal remove %v failed: %v", id, err)
+		}
+	}
+	sender := txSender(t)
+	queue := m.senders[sender]
+	delete(queue, t.Nonce)
+	if len(queue) == 0 {
+		delete(m.senders, sender)
+	}
+}
+
+// agedFrom returns the time in seconds t expires from, the time it was readmitted from the journal
+// if later than its own
+func (m *memPool) agedFrom(t *tx.Tx) int64 {
+	txTime := t.Time / 1e9
+	if reloaded, ok := m.reloaded[t.TxID()]; ok && reloaded > txTime {
+		return reloaded
+	}
+	return txTime
+}
+
+// cheapestTail finds the cheapest tx among the last txs of every sender but except, so that
+// evicting it never leaves a nonce gap, not even in the queue of the tx being added
+func (m *memPool) cheapestTail(except vm.IOSTAccount) *tx.Tx {
+	var cheapest *tx.Tx
+	for sender, queue := range m.senders {
+		if sender == except {
+			continue
+		}
+		var tail *tx.Tx
+		for _, t := range queue {
+			if tail == nil || t.Nonce > tail.Nonce {
+				tail = t
+			}
+		}
+		if cheapest == nil || txPrice(tail) < txPrice(cheapest) {
+			cheapest = tail
+		}
+	}
+	return cheapest
+}
+
+// DropStale removes txs whose nonce has already been used according to nonceOf
+func (m *memPool) DropStale(nonceOf func(sender vm.IOSTAccount) int64) {
+	for sender, queue := range m.senders {
+		nonce := nonceOf(sender)
+		for n, t := range queue {
+			if n <= nonce {
+				m.Del(t.TxID())
+			}
+		}
+	}
+}
+
+// Pending returns executable txs of every sender in nonce order, together with the number of
+// future txs that wait for a missing nonce. skip reports txs that must not be packed
+func (m *memPool) Pending(nonceOf func(sender vm.IOSTAccount) int64, skip func(t *tx.Tx) bool) (map[vm.IOSTAccount][]*tx.Tx, int) {
+	pending := make(map[vm.IOSTAccount][]*tx.Tx)
+	future := 0
+	for sender, queue := range m.senders {
+		nonces := make([]int64, 0, len(queue))
+		for n := range queue {
+			nonces = append(nonces, n)
+		}
+		sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+
+		next := nonceOf(sender) + 1
+		for i, n := range nonces {
+			if n < next {
+				continue
+			}
+			if n != next {
+				future += len(nonces) - i
+				break
+			}
+			if !skip(queue[n]) {
+				pending[sender] = append(pending[sender], queue[n])
+			}
+			next++
+		}
+	}
+	return pending, future
+}
+
+// byPrice picks txs across senders by price, while txs of one sender keep their nonce order
+type byPrice [][]*tx.Tx
+
+func (b byPrice) Len() int { return len(b) }
+func (b byPrice) Less(i, j int) bool {
+	pi, pj := txPrice(b[i][0]), txPrice(b[j][0])
+	if pi == pj {
+		return b[i][0].Time < b[j][0].Time
+	}
+	return pi > pj
+}
+func (b byPrice) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+
+func (b *byPrice) Push(x interface{}) {
+	*b = append(*b, x.([]*tx.Tx))
+}
+
+func (b *byPrice) Pop() interface{} {
+	old := *b
+	n := len(old)
+	x := old[n-1]
+	*b = old[0 : n-1]
+	return x
+}
+
+// orderByPrice merges pending queues into at most maxCnt txs, highest price first
+func orderByPrice(pending map[vm.IOSTAccount][]*tx.Tx, maxCnt int) tx.TransactionsList {
+	h := make(byPrice, 0, len(pending))
+	for _, queue := range pending {
+		if len(queue) > 0 {
+			h = append(h, queue)
+		}
+	}
+	heap.Init(&h)
+
+	list := make(tx.TransactionsList, 0)
+	for h.Len() > 0 && len(list) < maxCnt {
+		queue := h[0]
+		list = append(list, queue[0])
+		if len(queue) > 1 {
+			h[0] = queue[1:]
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+	return list
+}
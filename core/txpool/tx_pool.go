@@ -2,7 +2,6 @@ package txpool
 
 import (
 	"fmt"
-	"sort"
 	"sync"
 	"time"
 
@@ -13,6 +12,8 @@ import (
 	"github.com/iost-official/Go-IOS-Protocol/core/tx"
 	"github.com/iost-official/Go-IOS-Protocol/log"
 	"github.com/iost-official/Go-IOS-Protocol/network"
+	"github.com/iost-official/Go-IOS-Protocol/verifier"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -27,10 +28,76 @@ var (
 			Help: "Count of received transaction by current node",
 		},
 	)
+	rejectedTransactionCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rejected_transaction_count",
+			Help: "Count of transaction rejected by the tx pool",
+		},
+	)
+	replacedTransactionCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "replaced_transaction_count",
+			Help: "Count of transaction replaced by a higher priced one",
+		},
+	)
+	evictedTransactionCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "evicted_transaction_count",
+			Help: "Count of transaction evicted from the full tx pool",
+		},
+	)
+	pendingTransactionGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pending_transaction_num",
+			Help: "Number of executable transaction in the tx pool",
+		},
+	)
+	futureTransactionGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "future_transaction_num",
+			Help: "Number of transaction waiting for a missing nonce in the tx pool",
+		},
+	)
+	txPoolSenderGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "tx_pool_sender_num",
+			Help: "Number of senders having transaction in the tx pool",
+		},
+	)
+	announcedTransactionCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "announced_transaction_count",
+			Help: "Count of transaction hash announced by current node",
+		},
+	)
+	requestedTransactionCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "requested_transaction_count",
+			Help: "Count of announced transaction requested by current node",
+		},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(receivedTransactionCount)
+	prometheus.MustRegister(rejectedTransactionCount)
+	prometheus.MustRegister(replacedTransactionCount)
+	prometheus.MustRegister(evictedTransactionCount)
+	prometheus.MustRegister(pendingTransactionGauge)
+	prometheus.MustRegister(futureTransactionGauge)
+	prometheus.MustRegister(txPoolSenderGauge)
+	prometheus.MustRegister(announcedTransactionCount)
+	prometheus.MustRegister(requestedTransactionCount)
+}
+
+// PoolStats is a snapshot of the tx pool
+type PoolStats struct {
+	Total    int
+	Pending  int
+	Future   int
+	Senders  int
+	Evicted  int64
+	Replaced int64
 }
 
 type TxPoolServer struct {
@@ -41,8 +108,13 @@ type TxPoolServer struct {
 	router network.Router
 
 	blockTx   blockTx
-	listTx    listTx
-	pendingTx listTx
+	memPool   *memPool
+	journal   *txJournal
+	pendingTx tx.TransactionsList
+
+	seenTx        *hashCache
+	requestedTx   *hashCache
+	announceQueue []string
 
 	checkIterateBlockHash blockHashList
 
@@ -61,8 +133,10 @@ func NewTxPoolServer(chain blockcache.BlockCache, chConfirmBlock chan *block.Blo
 			blkTx:   make(map[string]*hashMap),
 			blkTime: make(map[string]int64),
 		},
-		listTx:                listTx{list: make(map[string]*tx.Tx)},
-		pendingTx:             listTx{list: make(map[string]*tx.Tx)},
+		memPool:               newMemPool(),
+		pendingTx:             make(tx.TransactionsList, 0),
+		seenTx:                newHashCache(SeenExpiry),
+		requestedTx:           newHashCache(RequestTimeout),
 		checkIterateBlockHash: blockHashList{blockList: make(map[string]struct{}, 0)},
 		filterTime:            int64(filterTime),
 	}
@@ -75,11 +149,20 @@ func NewTxPoolServer(chain blockcache.BlockCache, chConfirmBlock chan *block.Blo
 	p.chTx, err = p.router.FilteredChan(network.Filter{
 		AcceptType: []network.ReqType{
 			network.ReqPublishTx,
+			network.ReqTxHashes,
+			network.ReqTxByHash,
 		}})
 	if err != nil {
 		return nil, err
 	}
 
+	p.journal, err = newTxJournal(tx.LdbPath)
+	if err != nil {
+		return nil, err
+	}
+	p.memPool.journal = p.journal
+	p.loadJournal()
+
 	TxPoolS = p
 	return p, nil
 }
@@ -93,6 +176,13 @@ func (pool *TxPoolServer) Stop() {
 	log.Log.I("TxPoolServer Stop")
 	close(pool.chTx)
 	close(pool.chConfirmBlock)
+
+	pool.mu.Lock()
+	pool.memPool.journal = nil
+	pool.mu.Unlock()
+	if pool.journal != nil {
+		pool.journal.close()
+	}
 }
 
 func (pool *TxPoolServer) loop() {
@@ -101,6 +191,8 @@ func (pool *TxPoolServer) loop() {
 
 	clearTx := time.NewTicker(clearInterval)
 	defer clearTx.Stop()
+	announceTx := time.NewTicker(announceInterval)
+	defer announceTx.Stop()
 
 	for {
 		select {
@@ -109,20 +201,23 @@ func (pool *TxPoolServer) loop() {
 				return
 			}
 
-			var tx tx.Tx
-			err := tx.Decode(tr.Body)
-			if err != nil {
+			switch network.ReqType(tr.ReqType) {
+			case network.ReqTxHashes:
+				pool.handleAnnounce(tr)
+				continue
+			case network.ReqTxByHash:
+				pool.handleTxRequest(tr)
 				continue
 			}
 
-			if pool.txTimeOut(&tx) {
+			var tx tx.Tx
+			err := tx.Decode(tr.Body)
+			if err != nil {
+				pool.router.PenalizeSender(tr, network.PenaltyBadMsg)
 				continue
 			}
 
-			if blockcache.VerifyTxSig(tx) {
-				pool.addListTx(&tx)
-				receivedTransactionCount.Inc()
-			}
+			pool.receiveTx(&tx, tr)
 
 		case bl, ok := <-pool.chConfirmBlock:
 			if !ok {
@@ -131,9 +226,14 @@ func (pool *TxPoolServer) loop() {
 			pool.addBlockTx(bl)
 			bhl := pool.blockHash(pool.chain.LongestChain())
 			pool.updateBlockHash(bhl)
+			pool.dropStale()
 		case <-clearTx.C:
 			pool.delTimeOutTx()
 			pool.delTimeOutBlockTx()
+			pool.seenTx.Expire()
+			pool.requestedTx.Expire()
+		case <-announceTx.C:
+			pool.announce()
 		}
 	}
 }
@@ -149,13 +249,8 @@ func (pool *TxPoolServer) PendingTransactions(maxCnt int) tx.TransactionsList {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
 
-	var pendingList tx.TransactionsList
-	list := pool.pendingTx.GetList()
-
-	for _, tx := range list {
-		pendingList = append(pendingList, tx)
-	}
-	sort.Sort(pendingList)
+	pendingList := make(tx.TransactionsList, len(pool.pendingTx))
+	copy(pendingList, pool.pendingTx)
 
 	return pendingList
 }
@@ -164,21 +259,33 @@ func (pool *TxPoolServer) Transaction(hash string) *tx.Tx {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
 
-	return pool.listTx.Get(hash)
+	return pool.memPool.Get(hash)
+}
+
+// TransactionsByShortID indexes txs of the pool by block.ShortTxID, compact blocks are rebuilt from it
+func (pool *TxPoolServer) TransactionsByShortID() map[string]*tx.Tx {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	index := make(map[string]*tx.Tx, pool.memPool.Len())
+	for _, t := range pool.memPool.GetList() {
+		index[block.ShortTxID(t)] = t
+	}
+	return index
 }
 
 func (pool *TxPoolServer) ExistTransaction(hash string) bool {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
 
-	return pool.listTx.Exist(hash)
+	return pool.memPool.Exist(hash)
 }
 
 func (pool *TxPoolServer) TransactionNum() int {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
 
-	return pool.listTx.Len()
+	return pool.memPool.Len()
 }
 
 func (pool *TxPoolServer) PendingTransactionNum() int {
@@ -188,6 +295,33 @@ func (pool *TxPoolServer) PendingTransactionNum() int {
 	return pool.pendingTx.Len()
 }
 
+// Stats counts txs in the pool against the state of the longest chain
+func (pool *TxPoolServer) Stats() PoolStats {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	pending, future := pool.memPool.Pending(pool.nonceOf(), func(t *tx.Tx) bool { return false })
+	stats := PoolStats{
+		Total:    pool.memPool.Len(),
+		Future:   future,
+		Senders:  len(pool.memPool.senders),
+		Evicted:  pool.memPool.evicted,
+		Replaced: pool.memPool.replaced,
+	}
+	for _, queue := range pending {
+		stats.Pending += len(queue)
+	}
+	return stats
+}
+
+// SenderTxNum returns the number of txs of sender in the pool
+func (pool *TxPoolServer) SenderTxNum(sender vm.IOSTAccount) int {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return len(pool.memPool.senders[sender])
+}
+
 func (pool *TxPoolServer) BlockTxNum() int {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
@@ -219,14 +353,30 @@ func (pool *TxPoolServer) slotToSec(t int64) int64 {
 	return slot.ToUnixSec()
 }
 
-func (pool *TxPoolServer) addListTx(tx *tx.Tx) {
+func (pool *TxPoolServer) addListTx(tx *tx.Tx) error {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
-	if !pool.listTx.Exist(tx.TxID()) {
-		pool.listTx.Add(tx)
+	return pool.memPool.Add(tx)
+}
+
+// nonceOf reads nonces of senders from the state of the longest chain
+func (pool *TxPoolServer) nonceOf() func(sender vm.IOSTAccount) int64 {
+	sp := pool.chain.LongestPool()
+	return func(sender vm.IOSTAccount) int64 {
+		if sp == nil {
+			return 0
+		}
+		return verifier.NonceOf(sender, sp)
 	}
+}
+
+// dropStale removes txs already included in the longest chain
+func (pool *TxPoolServer) dropStale() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
 
+	pool.memPool.DropStale(pool.nonceOf())
 }
 
 func (pool *TxPoolServer) txTimeOut(tx *tx.Tx) bool {
@@ -247,15 +397,14 @@ func (pool *TxPoolServer) delTimeOutTx() {
 	nTime := time.Now().Unix()
 	hashList := make([]string, 0)
 
-	list := pool.listTx.GetList()
+	list := pool.memPool.GetList()
 	for hash, tx := range list {
-		txTime := tx.Time / 1e9
-		if nTime-txTime > pool.filterTime {
+		if nTime-pool.memPool.agedFrom(tx) > pool.filterTime {
 			hashList = append(hashList, hash)
 		}
 	}
 	for _, hash := range hashList {
-		pool.listTx.Del(hash)
+		pool.memPool.Del(hash)
 	}
 
 }
@@ -293,17 +442,16 @@ func (pool *TxPoolServer) updatePending(maxCnt int) {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
-	pool.pendingTx.list = make(map[string]*tx.Tx, 0)
+	nonceOf := pool.nonceOf()
+	pool.memPool.DropStale(nonceOf)
+	pending, future := pool.memPool.Pending(nonceOf, func(t *tx.Tx) bool {
+		return pool.txExistTxPool(t.TxID())
+	})
+	pool.pendingTx = orderByPrice(pending, maxCnt)
 
-	list := pool.listTx.GetList()
-	for hash, tr := range list {
-		if !pool.txExistTxPool(hash) {
-			pool.pendingTx.Add(tr)
-			if pool.pendingTx.Len() >= maxCnt {
-				break
-			}
-		}
-	}
+	pendingTransactionGauge.Set(float64(pool.pendingTx.Len()))
+	futureTransactionGauge.Set(float64(future))
+	txPoolSenderGauge.Set(float64(len(pool.memPool.senders)))
 }
 
 func (pool *TxPoolServer) txExistTxPool(hash string) bool {
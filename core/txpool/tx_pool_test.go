@@ -1,7 +1,11 @@
 package txpool
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
+	"time"
 
 	. "github.com/golang/mock/gomock"
 	"github.com/iost-official/Go-IOS-Protocol/account"
@@ -51,6 +55,9 @@ func TestNewTxPoolServer(t *testing.T) {
 		network.Route = mockRouter
 		txChan := make(chan message.Message, 100000)
 		mockRouter.EXPECT().FilteredChan(Any()).Return(txChan, nil)
+		mockRouter.EXPECT().Penalize(Any(), Any()).AnyTimes()
+		mockRouter.EXPECT().PenalizeSender(Any(), Any()).AnyTimes()
+		mockRouter.EXPECT().Relay(Any()).AnyTimes()
 
 		txDb := tx.TxDbInstance()
 		if txDb == nil {
@@ -223,7 +230,7 @@ func TestNewTxPoolServer(t *testing.T) {
 
 			listTxCnt := 2
 			for i := 0; i < listTxCnt; i++ {
-				tx := genTx(accountList[0], 30+i)
+				tx := genTx(accountList[1], 1+i)
 				txPool.addListTx(&tx)
 			}
 
@@ -297,6 +304,9 @@ func envInit(b *testing.B) (blockcache.BlockCache, []account.Account, []string,
 	network.Route = mockRouter
 	txChan := make(chan message.Message, 1)
 	mockRouter.EXPECT().FilteredChan(Any()).Return(txChan, nil)
+	mockRouter.EXPECT().Penalize(Any(), Any()).AnyTimes()
+	mockRouter.EXPECT().PenalizeSender(Any(), Any()).AnyTimes()
+	mockRouter.EXPECT().Relay(Any()).AnyTimes()
 
 	txDb := tx.TxDbInstance()
 	if txDb == nil {
@@ -366,3 +376,159 @@ func genBlocks(p blockcache.BlockCache, accountList []account.Account, witnessLi
 	}
 	return
 }
+
+func genPricedTx(sender string, nonce int, price float64) *tx.Tx {
+	main := lua.NewMethod(2, "main", 0, 1)
+	code := `function main()
+				Put("hello", "world")
+				return "success"
+			end`
+	lc := lua.NewContract(vm.ContractInfo{Prefix: "test", GasLimit: 100, Price: price, Publisher: vm.IOSTAccount(sender)}, code, main)
+
+	_tx := tx.NewTx(int64(nonce), &lc)
+	return &_tx
+}
+
+func TestMemPool(t *testing.T) {
+	Convey("test memPool", t, func() {
+		m := newMemPool()
+		nonceOf := func(sender vm.IOSTAccount) int64 { return 0 }
+		noSkip := func(t *tx.Tx) bool { return false }
+
+		Convey("pending and future", func() {
+			So(m.Add(genPricedTx("a", 1, 1)), ShouldBeNil)
+			So(m.Add(genPricedTx("a", 2, 1)), ShouldBeNil)
+			So(m.Add(genPricedTx("a", 4, 1)), ShouldBeNil)
+			So(m.Add(genPricedTx("b", 1, 3)), ShouldBeNil)
+			So(m.Add(genPricedTx("b", 2, 0.5)), ShouldBeNil)
+
+			pending, future := m.Pending(nonceOf, noSkip)
+			So(len(pending["a"]), ShouldEqual, 2)
+			So(len(pending["b"]), ShouldEqual, 2)
+			So(future, ShouldEqual, 1)
+
+			list := orderByPrice(pending, 10)
+			So(len(list), ShouldEqual, 4)
+			So(list[0].Contract.Info().Publisher, ShouldEqual, vm.IOSTAccount("b"))
+			So(list[1].Contract.Info().Publisher, ShouldEqual, vm.IOSTAccount("a"))
+			So(list[1].Nonce, ShouldEqual, 1)
+			So(list[2].Nonce, ShouldEqual, 2)
+			So(list[3].Contract.Info().Price, ShouldEqual, 0.5)
+			So(len(orderByPrice(pending, 2)), ShouldEqual, 2)
+
+			m.DropStale(func(sender vm.IOSTAccount) int64 { return 1 })
+			So(m.Len(), ShouldEqual, 3)
+		})
+
+		Convey("replace by fee", func() {
+			So(m.Add(genPricedTx("a", 1, 1)), ShouldEqual, nil)
+			So(m.Add(genPricedTx("a", 1, 1.05)), ShouldEqual, ErrReplaceUnderpriced)
+			So(m.Add(genPricedTx("a", 1, 2)), ShouldBeNil)
+			So(m.Len(), ShouldEqual, 1)
+			pending, _ := m.Pending(nonceOf, noSkip)
+			So(pending["a"][0].Contract.Info().Price, ShouldEqual, 2)
+		})
+
+		Convey("limits and eviction", func() {
+			gs, as := GlobalSlots, AccountSlots
+			GlobalSlots, AccountSlots = 3, 2
+			defer func() { GlobalSlots, AccountSlots = gs, as }()
+
+			So(m.Add(genPricedTx("a", 1, 1)), ShouldBeNil)
+			So(m.Add(genPricedTx("a", 2, 1)), ShouldBeNil)
+			So(m.Add(genPricedTx("a", 3, 5)), ShouldEqual, ErrSenderFull)
+			So(m.Add(genPricedTx("b", 1, 2)), ShouldBeNil)
+			So(m.Add(genPricedTx("c", 1, 1)), ShouldEqual, ErrPoolUnderpriced)
+			So(m.Add(genPricedTx("c", 1, 3)), ShouldBeNil)
+			So(m.Len(), ShouldEqual, 3)
+
+			pending, _ := m.Pending(nonceOf, noSkip)
+			So(len(pending["a"]), ShouldEqual, 1)
+		})
+
+		Convey("eviction keeps the queue of the sender", func() {
+			gs := GlobalSlots
+			GlobalSlots = 2
+			defer func() { GlobalSlots = gs }()
+
+			So(m.Add(genPricedTx("a", 1, 1)), ShouldBeNil)
+			So(m.Add(genPricedTx("b", 1, 2)), ShouldBeNil)
+			So(m.Add(genPricedTx("a", 2, 3)), ShouldBeNil)
+
+			pending, future := m.Pending(nonceOf, noSkip)
+			So(len(pending["a"]), ShouldEqual, 2)
+			So(len(pending["b"]), ShouldEqual, 0)
+			So(future, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestRejectCode(t *testing.T) {
+	Convey("test RejectCode", t, func() {
+		So(RejectCode(nil), ShouldEqual, 0)
+		So(RejectCode(fmt.Errorf("other")), ShouldEqual, -1)
+		for err, code := range rejectCodes {
+			So(RejectCode(err), ShouldEqual, code)
+			So(RejectReason(code), ShouldEqual, err.Error())
+		}
+		So(RejectReason(-1), ShouldEqual, "tx rejected")
+	})
+}
+
+func TestTxJournal(t *testing.T) {
+	Convey("test txJournal", t, func() {
+		dir, err := ioutil.TempDir("", "journal")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		j, err := newTxJournal(dir + "/")
+		So(err, ShouldBeNil)
+
+		m := newMemPool()
+		m.journal = j
+		tx1 := genPricedTx("a", 2, 1)
+		tx2 := genPricedTx("a", 1, 1)
+		So(m.Add(tx1), ShouldBeNil)
+		So(m.Add(tx2), ShouldBeNil)
+
+		txs := j.load()
+		So(len(txs), ShouldEqual, 2)
+		So(txs[0].Nonce, ShouldEqual, 1)
+		So(txs[1].Nonce, ShouldEqual, 2)
+
+		m.Del(tx2.TxID())
+		txs = j.load()
+		So(len(txs), ShouldEqual, 1)
+		So(txs[0].TxID(), ShouldEqual, tx1.TxID())
+
+		old := genPricedTx("b", 1, 1)
+		old.Time = time.Now().Add(-time.Hour).UnixNano()
+		So(m.Add(old), ShouldBeNil)
+		So(m.agedFrom(old), ShouldEqual, old.Time/1e9)
+		m.reloaded[old.TxID()] = time.Now().Unix()
+		So(m.agedFrom(old), ShouldEqual, m.reloaded[old.TxID()])
+		m.Del(old.TxID())
+		So(m.reloaded, ShouldBeEmpty)
+		j.close()
+	})
+}
+
+func TestHashCache(t *testing.T) {
+	Convey("test hashCache", t, func() {
+		c := newHashCache(50 * time.Millisecond)
+		So(c.Add("h1"), ShouldBeTrue)
+		So(c.Add("h1"), ShouldBeFalse)
+		So(c.Has("h1"), ShouldBeTrue)
+		So(c.Has("h2"), ShouldBeFalse)
+
+		time.Sleep(60 * time.Millisecond)
+		So(c.Has("h1"), ShouldBeFalse)
+		So(c.Add("h2"), ShouldBeTrue)
+		c.Expire()
+		So(c.Len(), ShouldEqual, 1)
+		So(c.Add("h1"), ShouldBeTrue)
+
+		c.Del("h1")
+		So(c.Has("h1"), ShouldBeFalse)
+	})
+}
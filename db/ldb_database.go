@@ -92,9 +92,15 @@ func (db *LDBDatabase) NewIterator() iterator.Iterator {
 	return db.db.NewIterator(nil, nil)
 }
 
+// Close closes the database, the file is opened again by the next NewLDBDatabase.
 func (db *LDBDatabase) Close() {
 	db.quitLock.Lock()
 	defer db.quitLock.Unlock()
+	mutex.Lock()
+	if ldbMap[db.fn] == db {
+		delete(ldbMap, db.fn)
+	}
+	mutex.Unlock()
 	db.db.Close()
 }
 
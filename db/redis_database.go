@@ -1,6 +1,7 @@
 package db
 
 import (
+	"bytes"
 	"strconv"
 	"time"
 
@@ -126,3 +127,41 @@ func (rdb *RedisDatabase) GetAll(key string) (map[string]string, error) {
 	defer conn.Close()
 	return redis.StringMap(conn.Do("HGETALL", key))
 }
+
+// Keys returns the keys of the database beginning with prefix, a key may be returned twice if it
+// changed meanwhile.
+func (rdb *RedisDatabase) Keys(prefix []byte) ([][]byte, error) {
+	conn := rdb.connPool.Get()
+	defer conn.Close()
+	match := make([]byte, 0, len(prefix)+1)
+	for _, c := range prefix {
+		if bytes.IndexByte([]byte(`*?[]\`), c) >= 0 {
+			match = append(match, '\\')
+		}
+		match = append(match, c)
+	}
+	match = append(match, '*')
+	keys := make([][]byte, 0)
+	cursor := "0"
+	for {
+		values, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", match, "COUNT", 1000))
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != 2 {
+			return nil, errors.New("unexpected reply of scan")
+		}
+		cursor, err = redis.String(values[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		ks, err := redis.ByteSlices(values[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, ks...)
+		if cursor == "0" {
+			return keys, nil
+		}
+	}
+}
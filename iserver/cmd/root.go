@@ -21,10 +21,12 @@ import (
 	"os"
 	"runtime"
 	"runtime/pprof"
+	"sort"
 
 	"github.com/iost-official/Go-IOS-Protocol/account"
 	"github.com/iost-official/Go-IOS-Protocol/common"
 	"github.com/iost-official/Go-IOS-Protocol/consensus"
+	"github.com/iost-official/Go-IOS-Protocol/consensus/common"
 	"github.com/iost-official/Go-IOS-Protocol/core/block"
 	"github.com/iost-official/Go-IOS-Protocol/core/blockcache"
 	"github.com/iost-official/Go-IOS-Protocol/core/state"
@@ -44,7 +46,6 @@ import (
 
 	"github.com/iost-official/Go-IOS-Protocol/consensus/pob"
 	"github.com/iost-official/Go-IOS-Protocol/core/txpool"
-	"github.com/iost-official/Go-IOS-Protocol/vm"
 )
 
 var cfgFile string
@@ -106,6 +107,35 @@ var rootCmd = &cobra.Command{
 		log.Log.I("redis.addr: %v", redisAddr)
 		log.Log.I("redis.port: %v", redisPort)
 
+		if viper.IsSet("genesis.chain-id") {
+			tx.ChainID = viper.GetInt64("genesis.chain-id")
+		}
+		log.Log.I("chain-id: %v", tx.ChainID)
+		// block limits only take effect at genesis, later they are read from chain parameters
+		if viper.GetInt64("vm.max-block-gas") > 0 {
+			verifier.MaxBlockGas = uint64(viper.GetInt64("vm.max-block-gas"))
+		}
+		if viper.GetInt64("vm.max-block-size") > 0 {
+			verifier.MaxBlockSize = uint64(viper.GetInt64("vm.max-block-size"))
+		}
+		if viper.IsSet("genesis.block-reward") {
+			verifier.BlockReward = viper.GetFloat64("genesis.block-reward")
+		}
+		if viper.IsSet("genesis.reward-halving") {
+			verifier.RewardHalving = viper.GetInt64("genesis.reward-halving")
+		}
+		if viper.IsSet("genesis.servi-reward") {
+			verifier.ServiReward = viper.GetFloat64("genesis.servi-reward")
+		}
+		if viper.IsSet("genesis.servi-period") {
+			verifier.ServiPeriod = viper.GetInt64("genesis.servi-period")
+		}
+		// state snapshots served to the nodes joining, 0 disables them
+		if viper.IsSet("sync.snapshot-interval") {
+			consensus_common.SnapshotInterval = uint64(viper.GetInt64("sync.snapshot-interval"))
+		}
+		log.Log.I("sync.snapshot-interval: %v", consensus_common.SnapshotInterval)
+
 		tx.LdbPath = ldbPath
 		block.LdbPath = ldbPath
 		db.DBAddr = redisAddr
@@ -196,40 +226,73 @@ var rootCmd = &cobra.Command{
 				}
 			}
 		}
+		if cid, err := state.StdPool.Get(tx.ChainIDKey); err == nil {
+			if val, ok := cid.(*state.VInt); ok && int64(val.ToInt()) != tx.ChainID {
+				log.Log.E("chain-id %v of local chain mismatches config %v, stop the program!", val.ToInt(), tx.ChainID)
+				os.Exit(1)
+			}
+		}
+
 		log.Log.I("1.Start the P2P networks")
 
 		logPath := viper.GetString("net.log-path")
 		nodeTablePath := viper.GetString("net.node-table-path")
-		nodeID := viper.GetString("net.node-id") //optional
+		nodeKeyPath := viper.GetString("net.node-key-path") //optional
 		listenAddr := viper.GetString("net.listen-addr")
-		regAddr := viper.GetString("net.register-addr")
+		regAddr := viper.GetString("net.register-addr")     //optional
+		bootNodes := viper.GetStringSlice("net.boot-nodes") //optional
+		gossipFanout := viper.GetInt("net.gossip-fanout")   //optional
 		rpcPort := viper.GetString("net.rpc-port")
 		target := viper.GetString("net.target") //optional
 		port := viper.GetInt64("net.port")
 		metricsPort := viper.GetString("net.metrics-port")
+		network.NetMode = viper.GetString("net.mode")             //optional
+		staticNodes := viper.GetStringSlice("net.static-nodes")   //optional
+		trustedNodes := viper.GetStringSlice("net.trusted-nodes") //optional
+		allowedPeers := viper.GetStringSlice("net.allowed-peers") //optional
 
 		log.Log.I("net.log-path:  %v", logPath)
 		log.Log.I("net.node-table-path:  %v", nodeTablePath)
-		log.Log.I("net.node-id:   %v", nodeID)
+		log.Log.I("net.node-key-path:   %v", nodeKeyPath)
 		log.Log.I("net.listen-addr:  %v", listenAddr)
 		log.Log.I("net.register-addr:  %v", regAddr)
+		log.Log.I("net.boot-nodes:  %v", bootNodes)
+		log.Log.I("net.gossip-fanout:  %v", gossipFanout)
 		log.Log.I("net.target:  %v", target)
 		log.Log.I("net.port:  %v", port)
 		log.Log.I("net.rpcPort:  %v", rpcPort)
 		log.Log.I("net.metricsPort:  %v", metricsPort)
+		log.Log.I("net.mode:  %v", network.NetMode)
+		log.Log.I("net.static-nodes:  %v", staticNodes)
+		log.Log.I("net.trusted-nodes:  %v", trustedNodes)
+		log.Log.I("net.allowed-peers:  %v", allowedPeers)
 
-		if logPath == "" || nodeTablePath == "" || listenAddr == "" || regAddr == "" || port <= 0 || rpcPort == "" {
+		if logPath == "" || nodeTablePath == "" || listenAddr == "" || port <= 0 || rpcPort == "" {
 			log.Log.E("Network config initialization failed, stop the program!")
 			os.Exit(1)
 		}
 
 		log.Log.I("network instance")
+		chainInfo := func() ([]byte, uint64) {
+			length := blockChain.Length()
+			if length == 0 {
+				return nil, 0
+			}
+			return blockChain.GetBlockByNumber(0).HeadHash(), length - 1
+		}
 		net, err := network.GetInstance(
 			&network.NetConfig{
 				LogPath:       logPath,
 				NodeTablePath: nodeTablePath,
-				NodeID:        nodeID,
+				NodeKeyPath:   nodeKeyPath,
 				RegisterAddr:  regAddr,
+				BootNodes:     bootNodes,
+				ChainID:       tx.ChainID,
+				ChainInfo:     chainInfo,
+				GossipFanout:  gossipFanout,
+				StaticNodes:   staticNodes,
+				TrustedNodes:  trustedNodes,
+				AllowedPeers:  allowedPeers,
 				ListenAddr:    listenAddr},
 			target,
 			uint16(port))
@@ -253,39 +316,14 @@ var rootCmd = &cobra.Command{
 
 		log.Log.I("account ID = %v", acc.ID)
 
-		// init servi
-		sp, err := tx.NewServiPool(len(account.GenesisAccount), 100)
-		if err != nil {
-			log.Log.E("NewServiPool failed, stop the program! err:%v", err)
-			os.Exit(1)
-		}
-		tx.Data = tx.NewHolder(acc, state.StdPool, sp)
-		tx.Data.Spool.Restore()
-		bu, _ := tx.Data.Spool.BestUser()
-
-		if len(bu) != len(account.GenesisAccount) {
-			tx.Data.Spool.ClearBtu()
-			for k, v := range account.GenesisAccount {
-				ser, err := tx.Data.Spool.User(vm.IOSTAccount(k))
-				if err == nil {
-					ser.SetBalance(v)
-				}
+		tx.Data = tx.NewHolder(acc, state.StdPool)
 
-			}
-			tx.Data.Spool.Flush()
-		}
+		// the witnesses are the genesis accounts, in the same order on every node
 		witnessList := make([]string, 0)
-
-		bu, err = tx.Data.Spool.BestUser()
-		if err != nil {
-			for k, _ := range account.GenesisAccount {
-				witnessList = append(witnessList, k)
-			}
-		} else {
-			for _, v := range bu {
-				witnessList = append(witnessList, string(v.Owner()))
-			}
+		for k := range account.GenesisAccount {
+			witnessList = append(witnessList, k)
 		}
+		sort.Strings(witnessList)
 
 		for i, witness := range witnessList {
 			log.Log.I("witnessList[%v] = %v", i, witness)
@@ -299,8 +337,7 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		consensus.Run()
-		serverExit = append(serverExit, consensus)
+		// the tx pool reloads its journal against the chain before block production begins
 		blockCache := consensus.BlockCache()
 		txPool, err := txpool.NewTxPoolServer(blockCache, blockCache.OnBlockChan())
 		if err != nil {
@@ -308,6 +345,9 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		consensus.Run()
+		serverExit = append(serverExit, consensus)
+
 		txPool.Start()
 		serverExit = append(serverExit, txPool)
 
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/iost-official/Go-IOS-Protocol/account"
+	"github.com/iost-official/Go-IOS-Protocol/common"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 )
@@ -36,8 +37,18 @@ var accountCmd = &cobra.Command{
 			if strings.ContainsAny(nickName, `?*:|/\"`) || len(nickName) > 16 {
 				fmt.Println("invalid nick name")
 			}
-			ac, _ := account.NewAccount(nil)
-			pubfile, err := os.Create(kvPath + "/" + nickName + "_secp.pub")
+			algo, err := common.ParseSignAlgorithm(signAlgo)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+			ac, err := account.NewAccountWithAlgo(nil, algo)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+			fileName := kvPath + "/" + nickName + "_" + keyFileSuffix(algo)
+			pubfile, err := os.Create(fileName + ".pub")
 			if err != nil {
 				fmt.Println(err.Error())
 				return
@@ -50,7 +61,7 @@ var accountCmd = &cobra.Command{
 				return
 			}
 
-			secFile, err := os.Create(kvPath + "/" + nickName + "_secp")
+			secFile, err := os.Create(fileName)
 			if err != nil {
 				fmt.Println(err.Error())
 				return
@@ -71,6 +82,7 @@ var accountCmd = &cobra.Command{
 
 var kvPath string
 var nickName string
+var signAlgo string
 
 func init() {
 	rootCmd.AddCommand(accountCmd)
@@ -83,6 +95,7 @@ func init() {
 
 	accountCmd.Flags().StringVarP(&nickName, "create", "c", "id", "Create new account, using input as nickname")
 	accountCmd.Flags().StringVarP(&kvPath, "path", "p", home+"/.ssh", "Set path of key pair file")
+	accountCmd.Flags().StringVarP(&signAlgo, "algo", "a", "secp256k1", "Set sign algorithm of new account, secp256k1 or ed25519")
 
 	// Here you will define your flags and configuration settings.
 
@@ -94,3 +107,10 @@ func init() {
 	// is called directly, e.g.:
 	// accountCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+func keyFileSuffix(algo common.SignAlgorithm) string {
+	if algo == common.Ed25519 {
+		return "ed25519"
+	}
+	return "secp"
+}
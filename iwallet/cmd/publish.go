@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 
@@ -25,7 +26,9 @@ import (
 	"github.com/iost-official/Go-IOS-Protocol/account"
 	"github.com/iost-official/Go-IOS-Protocol/common"
 	"github.com/iost-official/Go-IOS-Protocol/core/tx"
+	"github.com/iost-official/Go-IOS-Protocol/core/txpool"
 	pb "github.com/iost-official/Go-IOS-Protocol/rpc"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
@@ -56,6 +59,13 @@ var publishCmd = &cobra.Command{
 			return
 		}
 
+		for _, sign := range mtx.Signs {
+			if !mtx.VerifySigner(sign) {
+				fmt.Printf("Error: Sign of %v in %v wrong\n", vm.PubkeyToIOSTAccount(sign.Pubkey), args[0])
+				return
+			}
+		}
+
 		for i, v := range args {
 			if i == 0 {
 				continue
@@ -75,6 +85,9 @@ var publishCmd = &cobra.Command{
 				fmt.Printf("Error: Sign %v wrong\n", v)
 				return
 			}
+			if signedBy(mtx, sign.Pubkey) {
+				continue
+			}
 			mtx.Signs = append(mtx.Signs, sign)
 		}
 		fsk, err := ReadFile(kpPath)
@@ -149,12 +162,17 @@ func sendTx(stx tx.Tx) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	switch resp.Code {
-	case 0:
-		return resp.Hash, nil
-	case -1:
-		return nil, errors.New("tx rejected")
-	default:
-		return nil, errors.New("unknown return")
+	if resp.Code != 0 {
+		return nil, errors.New(txpool.RejectReason(resp.Code))
+	}
+	return resp.Hash, nil
+}
+
+func signedBy(mtx tx.Tx, pubkey []byte) bool {
+	for _, s := range mtx.Signs {
+		if bytes.Equal(s.Pubkey, pubkey) {
+			return true
+		}
 	}
+	return false
 }
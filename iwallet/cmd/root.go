@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/iost-official/Go-IOS-Protocol/core/tx"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -40,6 +41,7 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVarP(&dest, "dest", "d", "default", "Set destination of output file")
 	rootCmd.PersistentFlags().StringVarP(&server, "server", "s", "localhost:30303", "Set server of this client")
+	rootCmd.PersistentFlags().Int64Var(&tx.ChainID, "chain-id", tx.ChainID, "Set chain id of the network which txs are signed for")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
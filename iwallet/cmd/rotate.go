@@ -0,0 +1,102 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/tx"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+	"github.com/spf13/cobra"
+)
+
+// rotateCmd represents the rotate command
+var rotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Make a key rotation .sc file for a named account",
+	Long: `Make a key rotation .sc file for a named account, sign it with owner keys and publish it, e.g.
+	iwallet rotate -a alice -l active -t 2 --key <id1>:1 --key <id2>:1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !vm.IsAccountName(accountName) {
+			fmt.Println("invalid account name")
+			return
+		}
+		var level vm.PermissionLevel
+		switch permLevel {
+		case "owner":
+			level = vm.Owner
+		case "active":
+			level = vm.Active
+		default:
+			fmt.Println("invalid permission level, use owner or active")
+			return
+		}
+		perm := vm.Permission{Threshold: int64(threshold)}
+		for _, k := range keyWeights {
+			kw := strings.Split(k, ":")
+			weight := int64(1)
+			if len(kw) == 2 {
+				w, err := strconv.ParseInt(kw[1], 10, 64)
+				if err != nil {
+					fmt.Println("invalid key weight:", k)
+					return
+				}
+				weight = w
+			}
+			perm.Keys = append(perm.Keys, vm.KeyWeight{ID: vm.IOSTAccount(kw[0]), Weight: weight})
+		}
+		if err := perm.Validate(); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		info := vm.ContractInfo{GasLimit: int64(gasLimit), Price: price}
+		mTx, err := tx.NewKeyRotationTx(int64(Nonce), info, accountName, level, perm)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		if dest == "default" {
+			dest = accountName + "_" + permLevel + ".sc"
+		}
+
+		err = SaveTo(dest, mTx.Encode())
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	},
+}
+
+var accountName string
+var permLevel string
+var threshold int
+var keyWeights []string
+var gasLimit int
+var price float64
+
+func init() {
+	rootCmd.AddCommand(rotateCmd)
+
+	rotateCmd.Flags().StringVarP(&accountName, "account", "a", "", "Set name of the account")
+	rotateCmd.Flags().StringVarP(&permLevel, "level", "l", "active", "Set permission level to rotate, owner or active")
+	rotateCmd.Flags().IntVarP(&threshold, "threshold", "t", 1, "Set threshold of the new permission")
+	rotateCmd.Flags().StringSliceVar(&keyWeights, "key", nil, "Add a key of the new permission as id:weight")
+	rotateCmd.Flags().IntVarP(&Nonce, "nonce", "n", 1, "Set Nonce of this Transaction")
+	rotateCmd.Flags().IntVarP(&gasLimit, "gaslimit", "g", 10000, "Set gas limit of this Transaction")
+	rotateCmd.Flags().Float64VarP(&price, "price", "p", 1, "Set gas price of this Transaction")
+}
@@ -28,6 +28,7 @@ import (
 )
 
 var kpPath string
+var appendSign bool
 
 // signCmd represents the sign command
 var signCmd = &cobra.Command{
@@ -81,6 +82,25 @@ var signCmd = &cobra.Command{
 			return
 		}
 
+		if appendSign {
+			if signedBy(mtx, sig.Pubkey) {
+				fmt.Println("Error: already signed by this key")
+				return
+			}
+			mtx.Signs = append(mtx.Signs, sig)
+			if len(args) < 2 {
+				dest = args[0]
+			} else {
+				dest = args[1]
+			}
+			err = SaveTo(dest, mtx.Encode())
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+			fmt.Printf("%v signature(s) collected\n", len(mtx.Signs))
+			return
+		}
+
 		if len(args) < 2 {
 			dest = args[0][:strings.LastIndex(args[0], ".")]
 			dest = dest + ".sig"
@@ -106,6 +126,7 @@ func init() {
 	}
 
 	signCmd.Flags().StringVarP(&kpPath, "key-path", "k", home+"/.ssh/id_secp", "Set path of sec-key")
+	signCmd.Flags().BoolVarP(&appendSign, "append", "a", false, "Collect the signature into the .sc file instead of a .sig file, for multisig accounts")
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports Persistent Flags which will work for this command
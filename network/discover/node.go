@@ -1,9 +1,13 @@
 package discover
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"io/ioutil"
 	"net"
-	"sort"
+	"os"
 	//	"sort"
 	"strconv"
 	"strings"
@@ -14,9 +18,12 @@ import (
 	//	"math/rand"
 )
 
-// NodeID is a node's identity.
+// NodeID is a node's identity, the hex form of its ed25519 public key.
 type NodeID string
 
+// ErrInvalidNodeID is returned when a NodeID is not a public key.
+var ErrInvalidNodeID = errors.New("invalid node id")
+
 // Node represents a connected remote node.
 type Node struct {
 	IP       net.IP // len 4 for IPv4 or 16 for IPv6
@@ -84,35 +91,69 @@ func (n NodeID) String() string {
 	return string(n)
 }
 
-// GenNodeID generates a NodeID.
-func GenNodeID() NodeID {
-	id := common.ToHex(common.Sha256(common.Int64ToBytes(time.Now().UnixNano())))
-	return NodeID(id)
+// Pubkey returns the public key n stands for.
+func (n NodeID) Pubkey() (ed25519.PublicKey, error) {
+	b, err := hex.DecodeString(string(n))
+	if err != nil || len(b) != ed25519.PublicKeySize {
+		return nil, ErrInvalidNodeID
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+// PubkeyID returns the NodeID of pub.
+func PubkeyID(pub ed25519.PublicKey) NodeID {
+	return NodeID(common.ToHex(pub))
+}
+
+// GenNodeKey generates a node key.
+func GenNodeKey() (ed25519.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	return key, err
 }
 
-// ParseNode parses a string to a Node instance.
+// LoadNodeKey reads the node key stored in path, a new key is generated and stored if there is none.
+func LoadNodeKey(path string) (ed25519.PrivateKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err == nil {
+		seed, err := hex.DecodeString(strings.TrimSpace(string(b)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, errors.New("invalid node key file " + path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	key, err := GenNodeKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, []byte(common.ToHex(key.Seed())), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ParseNode parses a string to a Node instance, the string is either "id@ip:port" or "ip:port".
 func ParseNode(nodeStr string) (node *Node, err error) {
 	node = &Node{}
 	nodeIDStrs := strings.Split(nodeStr, "@")
+	if len(nodeIDStrs) > 2 {
+		return node, errors.New("invalid node " + nodeStr)
+	}
 	if len(nodeIDStrs) == 2 {
 		node.ID = NodeID(nodeIDStrs[0])
-		tcpStr := strings.Split(nodeIDStrs[1], ":")
-		node.IP = net.ParseIP(tcpStr[0])
-		tcp, err := strconv.Atoi(tcpStr[1])
-		if err != nil {
-			return node, err
-		}
-		node.TCP = uint16(tcp)
-	}
-	if len(nodeIDStrs) == 1 {
-		tcpStr := strings.Split(nodeIDStrs[0], ":")
-		node.IP = net.ParseIP(tcpStr[0])
-		tcp, err := strconv.Atoi(tcpStr[1])
-		if err != nil {
-			return node, err
-		}
-		node.TCP = uint16(tcp)
 	}
+	tcpStr := strings.Split(nodeIDStrs[len(nodeIDStrs)-1], ":")
+	if len(tcpStr) != 2 {
+		return node, errors.New("invalid node address " + nodeStr)
+	}
+	node.IP = net.ParseIP(tcpStr[0])
+	tcp, err := strconv.Atoi(tcpStr[1])
+	if err != nil {
+		return node, err
+	}
+	node.TCP = uint16(tcp)
 
 	return node, nil
 
@@ -121,37 +162,23 @@ func ParseNode(nodeStr string) (node *Node, err error) {
 // MaxNeighbourNum is the max count of a node's neighbours.
 const MaxNeighbourNum = 8
 
-// FindNeighbours returns a node's neighbours
+// FindNeighbours returns the MaxNeighbourNum nodes of ns closest to n in the Kademlia space.
 func (n *Node) FindNeighbours(ns []*Node) []*Node {
-
-	if len(ns) < MaxNeighbourNum {
-		return ns
-	}
-	neighbours := make([]*Node, 0)
-	disArr := make([]int, len(ns))
-	for k, v := range ns {
-		disArr[k] = xorDistance(n.Addr(), v.Addr())
-	}
-	sortArr := make([]int, len(ns))
-	copy(sortArr, disArr)
-	sort.Ints(sortArr)
-	neighbourKeys := make(map[int]int)
-	for _, v := range sortArr {
-		for k, vd := range disArr {
-			if _, ok := neighbourKeys[k]; !ok && v == vd && len(neighbourKeys) < MaxNeighbourNum {
-				neighbourKeys[k] = 0
-			}
+	neighbours := make([]*Node, 0, len(ns))
+	for _, v := range ns {
+		if v.ID != n.ID && v.Addr() != n.Addr() {
+			neighbours = append(neighbours, v)
 		}
 	}
-	for k := range neighbourKeys {
-		if len(neighbours) >= MaxNeighbourNum || n.Addr() == ns[k].Addr() {
-			continue
-		}
-		neighbours = append(neighbours, ns[k])
+	sortByDistance(neighbours, n.ID)
+	if len(neighbours) > MaxNeighbourNum {
+		neighbours = neighbours[:MaxNeighbourNum]
 	}
 	return neighbours
 }
 
+// xorDistance returns the length of the common prefix of one and other in bits, the bucket of
+// a node in the table of another.
 func xorDistance(one, other string) (ret int) {
 	oneBytes := []byte(one)
 	otherBytes := []byte(other)
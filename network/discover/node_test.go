@@ -1,6 +1,10 @@
 package discover
 
 import (
+	"crypto/ed25519"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -11,7 +15,15 @@ import (
 
 func TestGenNodeId(t *testing.T) {
 	Convey("Test of discover node\n", t, func() {
-		So(len(GenNodeID()), ShouldEqual, 64)
+		key, err := GenNodeKey()
+		So(err, ShouldBeNil)
+		id := PubkeyID(key.Public().(ed25519.PublicKey))
+		So(len(id), ShouldEqual, 64)
+		pub, err := id.Pubkey()
+		So(err, ShouldBeNil)
+		So(pub.Equal(key.Public()), ShouldBeTrue)
+		_, err = NodeID("84a8ec").Pubkey()
+		So(err, ShouldEqual, ErrInvalidNodeID)
 		node, err := ParseNode("84a8ecbeeb6d3f676da1b261c35c7cd15ae17f32b659a6f5ce7be2d60f6c16f9@18.219.254.124:30304")
 		So(err, ShouldBeNil)
 		So(node.TCP, ShouldEqual, uint16(30304))
@@ -21,6 +33,23 @@ func TestGenNodeId(t *testing.T) {
 	})
 }
 
+func TestLoadNodeKey(t *testing.T) {
+	Convey("load node key", t, func() {
+		dir, _ := ioutil.TempDir("", "nodekey")
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "node.key")
+		key, err := LoadNodeKey(path)
+		So(err, ShouldBeNil)
+		key2, err := LoadNodeKey(path)
+		So(err, ShouldBeNil)
+		So(key2.Equal(key), ShouldBeTrue)
+
+		ioutil.WriteFile(path, []byte("bad"), 0600)
+		_, err = LoadNodeKey(path)
+		So(err, ShouldNotBeNil)
+	})
+}
+
 func TestNode_xorDistance(t *testing.T) {
 	Convey("", t, func() {
 		dis := xorDistance("0056847afe9799739b3d9677972a3b58ef609ba78332428f85ed2534d0b49610", "0056847afe9799739b3d9677972a3b58ef4d92ae0b48d32d9c67dc9a302bfc76")
@@ -48,8 +77,8 @@ func TestNode_FindNeighbours(t *testing.T) {
 			"1156847afe9799739b3d9677972a3b58ef609ba78332428f85ed2534d0b49610",
 		}
 		ns := make([]*Node, 0)
-		for _, v := range nodeIds {
-			n := &Node{ID: NodeID(v)}
+		for k, v := range nodeIds {
+			n := NewNode(NodeID(v), net.ParseIP("127.0.0.1"), uint16(30301+k), uint16(30301+k))
 			ns = append(ns, n)
 		}
 		neighbours := ns[0].FindNeighbours(ns)
@@ -59,8 +88,15 @@ func TestNode_FindNeighbours(t *testing.T) {
 		}
 		result := strings.Join(neighboursStr, ",")
 
-		So(result, ShouldNotContainSubstring, nodeIds[1])
+		So(len(neighbours), ShouldEqual, MaxNeighbourNum)
 		So(result, ShouldNotContainSubstring, nodeIds[0])
+		self := idHash(ns[0].ID)
+		farthest := idHash(neighbours[len(neighbours)-1].ID)
+		for _, n := range ns[1:] {
+			if !strings.Contains(result, string(n.ID)) {
+				So(distCmp(self, idHash(n.ID), farthest), ShouldBeGreaterThanOrEqualTo, 0)
+			}
+		}
 	})
 }
 
@@ -0,0 +1,493 @@
+package discover
+
+import (
+	"io"
+	"time"
+	"unsafe"
+)
+
+var (
+	_ = unsafe.Sizeof(0)
+	_ = io.ReadFull
+	_ = time.Now()
+)
+
+type Ping struct {
+	Version    int32
+	TCP        uint16
+	Expiration int64
+}
+
+func (d *Ping) Size() (s uint64) {
+
+	s += 14
+	return
+}
+func (d *Ping) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+
+		buf[0+0] = byte(d.Version >> 0)
+
+		buf[1+0] = byte(d.Version >> 8)
+
+		buf[2+0] = byte(d.Version >> 16)
+
+		buf[3+0] = byte(d.Version >> 24)
+
+	}
+	{
+
+		buf[0+4] = byte(d.TCP >> 0)
+
+		buf[1+4] = byte(d.TCP >> 8)
+
+	}
+	{
+
+		buf[0+6] = byte(d.Expiration >> 0)
+
+		buf[1+6] = byte(d.Expiration >> 8)
+
+		buf[2+6] = byte(d.Expiration >> 16)
+
+		buf[3+6] = byte(d.Expiration >> 24)
+
+		buf[4+6] = byte(d.Expiration >> 32)
+
+		buf[5+6] = byte(d.Expiration >> 40)
+
+		buf[6+6] = byte(d.Expiration >> 48)
+
+		buf[7+6] = byte(d.Expiration >> 56)
+
+	}
+	return buf[:i+14], nil
+}
+
+func (d *Ping) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+
+		d.Version = 0 | (int32(buf[0+0]) << 0) | (int32(buf[1+0]) << 8) | (int32(buf[2+0]) << 16) | (int32(buf[3+0]) << 24)
+
+	}
+	{
+
+		d.TCP = 0 | (uint16(buf[0+4]) << 0) | (uint16(buf[1+4]) << 8)
+
+	}
+	{
+
+		d.Expiration = 0 | (int64(buf[0+6]) << 0) | (int64(buf[1+6]) << 8) | (int64(buf[2+6]) << 16) | (int64(buf[3+6]) << 24) | (int64(buf[4+6]) << 32) | (int64(buf[5+6]) << 40) | (int64(buf[6+6]) << 48) | (int64(buf[7+6]) << 56)
+
+	}
+	return i + 14, nil
+}
+
+type Pong struct {
+	ReplyTok   []byte
+	Expiration int64
+}
+
+func (d *Pong) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.ReplyTok))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 8
+	return
+}
+func (d *Pong) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.ReplyTok))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.ReplyTok)
+		i += l
+	}
+	{
+
+		buf[i+0+0] = byte(d.Expiration >> 0)
+
+		buf[i+1+0] = byte(d.Expiration >> 8)
+
+		buf[i+2+0] = byte(d.Expiration >> 16)
+
+		buf[i+3+0] = byte(d.Expiration >> 24)
+
+		buf[i+4+0] = byte(d.Expiration >> 32)
+
+		buf[i+5+0] = byte(d.Expiration >> 40)
+
+		buf[i+6+0] = byte(d.Expiration >> 48)
+
+		buf[i+7+0] = byte(d.Expiration >> 56)
+
+	}
+	return buf[:i+8], nil
+}
+
+func (d *Pong) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.ReplyTok)) >= l {
+			d.ReplyTok = d.ReplyTok[:l]
+		} else {
+			d.ReplyTok = make([]byte, l)
+		}
+		copy(d.ReplyTok, buf[i+0:])
+		i += l
+	}
+	{
+
+		d.Expiration = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+	}
+	return i + 8, nil
+}
+
+type FindNode struct {
+	Target     string
+	Expiration int64
+}
+
+func (d *FindNode) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Target))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 8
+	return
+}
+func (d *FindNode) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Target))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Target)
+		i += l
+	}
+	{
+
+		buf[i+0+0] = byte(d.Expiration >> 0)
+
+		buf[i+1+0] = byte(d.Expiration >> 8)
+
+		buf[i+2+0] = byte(d.Expiration >> 16)
+
+		buf[i+3+0] = byte(d.Expiration >> 24)
+
+		buf[i+4+0] = byte(d.Expiration >> 32)
+
+		buf[i+5+0] = byte(d.Expiration >> 40)
+
+		buf[i+6+0] = byte(d.Expiration >> 48)
+
+		buf[i+7+0] = byte(d.Expiration >> 56)
+
+	}
+	return buf[:i+8], nil
+}
+
+func (d *FindNode) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Target = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+
+		d.Expiration = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+	}
+	return i + 8, nil
+}
+
+type Neighbors struct {
+	Nodes      []string
+	Expiration int64
+}
+
+func (d *Neighbors) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Nodes))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Nodes {
+
+			{
+				l := uint64(len(d.Nodes[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	s += 8
+	return
+}
+func (d *Neighbors) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Nodes))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Nodes {
+
+			{
+				l := uint64(len(d.Nodes[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+0] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+0] = byte(t)
+					i++
+
+				}
+				copy(buf[i+0:], d.Nodes[k0])
+				i += l
+			}
+
+		}
+	}
+	{
+
+		buf[i+0+0] = byte(d.Expiration >> 0)
+
+		buf[i+1+0] = byte(d.Expiration >> 8)
+
+		buf[i+2+0] = byte(d.Expiration >> 16)
+
+		buf[i+3+0] = byte(d.Expiration >> 24)
+
+		buf[i+4+0] = byte(d.Expiration >> 32)
+
+		buf[i+5+0] = byte(d.Expiration >> 40)
+
+		buf[i+6+0] = byte(d.Expiration >> 48)
+
+		buf[i+7+0] = byte(d.Expiration >> 56)
+
+	}
+	return buf[:i+8], nil
+}
+
+func (d *Neighbors) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Nodes)) >= l {
+			d.Nodes = d.Nodes[:l]
+		} else {
+			d.Nodes = make([]string, l)
+		}
+		for k0 := range d.Nodes {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+0] & 0x7F)
+					for buf[i+0]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+0]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.Nodes[k0] = string(buf[i+0 : i+0+l])
+				i += l
+			}
+
+		}
+	}
+	{
+
+		d.Expiration = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+	}
+	return i + 8, nil
+}
@@ -0,0 +1,414 @@
+package discover
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	mrand "math/rand"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/common"
+)
+
+const (
+	alpha           = 3  // concurrency of lookups
+	bucketSize      = 16 // nodes per bucket
+	maxReplacements = 10 // replacements kept per bucket
+	hashBits        = 256
+
+	maxFindnodeFailures = 3
+	refreshInterval     = 5 * time.Minute
+	revalidateInterval  = 10 * time.Second
+)
+
+// Config is the config of the discovery table.
+type Config struct {
+	Key       ed25519.PrivateKey
+	Self      *Node   // the local node, its TCP port is the UDP port
+	Bootnodes []*Node // nodes to join the network through
+
+	// OnAdd and OnRemove are called when a node enters or leaves the table
+	OnAdd    func(*Node)
+	OnRemove func(*Node)
+}
+
+// bucket holds the nodes sharing the same prefix length with the local node, most recently seen
+// first, and the nodes to replace them with once they stop answering.
+type bucket struct {
+	entries      []*Node
+	replacements []*Node
+}
+
+// Table is the Kademlia table of the known nodes. Nodes are put in buckets by the length of the
+// common prefix of their id hash and the local one, nodes not answering are replaced by
+// recently seen ones, and the table is refreshed by looking up the local node and random ids.
+type Table struct {
+	mu        sync.Mutex
+	buckets   [hashBits]*bucket
+	fails     map[NodeID]int
+	self      *Node
+	bootnodes []*Node
+	net       *udp
+	onAdd     func(*Node)
+	onRemove  func(*Node)
+
+	refreshReq chan chan struct{}
+	closing    chan struct{}
+}
+
+// ListenUDP listens on the UDP port of cfg.Self and starts discovering the network.
+func ListenUDP(cfg *Config) (*Table, error) {
+	addr := &net.UDPAddr{Port: int(cfg.Self.TCP)}
+	conn, err := net.ListenUDP("udp4", addr)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Self.UDP = cfg.Self.TCP
+	t := newTable(cfg, newUDP(conn, cfg.Key, cfg.Self))
+	go t.net.readLoop()
+	go t.loop()
+	return t, nil
+}
+
+func newTable(cfg *Config, u *udp) *Table {
+	t := &Table{
+		fails:      make(map[NodeID]int),
+		self:       cfg.Self,
+		bootnodes:  cfg.Bootnodes,
+		net:        u,
+		onAdd:      cfg.OnAdd,
+		onRemove:   cfg.OnRemove,
+		refreshReq: make(chan chan struct{}),
+		closing:    make(chan struct{}),
+	}
+	for i := range t.buckets {
+		t.buckets[i] = &bucket{}
+	}
+	if u != nil {
+		u.tab = t
+	}
+	return t
+}
+
+// Close stops the table.
+func (t *Table) Close() {
+	close(t.closing)
+	t.net.close()
+}
+
+// Self returns the local node.
+func (t *Table) Self() *Node {
+	return t.self
+}
+
+// Refresh refreshes the table and waits for it to be done.
+func (t *Table) Refresh() {
+	done := make(chan struct{})
+	select {
+	case t.refreshReq <- done:
+		<-done
+	case <-t.closing:
+	}
+}
+
+func (t *Table) loop() {
+	refresh := time.NewTicker(refreshInterval)
+	revalidate := time.NewTicker(revalidateInterval)
+	defer refresh.Stop()
+	defer revalidate.Stop()
+
+	t.refresh()
+	for {
+		select {
+		case <-refresh.C:
+			t.refresh()
+		case done := <-t.refreshReq:
+			t.refresh()
+			close(done)
+		case <-revalidate.C:
+			if t.Len() == 0 {
+				t.refresh()
+			} else {
+				t.revalidate()
+			}
+		case <-t.closing:
+			return
+		}
+	}
+}
+
+// refresh joins the network through the bootnodes if the table is empty, then looks up the local
+// node to fill the closest buckets and a random id to fill the others.
+func (t *Table) refresh() {
+	if t.Len() == 0 {
+		var wg sync.WaitGroup
+		for _, n := range t.bootnodes {
+			wg.Add(1)
+			go func(n *Node) {
+				defer wg.Done()
+				if n.ID != t.self.ID && t.net.ping(n) == nil {
+					t.add(n)
+				}
+			}(n)
+		}
+		wg.Wait()
+	}
+	t.Lookup(t.self.ID)
+	t.Lookup(randomID())
+}
+
+// revalidate pings the least recently seen node of a random bucket, it is moved to the front if it
+// answers and replaced otherwise.
+func (t *Table) revalidate() {
+	t.mu.Lock()
+	buckets := make([]*bucket, 0)
+	for _, b := range t.buckets {
+		if len(b.entries) > 0 {
+			buckets = append(buckets, b)
+		}
+	}
+	if len(buckets) == 0 {
+		t.mu.Unlock()
+		return
+	}
+	b := buckets[mrand.Intn(len(buckets))]
+	last := b.entries[len(b.entries)-1]
+	t.mu.Unlock()
+
+	if t.net.ping(last) == nil {
+		t.add(last)
+		return
+	}
+	t.delete(last)
+}
+
+func randomID() NodeID {
+	b := make([]byte, ed25519.PublicKeySize)
+	rand.Read(b)
+	return NodeID(common.ToHex(b))
+}
+
+// idHash is the position of id in the Kademlia space.
+func idHash(id NodeID) []byte {
+	return common.Sha256([]byte(id))
+}
+
+// distCmp compares the distances of a and b to target, it returns -1 if a is closer.
+func distCmp(target, a, b []byte) int {
+	for i := range target {
+		da := a[i] ^ target[i]
+		db := b[i] ^ target[i]
+		if da > db {
+			return 1
+		} else if da < db {
+			return -1
+		}
+	}
+	return 0
+}
+
+// sortByDistance sorts nodes by their distance to target, closest first.
+func sortByDistance(nodes []*Node, target NodeID) {
+	th := idHash(target)
+	hashes := make(map[NodeID][]byte, len(nodes))
+	for _, n := range nodes {
+		hashes[n.ID] = idHash(n.ID)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return distCmp(th, hashes[nodes[i].ID], hashes[nodes[j].ID]) < 0
+	})
+}
+
+func (t *Table) bucket(id NodeID) *bucket {
+	d := xorDistance(string(idHash(t.self.ID)), string(idHash(id)))
+	if d >= hashBits {
+		return nil
+	}
+	return t.buckets[d]
+}
+
+func indexOf(nodes []*Node, id NodeID) int {
+	for i, n := range nodes {
+		if n.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// add puts n, which just answered, at the front of its bucket. If the bucket is full n becomes a
+// replacement.
+func (t *Table) add(n *Node) {
+	if n.ID == t.self.ID {
+		return
+	}
+	t.mu.Lock()
+	b := t.bucket(n.ID)
+	if b == nil {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.fails, n.ID)
+	added := false
+	if i := indexOf(b.entries, n.ID); i >= 0 {
+		b.entries = append(b.entries[:i], b.entries[i+1:]...)
+		b.entries = append([]*Node{n}, b.entries...)
+	} else if len(b.entries) < bucketSize {
+		n.addedAt = time.Now()
+		b.entries = append([]*Node{n}, b.entries...)
+		added = true
+	} else {
+		if i := indexOf(b.replacements, n.ID); i >= 0 {
+			b.replacements = append(b.replacements[:i], b.replacements[i+1:]...)
+		}
+		b.replacements = append(b.replacements, n)
+		if len(b.replacements) > maxReplacements {
+			b.replacements = b.replacements[1:]
+		}
+	}
+	t.mu.Unlock()
+	if added && t.onAdd != nil {
+		t.onAdd(n)
+	}
+}
+
+// delete removes n from the table, the most recent replacement of its bucket takes its place.
+func (t *Table) delete(n *Node) {
+	t.mu.Lock()
+	b := t.bucket(n.ID)
+	if b == nil {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.fails, n.ID)
+	i := indexOf(b.entries, n.ID)
+	if i < 0 {
+		t.mu.Unlock()
+		return
+	}
+	b.entries = append(b.entries[:i], b.entries[i+1:]...)
+	var r *Node
+	if len(b.replacements) > 0 {
+		r = b.replacements[len(b.replacements)-1]
+		b.replacements = b.replacements[:len(b.replacements)-1]
+		r.addedAt = time.Now()
+		b.entries = append(b.entries, r)
+	}
+	t.mu.Unlock()
+	if t.onRemove != nil {
+		t.onRemove(n)
+	}
+	if r != nil && t.onAdd != nil {
+		t.onAdd(r)
+	}
+}
+
+// fail records a failed request to n, n is deleted after maxFindnodeFailures.
+func (t *Table) fail(n *Node) {
+	t.mu.Lock()
+	t.fails[n.ID]++
+	fails := t.fails[n.ID]
+	t.mu.Unlock()
+	if fails >= maxFindnodeFailures {
+		t.delete(n)
+	}
+}
+
+// Len returns the number of nodes in the table.
+func (t *Table) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	l := 0
+	for _, b := range t.buckets {
+		l += len(b.entries)
+	}
+	return l
+}
+
+// Nodes returns all the nodes in the table.
+func (t *Table) Nodes() []*Node {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	nodes := make([]*Node, 0)
+	for _, b := range t.buckets {
+		nodes = append(nodes, b.entries...)
+	}
+	return nodes
+}
+
+// Closest returns the n nodes in the table closest to target.
+func (t *Table) Closest(target NodeID, n int) []*Node {
+	nodes := t.Nodes()
+	sortByDistance(nodes, target)
+	if len(nodes) > n {
+		nodes = nodes[:n]
+	}
+	return nodes
+}
+
+// Lookup finds the nodes closest to target in the network. The closest known nodes are asked for
+// closer ones, alpha at a time, until the closest bucketSize nodes have all been asked.
+func (t *Table) Lookup(target NodeID) []*Node {
+	asked := map[NodeID]bool{t.self.ID: true}
+	seen := map[NodeID]bool{t.self.ID: true}
+	result := t.Closest(target, bucketSize)
+	for _, n := range result {
+		seen[n.ID] = true
+	}
+
+	reply := make(chan []*Node, alpha)
+	pending := 0
+	for {
+		for i := 0; i < len(result) && pending < alpha; i++ {
+			n := result[i]
+			if asked[n.ID] {
+				continue
+			}
+			asked[n.ID] = true
+			pending++
+			go func() {
+				nodes, err := t.net.findnode(n, target)
+				if err != nil {
+					t.fail(n)
+				} else {
+					t.add(n)
+				}
+				reply <- nodes
+			}()
+		}
+		if pending == 0 {
+			break
+		}
+		for _, n := range <-reply {
+			if !seen[n.ID] {
+				seen[n.ID] = true
+				result = append(result, n)
+			}
+		}
+		pending--
+		sortByDistance(result, target)
+		if len(result) > bucketSize {
+			result = result[:bucketSize]
+		}
+	}
+	return result
+}
+
+// ParseNodes parses node strings, every node must have an id.
+func ParseNodes(nodeStrs []string) ([]*Node, error) {
+	nodes := make([]*Node, 0, len(nodeStrs))
+	for _, s := range nodeStrs {
+		n, err := ParseNode(s)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := n.ID.Pubkey(); err != nil {
+			return nil, ErrInvalidNodeID
+		}
+		n.UDP = n.TCP
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
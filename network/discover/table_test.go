@@ -0,0 +1,148 @@
+package discover
+
+import (
+	"crypto/ed25519"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newTestNode(port uint16) (*Node, ed25519.PrivateKey) {
+	key, _ := GenNodeKey()
+	return NewNode(PubkeyID(key.Public().(ed25519.PublicKey)), net.ParseIP("127.0.0.1"), port, port), key
+}
+
+func TestTable_add(t *testing.T) {
+	Convey("table buckets", t, func() {
+		self, _ := newTestNode(30301)
+		added, removed := 0, 0
+		tab := newTable(&Config{
+			Self:     self,
+			OnAdd:    func(*Node) { added++ },
+			OnRemove: func(*Node) { removed++ },
+		}, nil)
+
+		Convey("ignores the local node", func() {
+			tab.add(self)
+			So(tab.Len(), ShouldEqual, 0)
+		})
+
+		Convey("keeps replacements for full buckets", func() {
+			nodes := make([]*Node, 0)
+			for i := 0; len(tab.buckets[0].entries) < bucketSize || len(tab.buckets[0].replacements) == 0; i++ {
+				n, _ := newTestNode(uint16(30400 + i))
+				if tab.bucket(n.ID) == tab.buckets[0] {
+					nodes = append(nodes, n)
+				}
+				tab.add(n)
+			}
+			b := tab.buckets[0]
+			So(len(b.entries), ShouldEqual, bucketSize)
+			So(len(b.replacements), ShouldEqual, 1)
+			So(added, ShouldEqual, tab.Len())
+
+			replacement := b.replacements[0]
+			tab.delete(nodes[0])
+			So(len(b.entries), ShouldEqual, bucketSize)
+			So(indexOf(b.entries, replacement.ID), ShouldBeGreaterThanOrEqualTo, 0)
+			So(indexOf(b.entries, nodes[0].ID), ShouldEqual, -1)
+			So(removed, ShouldEqual, 1)
+		})
+
+		Convey("returns the closest nodes", func() {
+			for i := 0; i < 20; i++ {
+				n, _ := newTestNode(uint16(30400 + i))
+				tab.add(n)
+			}
+			target, _ := newTestNode(30500)
+			closest := tab.Closest(target.ID, 5)
+			So(len(closest), ShouldEqual, 5)
+			th := idHash(target.ID)
+			for _, n := range tab.Nodes() {
+				if indexOf(closest, n.ID) < 0 {
+					So(distCmp(th, idHash(n.ID), idHash(closest[4].ID)), ShouldBeGreaterThanOrEqualTo, 0)
+				}
+			}
+		})
+	})
+}
+
+func TestTable_Lookup(t *testing.T) {
+	Convey("nodes find each other through a boot node", t, func() {
+		boot, bootKey := newTestNode(30611)
+		bootTab, err := ListenUDP(&Config{Key: bootKey, Self: boot})
+		So(err, ShouldBeNil)
+		defer bootTab.Close()
+
+		tabs := make([]*Table, 0)
+		for i := 0; i < 3; i++ {
+			self, key := newTestNode(uint16(30612 + i))
+			tab, err := ListenUDP(&Config{Key: key, Self: self, Bootnodes: []*Node{boot}})
+			So(err, ShouldBeNil)
+			defer tab.Close()
+			tab.Refresh()
+			tabs = append(tabs, tab)
+		}
+		time.Sleep(100 * time.Millisecond)
+		So(bootTab.Len(), ShouldEqual, 3)
+
+		found := tabs[0].Lookup(tabs[2].Self().ID)
+		So(indexOf(found, tabs[2].Self().ID), ShouldBeGreaterThanOrEqualTo, 0)
+		So(indexOf(tabs[0].Nodes(), tabs[2].Self().ID), ShouldBeGreaterThanOrEqualTo, 0)
+	})
+
+	Convey("packets are authenticated", t, func() {
+		self, key := newTestNode(30621)
+		u := newUDP(nil, key, self)
+		req := &FindNode{Target: string(self.ID), Expiration: expirationTime()}
+		payload, _ := req.Marshal(nil)
+		packet, _ := u.encodePacket(findnodePacket, payload)
+		_, ptype, from, _, err := decodePacket(packet)
+		So(err, ShouldBeNil)
+		So(ptype, ShouldEqual, findnodePacket)
+		So(from, ShouldEqual, self.ID)
+
+		packet[len(packet)-1]++
+		_, _, _, _, err = decodePacket(packet)
+		So(err, ShouldEqual, ErrBadSignature)
+	})
+
+	Convey("find-node is answered at the bonded address only", t, func() {
+		self, key := newTestNode(30631)
+		tab, err := ListenUDP(&Config{Key: key, Self: self})
+		So(err, ShouldBeNil)
+		defer tab.Close()
+		u := tab.net
+
+		other, _ := newTestNode(30632)
+		req := &FindNode{Target: string(other.ID), Expiration: expirationTime()}
+		addr := udpAddr(other)
+		So(u.handleFindnode(req, other.ID, addr), ShouldEqual, ErrUnbonded)
+
+		u.mu.Lock()
+		u.lastPong[other.ID] = time.Now()
+		u.pongAddr[other.ID] = addr.String()
+		u.mu.Unlock()
+		So(u.handleFindnode(req, other.ID, addr), ShouldBeNil)
+		spoofed := &net.UDPAddr{IP: net.ParseIP("127.0.0.2"), Port: addr.Port}
+		So(u.handleFindnode(req, other.ID, spoofed), ShouldEqual, ErrUnbonded)
+	})
+
+	Convey("pings back a bounded number of nodes", t, func() {
+		self, key := newTestNode(30641)
+		u := newUDP(nil, key, self)
+		ids := make([]NodeID, 0)
+		for i := 0; i < maxBonding; i++ {
+			n, _ := newTestNode(uint16(30650 + i))
+			So(u.startBonding(n.ID), ShouldBeTrue)
+			So(u.startBonding(n.ID), ShouldBeFalse)
+			ids = append(ids, n.ID)
+		}
+		n, _ := newTestNode(30649)
+		So(u.startBonding(n.ID), ShouldBeFalse)
+		u.endBonding(ids[0])
+		So(u.startBonding(n.ID), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,405 @@
+package discover
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/common"
+)
+
+// errors of the discovery protocol
+var (
+	ErrPacketTooSmall = errors.New("too small")
+	ErrBadSignature   = errors.New("bad signature")
+	ErrExpired        = errors.New("expired")
+	ErrUnknownPacket  = errors.New("unknown packet type")
+	ErrTimeout        = errors.New("RPC timeout")
+	ErrUnbonded       = errors.New("unbonded node")
+	ErrClosed         = errors.New("socket closed")
+)
+
+// packet types
+const (
+	pingPacket byte = iota + 1
+	pongPacket
+	findnodePacket
+	neighborsPacket
+)
+
+const (
+	// Version is the version of the discovery protocol.
+	Version = 1
+
+	headSize      = ed25519.PublicKeySize + ed25519.SignatureSize
+	maxPacketSize = 2048
+
+	respTimeout    = 500 * time.Millisecond
+	expiration     = 20 * time.Second
+	bondExpiration = 24 * time.Hour
+	// maxBonding is the max number of pings sent back to unbonded nodes at the same time
+	maxBonding = 16
+)
+
+// udp is the transport of the discovery protocol. Every packet is the public key of the sender,
+// its signature of the rest of the packet, the packet type and the payload. Nodes are bonded by a
+// ping answered with a pong, find-node is only answered for bonded nodes at the address they
+// answered from so that it can not be used to flood a spoofed address. The UDP port of a node
+// is its TCP port.
+type udp struct {
+	conn *net.UDPConn
+	key  ed25519.PrivateKey
+	self *Node
+	tab  *Table
+
+	mu       sync.Mutex
+	pending  []*pending
+	lastPong map[NodeID]time.Time // the last pong received from a node
+	pongAddr map[NodeID]string    // the address the last pong of a node answered
+	bonding  map[NodeID]bool      // the nodes pinged back, at most maxBonding
+
+	closing chan struct{}
+}
+
+// pending is a reply the udp waits for. callback returns true when the reply is complete.
+type pending struct {
+	from     NodeID
+	ptype    byte
+	callback func(p interface{}) bool
+	errc     chan error
+}
+
+func newUDP(conn *net.UDPConn, key ed25519.PrivateKey, self *Node) *udp {
+	return &udp{
+		conn:     conn,
+		key:      key,
+		self:     self,
+		lastPong: make(map[NodeID]time.Time),
+		pongAddr: make(map[NodeID]string),
+		bonding:  make(map[NodeID]bool),
+		closing:  make(chan struct{}),
+	}
+}
+
+func (u *udp) close() {
+	close(u.closing)
+	u.conn.Close()
+}
+
+func udpAddr(n *Node) *net.UDPAddr {
+	port := n.UDP
+	if port == 0 {
+		port = n.TCP
+	}
+	return &net.UDPAddr{IP: n.IP, Port: int(port)}
+}
+
+func expirationTime() int64 {
+	return time.Now().Add(expiration).Unix()
+}
+
+func isExpired(ts int64) bool {
+	return time.Unix(ts, 0).Before(time.Now())
+}
+
+// encodePacket signs and encodes a packet, it returns the packet and its hash.
+func (u *udp) encodePacket(ptype byte, payload []byte) ([]byte, []byte) {
+	body := append([]byte{ptype}, payload...)
+	packet := make([]byte, 0, headSize+len(body))
+	packet = append(packet, u.key.Public().(ed25519.PublicKey)...)
+	packet = append(packet, ed25519.Sign(u.key, body)...)
+	packet = append(packet, body...)
+	return packet, common.Sha256(packet)
+}
+
+// decodePacket checks the signature of a packet and decodes it.
+func decodePacket(buf []byte) (p interface{}, ptype byte, from NodeID, hash []byte, err error) {
+	if len(buf) < headSize+1 {
+		return nil, 0, "", nil, ErrPacketTooSmall
+	}
+	pub := ed25519.PublicKey(buf[:ed25519.PublicKeySize])
+	body := buf[headSize:]
+	if !ed25519.Verify(pub, body, buf[ed25519.PublicKeySize:headSize]) {
+		return nil, 0, "", nil, ErrBadSignature
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid packet: %v", r)
+		}
+	}()
+	ptype = body[0]
+	switch ptype {
+	case pingPacket:
+		req := &Ping{}
+		_, err = req.Unmarshal(body[1:])
+		p = req
+	case pongPacket:
+		req := &Pong{}
+		_, err = req.Unmarshal(body[1:])
+		p = req
+	case findnodePacket:
+		req := &FindNode{}
+		_, err = req.Unmarshal(body[1:])
+		p = req
+	case neighborsPacket:
+		req := &Neighbors{}
+		_, err = req.Unmarshal(body[1:])
+		p = req
+	default:
+		return nil, ptype, "", nil, ErrUnknownPacket
+	}
+	return p, ptype, PubkeyID(pub), common.Sha256(buf), err
+}
+
+func (u *udp) write(to *net.UDPAddr, ptype byte, payload []byte) ([]byte, error) {
+	packet, hash := u.encodePacket(ptype, payload)
+	_, err := u.conn.WriteToUDP(packet, to)
+	return hash, err
+}
+
+// addPending registers a reply to wait for, it must be done before the request is sent.
+func (u *udp) addPending(from NodeID, ptype byte, callback func(p interface{}) bool) *pending {
+	p := &pending{from: from, ptype: ptype, callback: callback, errc: make(chan error, 1)}
+	u.mu.Lock()
+	u.pending = append(u.pending, p)
+	u.mu.Unlock()
+	return p
+}
+
+func (u *udp) removePending(p *pending) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, q := range u.pending {
+		if q == p {
+			u.pending = append(u.pending[:i], u.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// wait waits for p to complete.
+func (u *udp) wait(p *pending) error {
+	defer u.removePending(p)
+	select {
+	case err := <-p.errc:
+		return err
+	case <-time.After(respTimeout):
+		return ErrTimeout
+	case <-u.closing:
+		return ErrClosed
+	}
+}
+
+// handleReply passes a reply to the pending request waiting for it, it returns false if none is.
+func (u *udp) handleReply(from NodeID, ptype byte, p interface{}) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, q := range u.pending {
+		if q.from == from && q.ptype == ptype && q.callback(p) {
+			u.pending = append(u.pending[:i], u.pending[i+1:]...)
+			q.errc <- nil
+			return true
+		}
+	}
+	return false
+}
+
+// ping sends a ping to n and waits for the pong, n is bonded once it answers.
+func (u *udp) ping(n *Node) error {
+	req := &Ping{Version: Version, TCP: u.self.TCP, Expiration: expirationTime()}
+	payload, err := req.Marshal(nil)
+	if err != nil {
+		return err
+	}
+	packet, hash := u.encodePacket(pingPacket, payload)
+	p := u.addPending(n.ID, pongPacket, func(p interface{}) bool {
+		return bytes.Equal(p.(*Pong).ReplyTok, hash)
+	})
+	if _, err := u.conn.WriteToUDP(packet, udpAddr(n)); err != nil {
+		u.removePending(p)
+		return err
+	}
+	if err := u.wait(p); err != nil {
+		return err
+	}
+	u.mu.Lock()
+	u.lastPong[n.ID] = time.Now()
+	u.pongAddr[n.ID] = udpAddr(n).String()
+	u.mu.Unlock()
+	return nil
+}
+
+// bonded tells whether n answered a ping lately.
+func (u *udp) bonded(n *Node) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return time.Since(u.lastPong[n.ID]) < bondExpiration
+}
+
+// bondedAt tells whether id answered a ping sent to addr lately.
+func (u *udp) bondedAt(id NodeID, addr *net.UDPAddr) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return time.Since(u.lastPong[id]) < bondExpiration && u.pongAddr[id] == addr.String()
+}
+
+// findnode asks n for the nodes closest to target it knows, n is pinged first if it is not bonded.
+func (u *udp) findnode(n *Node, target NodeID) ([]*Node, error) {
+	if !u.bonded(n) {
+		if err := u.ping(n); err != nil {
+			return nil, err
+		}
+	}
+	req := &FindNode{Target: string(target), Expiration: expirationTime()}
+	payload, err := req.Marshal(nil)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*Node, 0)
+	p := u.addPending(n.ID, neighborsPacket, func(p interface{}) bool {
+		for _, s := range p.(*Neighbors).Nodes {
+			node, err := ParseNode(s)
+			if err != nil || node.ID == u.self.ID {
+				continue
+			}
+			if _, err := node.ID.Pubkey(); err != nil {
+				continue
+			}
+			node.UDP = node.TCP
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	if _, err := u.write(udpAddr(n), findnodePacket, payload); err != nil {
+		u.removePending(p)
+		return nil, err
+	}
+	return nodes, u.wait(p)
+}
+
+// readLoop reads and handles packets until the connection is closed.
+func (u *udp) readLoop() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, from, err := u.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-u.closing:
+				return
+			default:
+			}
+			continue
+		}
+		packet := make([]byte, n)
+		copy(packet, buf[:n])
+		u.handlePacket(packet, from)
+	}
+}
+
+func (u *udp) handlePacket(buf []byte, from *net.UDPAddr) error {
+	p, ptype, fromID, hash, err := decodePacket(buf)
+	if err != nil {
+		return err
+	}
+	if fromID == u.self.ID {
+		return nil
+	}
+	switch ptype {
+	case pingPacket:
+		return u.handlePing(p.(*Ping), fromID, from, hash)
+	case pongPacket:
+		if isExpired(p.(*Pong).Expiration) {
+			return ErrExpired
+		}
+		u.handleReply(fromID, ptype, p)
+	case findnodePacket:
+		return u.handleFindnode(p.(*FindNode), fromID, from)
+	case neighborsPacket:
+		if isExpired(p.(*Neighbors).Expiration) {
+			return ErrExpired
+		}
+		u.handleReply(fromID, ptype, p)
+	}
+	return nil
+}
+
+func (u *udp) handlePing(req *Ping, fromID NodeID, from *net.UDPAddr, hash []byte) error {
+	if isExpired(req.Expiration) {
+		return ErrExpired
+	}
+	pong := &Pong{ReplyTok: hash, Expiration: expirationTime()}
+	payload, err := pong.Marshal(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := u.write(from, pongPacket, payload); err != nil {
+		return err
+	}
+
+	// the sender is added once it answers a ping too, which proves it owns the address
+	n := NewNode(fromID, from.IP, uint16(from.Port), req.TCP)
+	if u.tab == nil {
+		return nil
+	}
+	if u.bonded(n) {
+		u.tab.add(n)
+		return nil
+	}
+	if !u.startBonding(fromID) {
+		return nil
+	}
+	go func() {
+		defer u.endBonding(fromID)
+		if u.ping(n) == nil {
+			u.tab.add(n)
+		}
+	}()
+	return nil
+}
+
+// startBonding tells whether id may be pinged back, a node is pinged back once at a time and
+// no more than maxBonding nodes are, the others can ping again later.
+func (u *udp) startBonding(id NodeID) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.bonding[id] || len(u.bonding) >= maxBonding {
+		return false
+	}
+	u.bonding[id] = true
+	return true
+}
+
+func (u *udp) endBonding(id NodeID) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.bonding, id)
+}
+
+func (u *udp) handleFindnode(req *FindNode, fromID NodeID, from *net.UDPAddr) error {
+	if isExpired(req.Expiration) {
+		return ErrExpired
+	}
+	if !u.bondedAt(fromID, from) {
+		return ErrUnbonded
+	}
+	if u.tab == nil {
+		return nil
+	}
+	closest := u.tab.Closest(NodeID(req.Target), bucketSize)
+	resp := &Neighbors{Nodes: make([]string, 0, len(closest)), Expiration: expirationTime()}
+	for _, n := range closest {
+		if n.ID != fromID {
+			resp.Nodes = append(resp.Nodes, n.String())
+		}
+	}
+	payload, err := resp.Marshal(nil)
+	if err != nil {
+		return err
+	}
+	_, err = u.write(from, neighborsPacket, payload)
+	return err
+}
@@ -0,0 +1,262 @@
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+// Framing v2 sends a packed request in frames carrying a checksum, the request may be compressed
+// and is cut into several frames if large. Frames of different requests interleave on a
+// connection, so that small requests are not held up by a large block.
+//
+//	magic    [4]byte "ios2"
+//	flags    byte    compression | frameMore if the request goes on in the next frame of the stream
+//	stream   uint32  the request the frame belongs to
+//	length   uint32  of the payload
+//	checksum uint32  crc32c of the payload
+//	payload
+//
+// Peers which do not speak the "frame" capability at version 2 are sent the v1 framing, which is
+// read whatever was negotiated.
+
+// framing v2 parameters
+var (
+	// NetVersionV2 starts the frames of framing v2.
+	NetVersionV2 = [4]byte{'i', 'o', 's', '2'}
+	// FrameChunkSize bounds the payload of a frame, larger requests go in several frames. Frames
+	// read larger than it are refused.
+	FrameChunkSize = 64 << 10
+	// MaxPartialSize bounds the bytes buffered for the requests partially read from a peer
+	MaxPartialSize = int(MaxRequestSize)
+	// CompressMinSize is the size requests are compressed from
+	CompressMinSize = 512
+	// Compressions are the compressions requests are sent with, by preference. The first one the
+	// peer speaks is used.
+	Compressions = []string{"snappy", "deflate"}
+
+	maxPartialStreams = 16
+)
+
+// errors of framing v2
+var (
+	ErrFrame         = errors.New("invalid frame")
+	ErrFrameChecksum = errors.New("frame checksum mismatch")
+)
+
+const (
+	codecNone byte = iota
+	codecSnappy
+	codecDeflate
+
+	frameMore    byte = 0x80
+	frameHeadLen      = 4 + 1 + 4 + 4 + 4
+)
+
+var codecOfName = map[string]byte{"snappy": codecSnappy, "deflate": codecDeflate}
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// framingOf returns the framing and the compression to send requests with to a peer which
+// negotiated caps.
+func framingOf(caps capSet) (uint32, byte) {
+	if caps["frame"] < 2 {
+		return 1, codecNone
+	}
+	for _, name := range Compressions {
+		if _, ok := caps[name]; ok {
+			return 2, codecOfName[name]
+		}
+	}
+	return 2, codecNone
+}
+
+// frameConn is a connection with a peer after the hello, requests are sent in the framing
+// negotiated.
+type frameConn struct {
+	net.Conn
+	framing uint32
+	codec   byte
+	stream  uint32
+	// only used by the reading goroutine
+	partial     map[uint32]*partialReq
+	partialSize int
+}
+
+// partialReq is a request whose frames are not all read yet.
+type partialReq struct {
+	codec byte
+	data  []byte
+}
+
+func newFrameConn(conn net.Conn, sess *session) *frameConn {
+	framing, codec := framingOf(sess.caps)
+	return &frameConn{Conn: conn, framing: framing, codec: codec, partial: make(map[uint32]*partialReq)}
+}
+
+func compress(codec byte, data []byte) ([]byte, error) {
+	switch codec {
+	case codecSnappy:
+		return snappy.Encode(nil, data), nil
+	case codecDeflate:
+		buf := new(bytes.Buffer)
+		w, err := flate.NewWriter(buf, flate.BestSpeed)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return data, nil
+}
+
+// decompress decompresses data, requests larger than MaxRequestSize are refused.
+func decompress(codec byte, data []byte) ([]byte, error) {
+	switch codec {
+	case codecNone:
+		return data, nil
+	case codecSnappy:
+		n, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, ErrFrame
+		}
+		if n > int(MaxRequestSize) {
+			return nil, ErrMsgTooLarge
+		}
+		out, err := snappy.Decode(nil, data)
+		if err != nil {
+			return nil, ErrFrame
+		}
+		return out, nil
+	case codecDeflate:
+		r := flate.NewReader(bytes.NewReader(data))
+		defer r.Close()
+		out, err := ioutil.ReadAll(io.LimitReader(r, int64(MaxRequestSize)+1))
+		if err != nil {
+			return nil, ErrFrame
+		}
+		if len(out) > int(MaxRequestSize) {
+			return nil, ErrMsgTooLarge
+		}
+		return out, nil
+	}
+	return nil, ErrFrame
+}
+
+// packFrames returns the frames of pack, a packed request, as stream. pack is compressed with
+// codec if that makes it smaller.
+func packFrames(pack []byte, codec byte, stream uint32) [][]byte {
+	payload := pack
+	if codec != codecNone && len(pack) >= CompressMinSize {
+		if c, err := compress(codec, pack); err == nil && len(c) < len(pack) {
+			payload = c
+			frameSavedBytes.Add(float64(len(pack) - len(c)))
+		} else {
+			codec = codecNone
+		}
+	} else {
+		codec = codecNone
+	}
+	frames := make([][]byte, 0, len(payload)/FrameChunkSize+1)
+	for {
+		n := len(payload)
+		flags := codec
+		if n > FrameChunkSize {
+			n = FrameChunkSize
+			flags |= frameMore
+		}
+		frame := make([]byte, frameHeadLen+n)
+		copy(frame, NetVersionV2[:])
+		frame[4] = flags
+		binary.BigEndian.PutUint32(frame[5:], stream)
+		binary.BigEndian.PutUint32(frame[9:], uint32(n))
+		binary.BigEndian.PutUint32(frame[13:], crc32.Checksum(payload[:n], crcTable))
+		copy(frame[frameHeadLen:], payload[:n])
+		frames = append(frames, frame)
+		payload = payload[n:]
+		if flags&frameMore == 0 {
+			return frames
+		}
+	}
+}
+
+// writeFrames sends pack in framing v2, each frame is written at once so that frames of
+// requests sent concurrently interleave.
+func (fc *frameConn) writeFrames(pack []byte) error {
+	stream := atomic.AddUint32(&fc.stream, 1)
+	for _, frame := range packFrames(pack, fc.codec, stream) {
+		fc.SetWriteDeadline(time.Now().Add(WriteTimeout))
+		if _, err := fc.Write(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFrame reads a frame of framing v2 whose magic is read already. It returns the packed
+// request once its last frame is read, nil before.
+func (fc *frameConn) readFrame() ([]byte, error) {
+	head := make([]byte, frameHeadLen-4)
+	if _, err := io.ReadFull(fc.Conn, head); err != nil {
+		return nil, err
+	}
+	flags := head[0]
+	stream := binary.BigEndian.Uint32(head[1:])
+	length := binary.BigEndian.Uint32(head[5:])
+	sum := binary.BigEndian.Uint32(head[9:])
+	if length > uint32(FrameChunkSize) {
+		return nil, ErrMsgTooLarge
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(fc.Conn, payload); err != nil {
+		return nil, err
+	}
+	if crc32.Checksum(payload, crcTable) != sum {
+		return nil, ErrFrameChecksum
+	}
+	codec := flags &^ frameMore
+	p, ok := fc.partial[stream]
+	if !ok {
+		if len(fc.partial) >= maxPartialStreams {
+			return nil, ErrFrame
+		}
+		p = &partialReq{codec: codec}
+	} else if p.codec != codec {
+		return nil, ErrFrame
+	}
+	if len(p.data)+len(payload) > int(MaxRequestSize) || fc.partialSize+len(payload) > MaxPartialSize {
+		return nil, ErrMsgTooLarge
+	}
+	p.data = append(p.data, payload...)
+	if flags&frameMore != 0 {
+		fc.partial[stream] = p
+		fc.partialSize += len(payload)
+		return nil, nil
+	}
+	// the frames read before this one were buffered
+	fc.partialSize -= len(p.data) - len(payload)
+	delete(fc.partial, stream)
+	pack, err := decompress(p.codec, p.data)
+	if err != nil {
+		return nil, err
+	}
+	// the request within is in v1 framing
+	if len(pack) < 8 || !isNetVersionMatch(pack) || int(binary.BigEndian.Uint32(pack[4:8]))+8 != len(pack) {
+		return nil, ErrFrame
+	}
+	return pack, nil
+}
@@ -0,0 +1,330 @@
+package network
+
+import (
+	"container/list"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/common"
+	"github.com/iost-official/Go-IOS-Protocol/core/message"
+	"github.com/iost-official/Go-IOS-Protocol/network/discover"
+)
+
+// gossip parameters
+var (
+	// GossipMaxTTL is the number of hops a broadcast message travels at most
+	GossipMaxTTL int8 = 6
+	// DefaultGossipFanout is the number of neighbours a message is gossiped to if not configured
+	DefaultGossipFanout = 8
+	// Messages with a body of at least LazyPushSize are pushed to GossipEagerFanout of the
+	// neighbours only, the others are told the message id and ask for the message if they lack it
+	LazyPushSize      = 32 << 10
+	GossipEagerFanout = 3
+
+	GossipSeenCacheSize = 50000 // ids of the messages seen
+	GossipMsgCacheSize  = 64    // lazily pushed messages kept to answer for
+	// GossipWantTimeout is how long a message asked for is waited for before it is asked from
+	// another peer
+	GossipWantTimeout = 2 * time.Second
+)
+
+// lruCache is a map of bounded size, the least recently used entries are evicted.
+type lruCache struct {
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{size: size, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+// add puts key, it returns false if key was already there.
+func (c *lruCache) add(key string, value interface{}) bool {
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*lruEntry).value = value
+		return false
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value})
+	if c.ll.Len() > c.size {
+		last := c.ll.Back()
+		c.ll.Remove(last)
+		delete(c.items, last.Value.(*lruEntry).key)
+	}
+	return true
+}
+
+func (c *lruCache) get(key string) (interface{}, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+func (c *lruCache) contains(key string) bool {
+	_, ok := c.items[key]
+	return ok
+}
+
+func (c *lruCache) len() int {
+	return c.ll.Len()
+}
+
+// MessageID is the id of a broadcast message, the same at every hop.
+func MessageID(msg message.Message) []byte {
+	msg.From, msg.To, msg.TTL = "", "", 0
+	data, _ := msg.Marshal(nil)
+	return common.Sha256(data)
+}
+
+// isRelayedOnceValid tells whether messages of reqType are relayed by their consumer once it
+// validated them rather than on receipt, so that no honest node relays an invalid block or tx.
+func isRelayedOnceValid(reqType int32) bool {
+	switch ReqType(reqType) {
+	case ReqNewBlock, ReqCompactBlock, ReqPublishTx:
+		return true
+	}
+	return false
+}
+
+// isAnnouncement tells whether messages of reqType only reach neighbours, every node announces
+// what it has itself.
+func isAnnouncement(reqType int32) bool {
+	return reqType == int32(ReqDownloadBlock) || reqType == int32(ReqTxHashes)
+}
+
+// gossip remembers the messages seen so that each is handled and relayed once, and the
+// lazily pushed ones to send them to the peers asking for them.
+type gossip struct {
+	mu     sync.Mutex
+	fanout int
+	seen   *lruCache // id -> the peer it was first received from, empty if sent by the local node
+	msgs   *lruCache // id -> message.Message
+	wanted *lruCache // id -> time asked for
+}
+
+func newGossip(fanout int) *gossip {
+	if fanout <= 0 {
+		fanout = DefaultGossipFanout
+	}
+	return &gossip{
+		fanout: fanout,
+		seen:   newLRUCache(GossipSeenCacheSize),
+		msgs:   newLRUCache(GossipMsgCacheSize),
+		wanted: newLRUCache(GossipSeenCacheSize),
+	}
+}
+
+// markSeen records id, received from hop or sent by the local node if hop is empty. It returns
+// false if it was already seen.
+func (g *gossip) markSeen(id []byte, hop string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.seen.get(string(id)); ok {
+		return false
+	}
+	return g.seen.add(string(id), hop)
+}
+
+// hopOf returns the peer id was first received from, empty if it was not received.
+func (g *gossip) hopOf(id []byte) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	v, ok := g.seen.get(string(id))
+	if !ok {
+		return ""
+	}
+	return v.(string)
+}
+
+func (g *gossip) hasSeen(id []byte) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.seen.contains(string(id))
+}
+
+// store keeps a lazily pushed message.
+func (g *gossip) store(id []byte, msg message.Message) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.msgs.add(string(id), msg)
+}
+
+func (g *gossip) load(id []byte) (message.Message, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	v, ok := g.msgs.get(string(id))
+	if !ok {
+		return message.Message{}, false
+	}
+	return v.(message.Message), true
+}
+
+// want tells whether id, announced by a peer, should be asked for: it is not seen and not
+// asked for within GossipWantTimeout.
+func (g *gossip) want(id []byte) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.seen.contains(string(id)) {
+		return false
+	}
+	if v, ok := g.wanted.get(string(id)); ok && time.Since(v.(time.Time)) < GossipWantTimeout {
+		return false
+	}
+	g.wanted.add(string(id), time.Now())
+	return true
+}
+
+// gossipTargets returns the neighbours to gossip msg to, but the one it came from, in random
+// order. Announcements go to every neighbour, other messages to fanout of them.
+func (bn *BaseNetwork) gossipTargets(msg message.Message) (targets []string, neighbours int) {
+	from, _ := discover.ParseNode(msg.From)
+	bn.neighbours.Range(func(k, v interface{}) bool {
+		node := v.(*discover.Node)
+		neighbours++
+		// msg is not sent back to the node it came from
+		if from != nil && node.ID == from.ID {
+			return true
+		}
+		targets = append(targets, node.String())
+		return true
+	})
+	rand.Shuffle(len(targets), func(i, j int) {
+		targets[i], targets[j] = targets[j], targets[i]
+	})
+	if !isAnnouncement(msg.ReqType) && len(targets) > bn.gossip.fanout {
+		targets = targets[:bn.gossip.fanout]
+	}
+	return targets, neighbours
+}
+
+// receiveGossip records a broadcast message received from hop, it returns false if it is a
+// duplicate.
+func (bn *BaseNetwork) receiveGossip(msg message.Message, hop string) bool {
+	if !bn.gossip.markSeen(MessageID(msg), hop) {
+		gossipDuplicateCount.Inc()
+		return false
+	}
+	gossipReceivedCount.Inc()
+	return true
+}
+
+// Relay gossips msg, a broadcast message received and validated by its consumer, on. Messages
+// which were not received by broadcast are not relayed.
+func (bn *BaseNetwork) Relay(msg message.Message) {
+	if bn.gossip.hopOf(MessageID(msg)) == "" {
+		return
+	}
+	bn.Broadcast(msg)
+}
+
+// Sender returns the peer msg was received from: the sender of a message sent to the local node,
+// or the peer which relayed a broadcast message, never its claimed origin.
+func (bn *BaseNetwork) Sender(msg message.Message) string {
+	if hop := bn.gossip.hopOf(MessageID(msg)); hop != "" {
+		return hop
+	}
+	return msg.From
+}
+
+// PenalizeSender penalizes the peer msg was received from, see Sender.
+func (bn *BaseNetwork) PenalizeSender(msg message.Message, penalty int) {
+	bn.Penalize(bn.Sender(msg), penalty)
+}
+
+// ihave tells to the id of a message of reqType, to asks for the message if it lacks it.
+func (bn *BaseNetwork) ihave(to string, id []byte, reqType int32) {
+	peer, err := bn.dial(to)
+	if err != nil {
+		bn.log.E("[net] ihave dial tcp got err:%v", err)
+		bn.deleteNode(to)
+		return
+	}
+	if !peer.caps.has(reqType) {
+		return
+	}
+	data, _ := (&MsgIDs{ReqType: reqType, IDs: [][]byte{id}}).Marshal(nil)
+	if !peer.send(newRequest(GossipIHave, bn.localNode.String(), data), reqType) {
+		return
+	}
+	gossipIHaveCount.Inc()
+}
+
+func decodeMsgIDs(b []byte) (ids *MsgIDs, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid message ids: %v", r)
+		}
+	}()
+	ids = &MsgIDs{}
+	_, err = ids.Unmarshal(b)
+	return ids, err
+}
+
+// handleIHave asks the peer which sent r for the announced messages not seen yet. The request is
+// queued like the messages of their type, so that it does not hold up the receive loop.
+func (bn *BaseNetwork) handleIHave(r *Request) {
+	ids, err := decodeMsgIDs(r.Body)
+	if err != nil {
+		bn.Penalize(string(r.From), PenaltyBadMsg)
+		return
+	}
+	want := &MsgIDs{}
+	for _, id := range ids.IDs {
+		if bn.gossip.want(id) {
+			want.IDs = append(want.IDs, id)
+		}
+	}
+	if len(want.IDs) == 0 {
+		return
+	}
+	want.ReqType = ids.ReqType
+	data, _ := want.Marshal(nil)
+	peer, err := bn.dial(string(r.From))
+	if err != nil {
+		bn.log.E("[net] iwant dial tcp got err:%v", err)
+		return
+	}
+	if peer.send(newRequest(GossipIWant, bn.localNode.String(), data), want.ReqType) {
+		gossipIWantCount.Add(float64(len(want.IDs)))
+	}
+}
+
+// handleIWant queues the messages asked for by the peer which sent r for it.
+func (bn *BaseNetwork) handleIWant(r *Request) {
+	ids, err := decodeMsgIDs(r.Body)
+	if err != nil {
+		bn.Penalize(string(r.From), PenaltyBadMsg)
+		return
+	}
+	peer, err := bn.dial(string(r.From))
+	if err != nil {
+		bn.log.E("[net] iwant dial tcp got err:%v", err)
+		return
+	}
+	for _, id := range ids.IDs {
+		msg, ok := bn.gossip.load(id)
+		if !ok || !peer.caps.has(msg.ReqType) {
+			continue
+		}
+		msg.To = string(r.From)
+		data, err := msg.Marshal(nil)
+		if err != nil {
+			continue
+		}
+		if !peer.send(newRequest(BroadcastMessage, bn.localNode.String(), data), msg.ReqType) {
+			continue
+		}
+		prometheusSendBlockTx(msg)
+	}
+}
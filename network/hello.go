@@ -0,0 +1,236 @@
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/network/discover"
+)
+
+// protocol versions
+const (
+	ProtocolVersion    uint32 = 1
+	MinProtocolVersion uint32 = 1
+)
+
+// HelloTimeout bounds the hello handshake of a new connection.
+var HelloTimeout = 5 * time.Second
+
+// LocalCaps are the capabilities the local node speaks: message types, then the framing and the
+// compressions it reads.
+var LocalCaps = []Cap{
+	{Name: "tx", Version: 1},
+	{Name: "block", Version: 1},
+	{Name: "sync", Version: 1},
+	{Name: "snap", Version: 1},
+	{Name: "frame", Version: 2},
+	{Name: "snappy", Version: 1},
+	{Name: "deflate", Version: 1},
+}
+
+// capOfReqType is the capability every ReqType belongs to, types without one are always spoken.
+var capOfReqType = map[ReqType]string{
+	ReqPublishTx:      "tx",
+	ReqTxHashes:       "tx",
+	ReqTxByHash:       "tx",
+	ReqBlockHeight:    "block",
+	RecvBlockHeight:   "block",
+	ReqNewBlock:       "block",
+	ReqCompactBlock:   "block",
+	ReqBlockTxs:       "block",
+	RecvBlockTxs:      "block",
+	ReqDownloadBlock:  "sync",
+	BlockHashQuery:    "sync",
+	BlockHashResponse: "sync",
+	ReqSyncBlock:      "sync",
+	ReqBlockHeaders:   "sync",
+	RecvBlockHeaders:  "sync",
+
+	ReqSnapshotManifest:  "snap",
+	RecvSnapshotManifest: "snap",
+	ReqSnapshotChunk:     "snap",
+	RecvSnapshotChunk:    "snap",
+}
+
+// RegisterCap puts types under the capability name, messages of them are only exchanged with
+// peers which negotiated it.
+func RegisterCap(name string, types ...ReqType) {
+	for _, t := range types {
+		capOfReqType[t] = name
+	}
+}
+
+// capSet is the negotiated capabilities with a peer and their versions, nil allows every message.
+type capSet map[string]uint32
+
+// has tells whether messages of reqType can be exchanged.
+func (c capSet) has(reqType int32) bool {
+	if c == nil {
+		return true
+	}
+	name, ok := capOfReqType[ReqType(reqType)]
+	if !ok {
+		return true
+	}
+	_, ok = c[name]
+	return ok
+}
+
+// negotiateCaps returns the capabilities both sides speak, each at the lower of both versions.
+func negotiateCaps(local, remote []Cap) capSet {
+	caps := make(capSet)
+	for _, l := range local {
+		for _, r := range remote {
+			if l.Name != r.Name {
+				continue
+			}
+			v := l.Version
+			if r.Version < v {
+				v = r.Version
+			}
+			if v > caps[l.Name] {
+				caps[l.Name] = v
+			}
+		}
+	}
+	return caps
+}
+
+// session is what the hello handshake agreed on with a peer.
+type session struct {
+	version uint32
+	caps    capSet
+}
+
+// Error implements error.
+func (d discReason) Error() string {
+	if int(d) < len(discReasonToString) && discReasonToString[d] != "" {
+		return discReasonToString[d]
+	}
+	return fmt.Sprintf("unknown disconnect reason %d", uint(d))
+}
+
+// localHello is the hello of the local node.
+func (bn *BaseNetwork) localHello() *Hello {
+	h := &Hello{
+		Version: ProtocolVersion,
+		Caps:    LocalCaps,
+		ChainID: bn.chainID,
+		NodeID:  string(bn.localNode.ID),
+	}
+	if bn.chainInfo != nil {
+		h.Genesis, h.HeadHeight = bn.chainInfo()
+	}
+	return h
+}
+
+// checkHello checks the hello of remote against the local node, it returns the agreed version and
+// capabilities, or why they are incompatible.
+func (bn *BaseNetwork) checkHello(h *Hello, remote *discover.Node) (uint32, capSet, error) {
+	if discover.NodeID(h.NodeID) != remote.ID {
+		return 0, nil, DiscUnexpectedIdentity
+	}
+	version := ProtocolVersion
+	if h.Version < version {
+		version = h.Version
+	}
+	if version < MinProtocolVersion {
+		return 0, nil, DiscIncompatibleVersion
+	}
+	local := bn.localHello()
+	if h.ChainID != local.ChainID {
+		return 0, nil, DiscIncompatibleChain
+	}
+	if len(h.Genesis) > 0 && len(local.Genesis) > 0 && !bytes.Equal(h.Genesis, local.Genesis) {
+		return 0, nil, DiscIncompatibleChain
+	}
+	caps := negotiateCaps(local.Caps, h.Caps)
+	if len(caps) == 0 {
+		return 0, nil, DiscUselessPeer
+	}
+	return version, caps, nil
+}
+
+func decodeHello(b []byte) (h *Hello, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid hello: %v", r)
+		}
+	}()
+	h = &Hello{}
+	_, err = h.Unmarshal(b)
+	return h, err
+}
+
+// decodeDisconnect returns the reason of a disconnect request.
+func decodeDisconnect(b []byte) discReason {
+	d := &Disconnect{}
+	if len(b) < 4 {
+		return DiscProtocolError
+	}
+	d.Unmarshal(b)
+	return discReason(d.Reason)
+}
+
+// disconnect tells the peer on conn why it is disconnected and closes conn.
+func (bn *BaseNetwork) disconnect(conn net.Conn, reason discReason) {
+	data, _ := (&Disconnect{Reason: uint32(reason)}).Marshal(nil)
+	bn.send(conn, newRequest(ReqDisconnect, bn.localNode.String(), data))
+	conn.Close()
+}
+
+// hello exchanges hellos on a new connection with remote, incompatible peers are disconnected.
+// The head height told by the peer is recorded.
+func (bn *BaseNetwork) hello(conn net.Conn, remote *discover.Node) (*session, error) {
+	data, err := bn.localHello().Marshal(nil)
+	if err != nil {
+		return nil, err
+	}
+	pack, err := newRequest(ReqHello, bn.localNode.String(), data).Pack()
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(HelloTimeout))
+	defer conn.SetDeadline(time.Time{})
+	werr := make(chan error, 1)
+	go func() {
+		_, err := conn.Write(pack)
+		werr <- err
+	}()
+
+	buf, err := bn.readMsg(conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := <-werr; err != nil {
+		return nil, err
+	}
+	req := new(Request)
+	if err := req.Unpack(bytes.NewReader(buf)); err != nil {
+		return nil, err
+	}
+	switch req.Type {
+	case ReqHello:
+	case ReqDisconnect:
+		return nil, decodeDisconnect(req.Body)
+	default:
+		bn.disconnect(conn, DiscProtocolError)
+		return nil, DiscProtocolError
+	}
+	h, err := decodeHello(req.Body)
+	if err != nil {
+		bn.disconnect(conn, DiscProtocolError)
+		return nil, DiscProtocolError
+	}
+	version, caps, err := bn.checkHello(h, remote)
+	if err != nil {
+		bn.disconnect(conn, err.(discReason))
+		return nil, err
+	}
+	if h.HeadHeight > 0 {
+		bn.SetNodeHeightMap(remote.String(), h.HeadHeight)
+	}
+	return &session{version: version, caps: caps}, nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func main() {
-	mode := flag.String("mode", "public", "operation mode: private | public")
+	mode := flag.String("mode", "public", "operation mode: private | public | permissioned")
 	flag.Parse()
 	fmt.Println("[WARNING] Running in " + *mode + " mode. ")
 	network.NetMode = *mode
@@ -33,12 +33,12 @@ type ipInfo struct {
 }
 
 func bootnodeStart() {
-	node, err := discover.ParseNode("84a8ecbeeb6d3f676da1b261c35c7cd15ae17f32b659a6f5ce7be2d60f6c16f9@0.0.0.0:30304")
+	node, err := discover.ParseNode("0.0.0.0:30304")
 	if err != nil {
 		fmt.Printf("parse boot node got err:%v\n", err)
 	}
 	conf := initNetConf()
-	conf.NodeID = string(node.ID)
+	conf.NodeKeyPath = "/tmp/bootnode.key"
 	baseNet, err := network.NewBaseNetwork(conf)
 	if err != nil {
 		fmt.Println("NewBaseNetwork ", err)
@@ -49,7 +49,7 @@ func bootnodeStart() {
 		fmt.Println("Init ", err)
 		return
 	}
-	fmt.Println("server starting", node.Addr())
+	fmt.Println("server starting", baseNet.LocalNode())
 	for {
 		select {
 		case <-ch:
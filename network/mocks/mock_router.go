@@ -147,3 +147,105 @@ func (m *MockRouter) QueryBlockHash(start, end uint64) error {
 func (mr *MockRouterMockRecorder) QueryBlockHash(start, end interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlockHash", reflect.TypeOf((*MockRouter)(nil).QueryBlockHash), start, end)
 }
+
+// NeighbourNum mocks base method
+func (m *MockRouter) NeighbourNum() int {
+	ret := m.ctrl.Call(m, "NeighbourNum")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// NeighbourNum indicates an expected call of NeighbourNum
+func (mr *MockRouterMockRecorder) NeighbourNum() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeighbourNum", reflect.TypeOf((*MockRouter)(nil).NeighbourNum))
+}
+
+// PeerHeights mocks base method
+func (m *MockRouter) PeerHeights() map[string]uint64 {
+	ret := m.ctrl.Call(m, "PeerHeights")
+	ret0, _ := ret[0].(map[string]uint64)
+	return ret0
+}
+
+// PeerHeights indicates an expected call of PeerHeights
+func (mr *MockRouterMockRecorder) PeerHeights() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerHeights", reflect.TypeOf((*MockRouter)(nil).PeerHeights))
+}
+
+// Penalize mocks base method
+func (m *MockRouter) Penalize(nodeStr string, penalty int) {
+	m.ctrl.Call(m, "Penalize", nodeStr, penalty)
+}
+
+// Penalize indicates an expected call of Penalize
+func (mr *MockRouterMockRecorder) Penalize(nodeStr, penalty interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Penalize", reflect.TypeOf((*MockRouter)(nil).Penalize), nodeStr, penalty)
+}
+
+// PenalizeSender mocks base method
+func (m *MockRouter) PenalizeSender(msg message.Message, penalty int) {
+	m.ctrl.Call(m, "PenalizeSender", msg, penalty)
+}
+
+// PenalizeSender indicates an expected call of PenalizeSender
+func (mr *MockRouterMockRecorder) PenalizeSender(msg, penalty interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PenalizeSender", reflect.TypeOf((*MockRouter)(nil).PenalizeSender), msg, penalty)
+}
+
+// Sender mocks base method
+func (m *MockRouter) Sender(msg message.Message) string {
+	ret := m.ctrl.Call(m, "Sender", msg)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Sender indicates an expected call of Sender
+func (mr *MockRouterMockRecorder) Sender(msg interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sender", reflect.TypeOf((*MockRouter)(nil).Sender), msg)
+}
+
+// Relay mocks base method
+func (m *MockRouter) Relay(msg message.Message) {
+	m.ctrl.Call(m, "Relay", msg)
+}
+
+// Relay indicates an expected call of Relay
+func (mr *MockRouterMockRecorder) Relay(msg interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Relay", reflect.TypeOf((*MockRouter)(nil).Relay), msg)
+}
+
+// AddPeer mocks base method
+func (m *MockRouter) AddPeer(list, entry string) error {
+	ret := m.ctrl.Call(m, "AddPeer", list, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPeer indicates an expected call of AddPeer
+func (mr *MockRouterMockRecorder) AddPeer(list, entry interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPeer", reflect.TypeOf((*MockRouter)(nil).AddPeer), list, entry)
+}
+
+// RemovePeer mocks base method
+func (m *MockRouter) RemovePeer(list, entry string) error {
+	ret := m.ctrl.Call(m, "RemovePeer", list, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePeer indicates an expected call of RemovePeer
+func (mr *MockRouterMockRecorder) RemovePeer(list, entry interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePeer", reflect.TypeOf((*MockRouter)(nil).RemovePeer), list, entry)
+}
+
+// PeerLists mocks base method
+func (m *MockRouter) PeerLists() map[string][]string {
+	ret := m.ctrl.Call(m, "PeerLists")
+	ret0, _ := ret[0].(map[string][]string)
+	return ret0
+}
+
+// PeerLists indicates an expected call of PeerLists
+func (mr *MockRouterMockRecorder) PeerLists() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerLists", reflect.TypeOf((*MockRouter)(nil).PeerLists))
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -32,6 +33,7 @@ const (
 	RegisterServerPort      = 30304
 	PublicMode              = "public"
 	CommitteeMode           = "committee"
+	PermissionedMode        = "permissioned" // only allowed peers are connected, discovery is off
 	RndBcastThreshold       = 0.5
 )
 
@@ -54,14 +56,32 @@ type Network interface {
 type NetConfig struct {
 	LogPath       string
 	NodeTablePath string
-	NodeID        string
-	ListenAddr    string
-	RegisterAddr  string
+	// NodeKeyPath is the file of the node key, the node id is its public key. An empty path makes
+	// the node use a new key every start
+	NodeKeyPath string
+	ListenAddr  string
+	// BootNodes are the nodes to join the discovery network through, as "id@ip:port"
+	BootNodes []string
+	// RegisterAddr is the optional register server asked for its node table
+	RegisterAddr string
+	// ChainID and ChainInfo tell peers which chain the node is on, peers on other chains are
+	// disconnected. ChainInfo returns the genesis hash, empty if unknown, and the head height
+	ChainID   int64
+	ChainInfo func() ([]byte, uint64)
+	// GossipFanout is the number of neighbours broadcast messages are relayed to, 0 is
+	// DefaultGossipFanout
+	GossipFanout int
+	// StaticNodes are always connected, as "id@ip:port". TrustedNodes, node ids, are exempt from
+	// the rate limits and bans. In PermissionedMode only StaticNodes, TrustedNodes and
+	// AllowedPeers, node ids or CIDRs, are connected
+	StaticNodes  []string
+	TrustedNodes []string
+	AllowedPeers []string
 }
 
 // BaseNetwork maintains all node table, and distributes the node table to all node.
 type BaseNetwork struct {
-	nodeTable     *db.LDBDatabase //all known node except remoteAddr
+	nodeTable     *db.LDBDatabase //all known node except remoteAddr, by node id
 	neighbours    *sync.Map
 	lock          sync.Mutex
 	peers         peerSet // manage all connection
@@ -70,6 +90,16 @@ type BaseNetwork struct {
 	RecentSent    *sync.Map
 	NodeHeightMap map[string]uint64 //maintain all height of nodes higher than current height
 	localNode     *discover.Node
+	nodeKey       ed25519.PrivateKey
+	table         *discover.Table
+	bootnodes     []*discover.Node
+	chainID       int64
+	chainInfo     func() ([]byte, uint64)
+	rep           *reputation
+	gossip        *gossip
+	lists         *peerLists
+	quit          chan struct{}
+	stopOnce      sync.Once
 
 	DownloadHeights *sync.Map //map[height]retry_times
 	regAddr         string
@@ -100,14 +130,35 @@ func NewBaseNetwork(conf *NetConfig) (*BaseNetwork, error) {
 		return nil, fmt.Errorf("failed to init db %v", err)
 	}
 	NodeHeightMap := make(map[string]uint64, 0)
-	if conf.NodeID == "" {
-		conf.NodeID = string(discover.GenNodeID())
+	var nodeKey ed25519.PrivateKey
+	if conf.NodeKeyPath != "" {
+		nodeKey, err = discover.LoadNodeKey(conf.NodeKeyPath)
+	} else {
+		nodeKey, err = discover.GenNodeKey()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to init node key %v", err)
+	}
+	bootnodes, err := discover.ParseNodes(conf.BootNodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse boot nodes %v", err)
 	}
-	localNode := &discover.Node{ID: discover.NodeID(conf.NodeID), IP: net.ParseIP(conf.ListenAddr)}
+	lists, err := newPeerLists(conf.StaticNodes, conf.TrustedNodes, conf.AllowedPeers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse peer lists %v", err)
+	}
+	localNode := &discover.Node{ID: discover.PubkeyID(nodeKey.Public().(ed25519.PublicKey)), IP: net.ParseIP(conf.ListenAddr)}
 	s := &BaseNetwork{
 		nodeTable:       nodeTable,
 		RecvCh:          recv,
 		localNode:       localNode,
+		nodeKey:         nodeKey,
+		bootnodes:       bootnodes,
+		chainID:         conf.ChainID,
+		chainInfo:       conf.ChainInfo,
+		rep:             newReputation(),
+		gossip:          newGossip(conf.GossipFanout),
+		lists:           lists,
 		neighbours:      new(sync.Map),
 		log:             srvLog,
 		NodeHeightMap:   NodeHeightMap,
@@ -115,7 +166,9 @@ func NewBaseNetwork(conf *NetConfig) (*BaseNetwork, error) {
 		regAddr:         conf.RegisterAddr,
 		RecentSent:      new(sync.Map),
 		NodeAddedTime:   new(sync.Map),
+		quit:            make(chan struct{}),
 	}
+	s.loadPeerLists()
 	return s, nil
 }
 
@@ -138,13 +191,37 @@ func (bn *BaseNetwork) Listen(port uint16) (<-chan message.Message, error) {
 		for {
 			conn, err := bn.listener.Accept()
 			if err != nil {
+				select {
+				case <-bn.quit:
+					return
+				default:
+				}
 				bn.log.E("[net] accept downStream node err:%v", err)
 				time.Sleep(2 * time.Second)
 				continue
 			}
-			go bn.receiveLoop(conn)
+			go bn.accept(conn)
 		}
 	}()
+	// the discovery table feeds the node table. It is off in PermissionedMode, where the nodes
+	// are the ones listed and the node is not to be found by others
+	if NetMode != PermissionedMode {
+		bn.table, err = discover.ListenUDP(&discover.Config{
+			Key:       bn.nodeKey,
+			Self:      bn.localNode,
+			Bootnodes: bn.bootnodes,
+			OnAdd: func(n *discover.Node) {
+				bn.putNode(n.String())
+			},
+			OnRemove: func(n *discover.Node) {
+				bn.deleteNode(n.String())
+				bn.findNeighbours()
+			},
+		})
+		if err != nil {
+			return bn.RecvCh, fmt.Errorf("failed to listen udp, err = %v", err)
+		}
+	}
 	//register
 	if bn.localNode.TCP == RegisterServerPort {
 		go bn.nodeCheckLoop()
@@ -152,44 +229,107 @@ func (bn *BaseNetwork) Listen(port uint16) (<-chan message.Message, error) {
 		go bn.registerLoop()
 		go bn.recentSentLoop()
 	}
+	for _, n := range bn.lists.staticNodes() {
+		bn.putNode(n.String())
+	}
+	go bn.staticLoop()
 	return bn.RecvCh, nil
 }
 
-// Broadcast broadcasts msg to all node in the node table.
+// accept authenticates an inbound connection and reads from it.
+func (bn *BaseNetwork) accept(conn net.Conn) {
+	sconn, remote, err := handshake(conn, bn.nodeKey, bn.localNode.TCP, false, "")
+	if err != nil {
+		bn.log.D("[net] handshake with %v got err:%v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+	if remote.ID == bn.localNode.ID {
+		bn.disconnect(sconn, DiscSelf)
+		return
+	}
+	if bn.IsBanned(remote.ID) {
+		bn.disconnect(sconn, DiscBanned)
+		return
+	}
+	if !bn.lists.allowed(remote) {
+		bn.disconnect(sconn, DiscNotAllowed)
+		return
+	}
+	sess, err := bn.hello(sconn, remote)
+	if err != nil {
+		bn.log.D("[net] hello with %v got err:%v", remote, err)
+		sconn.Close()
+		return
+	}
+	bn.receiveLoop(newFrameConn(sconn, sess), remote, sess.caps)
+}
+
+// LocalNode returns the local node, its id is the public key of the node key.
+func (bn *BaseNetwork) LocalNode() *discover.Node {
+	return bn.localNode
+}
+
+// isLocal tells whether nodeStr is the local node, by id or by address.
+func (bn *BaseNetwork) isLocal(nodeStr string) bool {
+	node, err := discover.ParseNode(nodeStr)
+	if err != nil {
+		return false
+	}
+	return node.ID == bn.localNode.ID || node.Addr() == bn.localNode.Addr()
+}
+
+// Broadcast gossips msg to fanout random neighbours. Large messages are pushed to a few of them
+// and announced to the others, which ask for them if they lack them.
 func (bn *BaseNetwork) Broadcast(msg message.Message) {
 	if msg.From == "" {
-		msg.From = bn.localNode.Addr()
+		msg.From = bn.localNode.String()
 	}
-	from := msg.From
-
-	bn.neighbours.Range(func(k, v interface{}) bool {
-		node := v.(*discover.Node)
-		if node.Addr() == from {
-			return false
-		}
-		msg.To = node.Addr()
-		bn.log.D("[net] broad msg: type= %v, from=%v,to=%v,time=%v, to node: %v", msg.ReqType, msg.From, msg.To, msg.Time, node.Addr())
+	id := MessageID(msg)
+	bn.gossip.markSeen(id, "")
+	targets, neighbours := bn.gossipTargets(msg)
+	if len(targets) == 0 {
+		return
+	}
+	eager, lazy := targets, []string(nil)
+	if len(msg.Body) >= LazyPushSize && msg.TTL > 0 && len(targets) > GossipEagerFanout {
+		eager, lazy = targets[:GossipEagerFanout], targets[GossipEagerFanout:]
+		stored := msg
+		stored.TTL--
+		bn.gossip.store(id, stored)
+	}
+	for _, to := range eager {
+		msg.To = to
+		bn.log.D("[net] broad msg: type= %v, from=%v,to=%v,time=%v", msg.ReqType, msg.From, msg.To, msg.Time)
 		if !bn.isRecentSent(msg) {
 			bn.broadcast(msg)
 			prometheusSendBlockTx(msg)
 		}
-		return true
-	})
+	}
+	for _, to := range lazy {
+		bn.ihave(to, id, msg.ReqType)
+	}
+	// the sender has msg too
+	covered := len(targets)
+	if neighbours > len(targets) {
+		covered++
+	}
+	gossipCoverage.Observe(float64(covered) / float64(neighbours))
 }
 
 func (bn *BaseNetwork) randomBroadcast(msg message.Message) {
 	if msg.From == "" {
-		msg.From = bn.localNode.Addr()
+		msg.From = bn.localNode.String()
 	}
-	from := msg.From
+	from, _ := discover.ParseNode(msg.From)
 
 	targetAddrs := make([]string, 0)
 	bn.neighbours.Range(func(k, v interface{}) bool {
 		node := v.(*discover.Node)
-		if node.Addr() == from {
-			return false
+		if from != nil && node.ID == from.ID {
+			return true
 		}
-		targetAddrs = append(targetAddrs, node.Addr())
+		targetAddrs = append(targetAddrs, node.String())
 		return true
 	})
 	if len(targetAddrs) == 0 {
@@ -212,8 +352,7 @@ func (bn *BaseNetwork) broadcast(msg message.Message) {
 	if msg.To == "" {
 		return
 	}
-	node, _ := discover.ParseNode(msg.To)
-	if msg.TTL == 0 || bn.localNode.Addr() == node.Addr() {
+	if msg.TTL == 0 || bn.isLocal(msg.To) {
 		return
 	}
 	msg.TTL = msg.TTL - 1
@@ -221,74 +360,115 @@ func (bn *BaseNetwork) broadcast(msg message.Message) {
 	if err != nil {
 		bn.log.E("[net] marshal request encountered err:%v", err)
 	}
-	req := newRequest(BroadcastMessage, bn.localNode.Addr(), data)
+	req := newRequest(BroadcastMessage, bn.localNode.String(), data)
 	peer, err := bn.dial(msg.To)
 	if err != nil {
 		bn.log.E("[net] broadcast dial tcp got err:%v", err)
-		bn.nodeTable.Delete([]byte(msg.To))
-		bn.NodeAddedTime.Delete(msg.To)
+		bn.deleteNode(msg.To)
 		return
 	}
-	if msg.ReqType == int32(ReqSyncBlock) || msg.ReqType == int32(ReqNewBlock) {
-		if er := bn.send(peer.blockConn, req); er != nil {
-			bn.log.E("[net] block conn sent error:%v", err)
-			bn.peers.RemoveByNodeStr(msg.To)
-		}
-	} else {
-		if er := bn.send(peer.conn, req); er != nil {
-			bn.log.E("[net] normal conn sent error:%v", err)
-			bn.peers.RemoveByNodeStr(msg.To)
-		}
+	if !peer.caps.has(msg.ReqType) {
+		return
+	}
+	if !peer.send(req, msg.ReqType) {
+		bn.log.D("[net] send queue of %v full, dropped a msg of type %v", msg.To, msg.ReqType)
 	}
 }
 
+// dial returns the peer of nodeStr, connecting to it if needed. nodeStr may omit the node id, then
+// whichever node answers at the address is accepted
 func (bn *BaseNetwork) dial(nodeStr string) (*Peer, error) {
 	bn.lock.Lock()
 	defer bn.lock.Unlock()
-	node, _ := discover.ParseNode(nodeStr)
-	if bn.localNode.Addr() == node.Addr() {
+	node, err := discover.ParseNode(nodeStr)
+	if err != nil {
+		return nil, err
+	}
+	if bn.isLocal(nodeStr) {
 		return nil, fmt.Errorf("dial local %v", node.Addr())
 	}
+	if node.ID != "" && bn.IsBanned(node.ID) {
+		return nil, fmt.Errorf("dial banned %v", node)
+	}
+	if node.ID != "" && !bn.lists.allowed(node) {
+		return nil, fmt.Errorf("dial %v: %v", node, ErrNotAllowed)
+	}
 	peer := bn.peers.Get(node)
 	if peer == nil {
 		bn.log.D("[net] dial to %v", node.Addr())
-		conn, blockConn, err := dial(node.Addr())
+		conn, remote, sess, err := bn.dialSecure(node)
 		if err != nil {
 			bn.log.E("failed to dial %v", err)
 			return nil, err
 		}
-		go bn.receiveLoop(conn)
-		go bn.receiveLoop(blockConn)
-		peer := newPeer(conn, blockConn, bn.localNode.Addr(), nodeStr)
-		bn.peers.Set(node, peer)
+		blockConn, _, _, err := bn.dialSecure(remote)
+		if err != nil {
+			conn.Close()
+			bn.log.E("failed to dial %v", err)
+			return nil, err
+		}
+		go bn.receiveLoop(conn, remote, sess.caps)
+		go bn.receiveLoop(blockConn, remote, sess.caps)
+		peer = bn.newPeer(conn, blockConn, remote)
+		peer.version, peer.caps = sess.version, sess.caps
+		bn.peers.Set(remote, peer)
 	}
 
-	return bn.peers.Get(node), nil
+	return peer, nil
+}
+
+// newPeer returns the peer remote on conn and blockConn, it is removed once a request can not be
+// written to it.
+func (bn *BaseNetwork) newPeer(conn, blockConn net.Conn, remote *discover.Node) *Peer {
+	var peer *Peer
+	onError := func() {
+		if bn.peers.Get(remote) == peer {
+			bn.peers.Remove(remote)
+		}
+	}
+	peer = newPeer(conn, blockConn, bn.localNode.String(), remote, bn.send, onError)
+	return peer
 }
 
-func dial(nodeAddr string) (net.Conn, net.Conn, error) {
-	conn, err := net.Dial("tcp4", nodeAddr)
+// dialSecure opens an authenticated connection to node and says hello, node.ID is checked unless
+// it is empty.
+func (bn *BaseNetwork) dialSecure(node *discover.Node) (net.Conn, *discover.Node, *session, error) {
+	conn, err := net.Dial("tcp4", node.Addr())
 	if err != nil {
 		if conn != nil {
 			conn.Close()
 		}
-		log.Report(&log.MsgNode{SubType: log.Subtypes["MsgNode"][2], Log: nodeAddr})
-		return nil, nil, fmt.Errorf("dial tcp %v got err:%v", nodeAddr, err)
+		log.Report(&log.MsgNode{SubType: log.Subtypes["MsgNode"][2], Log: node.Addr()})
+		return nil, nil, nil, fmt.Errorf("dial tcp %v got err:%v", node.Addr(), err)
 	}
-	blockConn, err := net.Dial("tcp4", nodeAddr)
+	sconn, remote, err := handshake(conn, bn.nodeKey, bn.localNode.TCP, true, node.ID)
 	if err != nil {
-		if blockConn != nil {
-			blockConn.Close()
-		}
-		log.Report(&log.MsgNode{SubType: log.Subtypes["MsgNode"][2], Log: nodeAddr})
-		return nil, nil, fmt.Errorf("dial tcp %v got err:%v", nodeAddr, err)
+		conn.Close()
+		return nil, nil, nil, fmt.Errorf("handshake with %v got err:%v", node.Addr(), err)
+	}
+	if remote.ID == bn.localNode.ID {
+		sconn.Close()
+		return nil, nil, nil, fmt.Errorf("dial local %v", node.Addr())
+	}
+	if bn.IsBanned(remote.ID) {
+		bn.disconnect(sconn, DiscBanned)
+		return nil, nil, nil, fmt.Errorf("dial banned %v", remote)
 	}
-	return conn, blockConn, nil
+	if !bn.lists.allowed(remote) {
+		bn.disconnect(sconn, DiscNotAllowed)
+		return nil, nil, nil, fmt.Errorf("dial %v: %v", remote, ErrNotAllowed)
+	}
+	sess, err := bn.hello(sconn, remote)
+	if err != nil {
+		sconn.Close()
+		return nil, nil, nil, fmt.Errorf("hello with %v got err:%v", node.Addr(), err)
+	}
+	return newFrameConn(sconn, sess), remote, sess, nil
 }
 
 // Send sends msg to msg.To.
 func (bn *BaseNetwork) Send(msg message.Message) {
-	if msg.To == bn.localNode.Addr() || msg.To == "" {
+	if msg.To == "" || bn.isLocal(msg.To) {
 		return
 	}
 	data, err := msg.Marshal(nil)
@@ -296,23 +476,20 @@ func (bn *BaseNetwork) Send(msg message.Message) {
 		bn.log.E("[net] marshal request encountered err:%v", err)
 	}
 	bn.log.D("[net] send msg: type= %v, from=%v,to=%v,time=%v", msg.ReqType, msg.From, msg.To, msg.Time)
-	req := newRequest(Message, bn.localNode.Addr(), data)
+	req := newRequest(Message, bn.localNode.String(), data)
 	peer, err := bn.dial(msg.To)
 	if err != nil {
-		bn.nodeTable.Delete([]byte(msg.To))
-		bn.NodeAddedTime.Delete(msg.To)
+		bn.deleteNode(msg.To)
 		bn.log.E("[net] Send, dial tcp got err:%v", err)
 		return
 	}
+	if !peer.caps.has(msg.ReqType) {
+		bn.log.D("[net] %v does not speak msg type %v", msg.To, msg.ReqType)
+		return
+	}
 
-	if msg.ReqType == int32(ReqSyncBlock) || msg.ReqType == int32(ReqNewBlock) {
-		if er := bn.send(peer.blockConn, req); er != nil {
-			bn.peers.RemoveByNodeStr(msg.To)
-		}
-	} else {
-		if er := bn.send(peer.conn, req); er != nil {
-			bn.peers.RemoveByNodeStr(msg.To)
-		}
+	if !peer.send(req, msg.ReqType) {
+		bn.log.D("[net] send queue of %v full, dropped a msg of type %v", msg.To, msg.ReqType)
 	}
 
 	prometheusSendBlockTx(msg)
@@ -323,9 +500,26 @@ func (bn *BaseNetwork) Close(port uint16) error {
 	if bn.listener != nil {
 		bn.listener.Close()
 	}
+	if bn.table != nil {
+		bn.table.Close()
+	}
 	return nil
 }
 
+// Stop stops the loops of the network, disconnects the peers and closes the node table.
+func (bn *BaseNetwork) Stop() {
+	bn.stopOnce.Do(func() {
+		close(bn.quit)
+		bn.Close(bn.localNode.TCP)
+		for _, p := range bn.peers.list() {
+			if node, err := discover.ParseNode(p.remote); err == nil {
+				bn.peers.Remove(node)
+			}
+		}
+		bn.nodeTable.Close()
+	})
+}
+
 func (bn *BaseNetwork) send(conn net.Conn, r *Request) error {
 	if conn == nil {
 		bn.log.E("[net] from %v,send data = %v, conn is nil", bn.localNode.Addr(), r)
@@ -336,15 +530,37 @@ func (bn *BaseNetwork) send(conn net.Conn, r *Request) error {
 		bn.log.E("[net] pack data encountered err:%v", err)
 		return nil
 	}
+	if fc, ok := conn.(*frameConn); ok && fc.framing >= 2 {
+		if err = fc.writeFrames(pack); err != nil {
+			bn.log.E("[net] conn write got err:%v", err)
+			conn.Close()
+		}
+		return err
+	}
 
-	conn.SetWriteDeadline(time.Now().Add(800 * time.Millisecond))
-	_, err = conn.Write(pack)
-	if err != nil {
+	if err = writeChunked(conn, pack); err != nil {
 		bn.log.E("[net] conn write got err:%v", err)
 		conn.Close()
 	}
 	return err
 }
+
+// writeChunked writes pack in pieces of FrameChunkSize, each given WriteTimeout like a frame of
+// framing v2, so that large requests are not cut by the deadline of a small one.
+func writeChunked(conn net.Conn, pack []byte) error {
+	for len(pack) > 0 {
+		n := len(pack)
+		if n > FrameChunkSize {
+			n = FrameChunkSize
+		}
+		conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+		if _, err := conn.Write(pack[:n]); err != nil {
+			return err
+		}
+		pack = pack[n:]
+	}
+	return nil
+}
 func (bn *BaseNetwork) readMsg(conn net.Conn) ([]byte, error) {
 
 	for {
@@ -356,6 +572,21 @@ func (bn *BaseNetwork) readMsg(conn net.Conn) ([]byte, error) {
 			return nil, err
 		}
 
+		if bytes.Equal(revH, NetVersionV2[:]) {
+			fc, ok := conn.(*frameConn)
+			if !ok {
+				return nil, ErrFrame
+			}
+			pack, err := fc.readFrame()
+			if err != nil {
+				return nil, err
+			}
+			if pack == nil {
+				continue
+			}
+			return pack, nil
+		}
+
 		if !isNetVersionMatch(revH) {
 			return nil, errors.New("[net] Receive head error")
 		}
@@ -367,6 +598,10 @@ func (bn *BaseNetwork) readMsg(conn net.Conn) ([]byte, error) {
 		if err := binary.Read(bytes.NewReader(revL), binary.BigEndian, &length); err != nil {
 			return nil, err
 		}
+		// timestamp, type and from length come first
+		if length < 12 || length > MaxRequestSize {
+			return nil, ErrMsgTooLarge
+		}
 
 		rbuf := make([]byte, length+8)
 		var n int
@@ -395,7 +630,10 @@ func (bn *BaseNetwork) readMsg(conn net.Conn) ([]byte, error) {
 	}
 
 }
-func (bn *BaseNetwork) receiveLoop(conn net.Conn) {
+
+// receiveLoop handles requests read from conn, which is authenticated as remote. Requests are
+// attributed to remote whatever address they declare
+func (bn *BaseNetwork) receiveLoop(conn net.Conn, remote *discover.Node, caps capSet) {
 
 	defer func() {
 		if conn != nil {
@@ -408,33 +646,81 @@ func (bn *BaseNetwork) receiveLoop(conn net.Conn) {
 		buf, err := bn.readMsg(conn)
 		if err != nil {
 			log.Log.E("[net] readMsg error:%v", err)
+			switch err {
+			case ErrMsgTooLarge:
+				bn.Penalize(remote.String(), PenaltyOversize)
+			case ErrFrame, ErrFrameChecksum:
+				bn.Penalize(remote.String(), PenaltyBadMsg)
+			}
 			return
 		}
+		if !bn.lists.isTrusted(remote.ID) && !bn.rep.allow(remote.ID, len(buf)) {
+			bn.Penalize(remote.String(), PenaltyRateLimit)
+			continue
+		}
 
 		req := new(Request)
 		if err := req.Unpack(bytes.NewReader(buf)); err != nil {
 			log.Log.E("[net] req.Unpack error")
+			bn.Penalize(remote.String(), PenaltyBadMsg)
 			continue
 		}
+		req.From = []byte(remote.String())
+
+		req.handle(bn, conn, caps)
+
+	}
 
-		req.handle(bn, conn)
+}
+
+// encodeNodeRecord makes the node table record of node, its live cycle followed by its address.
+func encodeNodeRecord(node *discover.Node, live int) []byte {
+	return append(common.IntToBytes(live), []byte(node.Addr())...)
+}
 
+// decodeNodeRecord parses the node table record of id.
+func decodeNodeRecord(id, record []byte) (*discover.Node, int, error) {
+	if len(record) < 4 {
+		return nil, 0, fmt.Errorf("invalid node record of %s", id)
+	}
+	node, err := discover.ParseNode(string(id) + "@" + string(record[4:]))
+	if err != nil {
+		return nil, 0, err
 	}
+	return node, common.BytesToInt(record[:4]), nil
+}
 
+// deleteNode forgets nodeStr, which can not be reached.
+func (bn *BaseNetwork) deleteNode(nodeStr string) {
+	node, err := discover.ParseNode(nodeStr)
+	if err != nil || node.ID == "" || bn.lists.isStatic(node.ID) {
+		return
+	}
+	bn.nodeTable.Delete([]byte(node.ID))
+	bn.NodeAddedTime.Delete(string(node.ID))
 }
 
-// AllNodesExcludeAddr returns all the known node in the network.
+// AllNodesExcludeAddr returns all the known node in the network but excludeAddr, which is either
+// a node or an address.
 func (bn *BaseNetwork) AllNodesExcludeAddr(excludeAddr string) ([]string, error) {
 	if bn.nodeTable == nil {
 		return nil, nil
 	}
+	exclude, _ := discover.ParseNode(excludeAddr)
 	addrs := make([]string, 0)
 	iter := bn.nodeTable.NewIterator()
 	for iter.Next() {
-		addr := string(iter.Key())
-		if addr != excludeAddr {
-			addrs = append(addrs, addr)
+		if isBanKey(iter.Key()) || isPeerListKey(iter.Key()) {
+			continue
 		}
+		node, _, err := decodeNodeRecord(iter.Key(), iter.Value())
+		if err != nil {
+			continue
+		}
+		if exclude != nil && (node.ID == exclude.ID || node.Addr() == exclude.Addr()) {
+			continue
+		}
+		addrs = append(addrs, node.String())
 	}
 	iter.Release()
 	if err := iter.Error(); err != nil {
@@ -444,7 +730,7 @@ func (bn *BaseNetwork) AllNodesExcludeAddr(excludeAddr string) ([]string, error)
 	return addrs, nil
 }
 
-// putnode puts node into node table of server.
+// putnode puts nodes into node table of server, nodes without a valid id are ignored.
 func (bn *BaseNetwork) putNode(addrs string) {
 	if addrs == "" {
 		return
@@ -456,15 +742,14 @@ func (bn *BaseNetwork) putNode(addrs string) {
 			bn.log.E("failed to ParseNode  %v,err: %v", addr, err)
 			continue
 		}
-		if addr != "" && addr != bn.localNode.Addr() {
-			_, err := bn.nodeTable.Has([]byte(addr))
-			if err != nil {
-				bn.log.E("failed to nodetable has %v, err: %v", addr, err)
-				continue
-			}
-			bn.nodeTable.Put([]byte(node.Addr()), common.IntToBytes(NodeLiveCycle))
-			if _, exist := bn.NodeAddedTime.Load(node.Addr()); !exist {
-				bn.NodeAddedTime.Store(node.Addr(), time.Now().Unix())
+		if _, err := node.ID.Pubkey(); err != nil {
+			bn.log.E("failed to ParseNode  %v,err: %v", addr, err)
+			continue
+		}
+		if !bn.isLocal(addr) && !bn.IsBanned(node.ID) && bn.lists.allowed(node) {
+			bn.nodeTable.Put([]byte(node.ID), encodeNodeRecord(node, NodeLiveCycle))
+			if _, exist := bn.NodeAddedTime.Load(string(node.ID)); !exist {
+				bn.NodeAddedTime.Store(string(node.ID), time.Now().Unix())
 			}
 		}
 	}
@@ -479,15 +764,20 @@ func (bn *BaseNetwork) nodeCheckLoop() {
 			iter := bn.nodeTable.NewIterator()
 			for iter.Next() {
 				k := iter.Key()
-				v := common.BytesToInt(iter.Value())
-				if v <= 0 {
-					bn.log.D("[net] delete node %v, cuz its last register time is %v", string(iter.Key()), common.BytesToInt64(iter.Value()))
-					bn.nodeTable.Delete(iter.Key())
-					bn.peers.RemoveByNodeStr(string(iter.Key()))
-					bn.neighbours.Delete(string(iter.Key()))
-					bn.NodeAddedTime.Delete(string(iter.Key()))
+				if isBanKey(k) || isPeerListKey(k) {
+					continue
+				}
+				node, v, err := decodeNodeRecord(k, iter.Value())
+				if err != nil || v <= 0 {
+					bn.log.D("[net] delete node %v, cuz its live cycle is %v", string(k), v)
+					bn.nodeTable.Delete(k)
+					if node != nil {
+						bn.peers.Remove(node)
+						bn.neighbours.Delete(node.String())
+					}
+					bn.NodeAddedTime.Delete(string(k))
 				} else {
-					bn.nodeTable.Put(k, common.IntToBytes(v-1))
+					bn.nodeTable.Put(k, encodeNodeRecord(node, v-1))
 				}
 			}
 			time.Sleep(CheckKnownNodeInterval * time.Second)
@@ -507,7 +797,7 @@ func (bn *BaseNetwork) registerLoop() {
 				continue
 			}
 			bn.log.D("[net] %v request node table from %v", bn.localNode.Addr(), bn.regAddr)
-			req := newRequest(ReqNodeTable, bn.localNode.Addr(), nil)
+			req := newRequest(ReqNodeTable, bn.localNode.String(), nil)
 			if er := bn.send(peer.conn, req); er != nil {
 				bn.peers.RemoveByNodeStr(bn.regAddr)
 			}
@@ -518,7 +808,7 @@ func (bn *BaseNetwork) registerLoop() {
 
 // findNeighbours finds neighbour nodes in the node table.
 func (bn *BaseNetwork) findNeighbours() {
-	nodesStr, _ := bn.AllNodesExcludeAddr(bn.localNode.Addr())
+	nodesStr, _ := bn.AllNodesExcludeAddr(bn.localNode.String())
 	nodes := make([]*discover.Node, 0)
 	for _, nodeStr := range nodesStr {
 		node, _ := discover.ParseNode(nodeStr)
@@ -534,6 +824,20 @@ func (bn *BaseNetwork) findNeighbours() {
 	for _, n := range neighbours {
 		bn.neighbours.Store(n.String(), n)
 	}
+	// static peers are always neighbours
+	for _, n := range bn.lists.staticNodes() {
+		bn.neighbours.Store(n.String(), n)
+	}
+}
+
+// NeighbourNum returns the number of neighbour nodes.
+func (bn *BaseNetwork) NeighbourNum() int {
+	num := 0
+	bn.neighbours.Range(func(k, v interface{}) bool {
+		num++
+		return true
+	})
+	return num
 }
 
 // AskABlock asks a node for a block.
@@ -541,7 +845,7 @@ func (bn *BaseNetwork) AskABlock(height uint64, to string) error {
 	msg := message.Message{
 		Body:    common.Uint64ToBytes(height),
 		ReqType: int32(ReqDownloadBlock),
-		From:    bn.localNode.Addr(),
+		From:    bn.localNode.String(),
 		Time:    time.Now().UnixNano(),
 		To:      to,
 	}
@@ -561,7 +865,7 @@ func (bn *BaseNetwork) QueryBlockHash(start, end uint64) error {
 		Body:    bytes,
 		ReqType: int32(BlockHashQuery),
 		TTL:     1, //BlockHashQuery req just broadcast to its neibour
-		From:    bn.localNode.Addr(),
+		From:    bn.localNode.String(),
 		Time:    time.Now().UnixNano(),
 	}
 	bn.log.D("[net] query block hash. start=%v, end=%v, from=%v", start, end, bn.localNode.Addr())
@@ -591,7 +895,7 @@ func (bn *BaseNetwork) Download(start, end uint64) error {
 				Body:    common.Uint64ToBytes(downloadHeight),
 				ReqType: int32(ReqDownloadBlock),
 				TTL:     MsgMaxTTL,
-				From:    bn.localNode.Addr(),
+				From:    bn.localNode.String(),
 				Time:    time.Now().UnixNano(),
 			}
 			bn.log.D("[net] download height = %v  nodeMap = %v", downloadHeight, bn.NodeHeightMap)
@@ -631,6 +935,17 @@ func (bn *BaseNetwork) GetNodeHeightMap(nodeStr string) uint64 {
 	return bn.NodeHeightMap[nodeStr]
 }
 
+// PeerHeights returns a copy of the known heights of nodes.
+func (bn *BaseNetwork) PeerHeights() map[string]uint64 {
+	bn.lock.Lock()
+	defer bn.lock.Unlock()
+	heights := make(map[string]uint64, len(bn.NodeHeightMap))
+	for k, v := range bn.NodeHeightMap {
+		heights[k] = v
+	}
+	return heights
+}
+
 func randNodeMatchHeight(m map[string]uint64, downloadHeight uint64) (targetNode string) {
 	rand.Seed(time.Now().UnixNano())
 	matchNum := 1
@@ -688,7 +1003,7 @@ func (bn *BaseNetwork) sendNodeTable(from []byte, conn net.Conn) {
 	if err != nil {
 		bn.log.E("[net] failed to get node table, %v", err)
 	}
-	req := newRequest(NodeTable, bn.localNode.Addr(), []byte(strings.Join(addrs, ",")))
+	req := newRequest(NodeTable, bn.localNode.String(), []byte(strings.Join(addrs, ",")))
 	if er := bn.send(conn, req); er != nil {
 		bn.log.E("[net] failed to send node table,%v ", err)
 		conn.Close()
@@ -696,13 +1011,23 @@ func (bn *BaseNetwork) sendNodeTable(from []byte, conn net.Conn) {
 	return
 }
 
+// isBlockReq tells whether reqType is sent over the block connection.
+func isBlockReq(reqType int32) bool {
+	switch ReqType(reqType) {
+	case ReqSyncBlock, ReqNewBlock, ReqCompactBlock, RecvBlockTxs:
+		return true
+	}
+	return false
+}
+
 func prometheusSendBlockTx(req message.Message) {
 	if req.ReqType == int32(ReqPublishTx) {
 		// sendTransactionSize.Observe(float64(req.Size()))
 		sendTransactionCount.Inc()
 	}
-	if req.ReqType == int32(ReqNewBlock) {
+	if req.ReqType == int32(ReqNewBlock) || req.ReqType == int32(ReqCompactBlock) {
 		// sendBlockSize.Observe(float64(req.Size()))
 		sendBlockCount.Inc()
+		sendBlockBytes.Add(float64(len(req.Body)))
 	}
 }
@@ -1,11 +1,17 @@
 package network
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net"
 	"os"
 	"testing"
 	"time"
 
-	"github.com/iost-official/Go-IOS-Protocol/common"
 	"github.com/iost-official/Go-IOS-Protocol/core/message"
 	"github.com/iost-official/Go-IOS-Protocol/network/discover"
 	. "github.com/smartystreets/goconvey/convey"
@@ -14,6 +20,7 @@ import (
 func TestBaseNetwork_AllNodesExcludeAddr(t *testing.T) {
 	Convey("AllNodesExcludeAddr", t, func() {
 		baseNet, _ := NewBaseNetwork(&NetConfig{RegisterAddr: registerAddr, ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_"})
+		defer baseNet.Stop()
 		iter := baseNet.nodeTable.NewIterator()
 		for iter.Next() {
 			baseNet.nodeTable.Delete(iter.Key())
@@ -21,33 +28,57 @@ func TestBaseNetwork_AllNodesExcludeAddr(t *testing.T) {
 		iter.Release()
 		So(iter.Error(), ShouldBeNil)
 
-		baseNet.nodeTable.Put([]byte(registerAddr), common.IntToBytes(2))
+		node, _ := discover.ParseNode(testNode(registerAddr))
+		baseNet.nodeTable.Put([]byte(node.ID), encodeNodeRecord(node, 2))
 
 		arr, err := baseNet.AllNodesExcludeAddr("")
 		So(err, ShouldBeNil)
 		So(len(arr), ShouldEqual, 1)
-		So(arr[0], ShouldEqual, registerAddr)
+		So(arr[0], ShouldEqual, node.String())
 
 		arr2, err := baseNet.AllNodesExcludeAddr(registerAddr)
 		So(err, ShouldBeNil)
 		So(len(arr2), ShouldEqual, 0)
+
+		arr3, err := baseNet.AllNodesExcludeAddr(node.String())
+		So(err, ShouldBeNil)
+		So(len(arr3), ShouldEqual, 0)
 	})
 }
 
 var registerAddr = "127.0.0.1:30304"
 
+// testNode returns a node string at addr with a new node id.
+// testNode returns a node listening on addr, its key is derived from addr so that tests see the
+// same node ids on every run
+func testNode(addr string) string {
+	seed := sha256.Sum256([]byte(addr))
+	key := ed25519.NewKeyFromSeed(seed[:])
+	return string(discover.PubkeyID(key.Public().(ed25519.PublicKey))) + "@" + addr
+}
+
+// testKeyPath stores the node key derived from name and returns its path, for NodeKeyPath
+func testKeyPath(name string) string {
+	seed := sha256.Sum256([]byte(name))
+	path := "iost_node_key_" + name
+	ioutil.WriteFile(path, []byte(hex.EncodeToString(seed[:])), 0600)
+	return path
+}
+
 func cleanLDB() {
 	os.RemoveAll("iost_db_")
 	os.RemoveAll("iost_db_1")
 	os.RemoveAll("iost_db_2")
 	os.RemoveAll("iost_db_2")
 	os.RemoveAll("iost_node_table_")
+	os.RemoveAll("iost_node_key_local")
 }
 
 func TestBaseNetwork_recentSentLoop(t *testing.T) {
 	Convey("recentSentLoop", t, func() {
 		cleanLDB()
 		baseNet, _ := NewBaseNetwork(&NetConfig{RegisterAddr: registerAddr, ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_"})
+		defer baseNet.Stop()
 		baseNet.RecentSent.Store("test_expired", time.Now().Add(-(MsgLiveThresholdSeconds+1)*time.Second))
 		baseNet.RecentSent.Store("test_not_expired", time.Now())
 		go func() {
@@ -66,6 +97,7 @@ func TestBaseNetwork_isRecentSent(t *testing.T) {
 	Convey("isRecentSent", t, func() {
 		cleanLDB()
 		baseNet, _ := NewBaseNetwork(&NetConfig{RegisterAddr: registerAddr, ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_"})
+		defer baseNet.Stop()
 		msg := message.Message{From: "sender", Time: time.Now().UnixNano(), To: "192.168.1.34:20003", Body: []byte{22, 11, 125}, TTL: 2}
 		is := baseNet.isRecentSent(msg)
 		So(is, ShouldBeFalse)
@@ -82,22 +114,23 @@ func TestBaseNetwork_isRecentSent(t *testing.T) {
 }
 
 var addresses = []string{
-	"127.0.0.1:30301",
-	"127.0.0.1:30302",
-	"127.0.0.1:30303",
-	"127.0.0.1:30305",
-	"127.0.0.1:30306",
-	"127.0.0.1:30307",
-	"127.0.0.1:30308",
-	"127.0.0.1:30309",
-	"19.192.22.23:30310",
-	"18.192.22.23:30311",
+	testNode("127.0.0.1:30301"),
+	testNode("127.0.0.1:30302"),
+	testNode("127.0.0.1:30303"),
+	testNode("127.0.0.1:30305"),
+	testNode("127.0.0.1:30306"),
+	testNode("127.0.0.1:30307"),
+	testNode("127.0.0.1:30308"),
+	testNode("127.0.0.1:30309"),
+	testNode("19.192.22.23:30310"),
+	testNode("18.192.22.23:30311"),
 }
 
 func TestBaseNetwork_findNeighbours(t *testing.T) {
 	Convey("findNeighbours", t, func() {
 		cleanLDB()
-		bn, _ := NewBaseNetwork(&NetConfig{RegisterAddr: "127.0.0.1:30304", ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_"})
+		bn, _ := NewBaseNetwork(&NetConfig{RegisterAddr: "127.0.0.1:30304", ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_", NodeKeyPath: testKeyPath("local")})
+		defer bn.Stop()
 		for _, addr := range addresses {
 			bn.putNode(addr)
 		}
@@ -107,17 +140,22 @@ func TestBaseNetwork_findNeighbours(t *testing.T) {
 			return true
 		})
 		So(neighbourLen, ShouldEqual, discover.MaxNeighbourNum)
-		_, ok1 := bn.neighbours.Load("@" + addresses[7])
+		// the farthest nodes from the local key
+		_, ok1 := bn.neighbours.Load(addresses[5])
 		So(ok1, ShouldBeFalse)
-		_, ok2 := bn.neighbours.Load("@" + addresses[6])
+		_, ok2 := bn.neighbours.Load(addresses[2])
 		So(ok2, ShouldBeFalse)
 
 		bn.neighbours.Range(func(k, v interface{}) bool {
 			node := v.(*discover.Node)
 			bn.neighbours.Delete(node.String())
-			bn.nodeTable.Delete([]byte(node.Addr()))
+			bn.nodeTable.Delete([]byte(node.ID))
 			return true
 		})
+		for _, addr := range addresses {
+			node, _ := discover.ParseNode(addr)
+			bn.nodeTable.Delete([]byte(node.ID))
+		}
 
 		neighbourLen = 0
 		bn.neighbours.Range(func(k, v interface{}) bool {
@@ -133,25 +171,464 @@ func TestBaseNetwork_findNeighbours(t *testing.T) {
 func TestBaseNetwork_putNode(t *testing.T) {
 	Convey("putNode", t, func() {
 		cleanLDB()
-		bn, _ := NewBaseNetwork(&NetConfig{RegisterAddr: "127.0.0.1:30304", ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_"})
+		bn, _ := NewBaseNetwork(&NetConfig{RegisterAddr: "127.0.0.1:30304", ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_", NodeKeyPath: testKeyPath("local")})
+		defer bn.Stop()
 
+		node0, _ := discover.ParseNode(addresses[0])
 		bn.putNode(addresses[0])
-		b, err := bn.nodeTable.Get([]byte(addresses[0]))
+		b, err := bn.nodeTable.Get([]byte(node0.ID))
+		So(err, ShouldBeNil)
+		node, live, err := decodeNodeRecord([]byte(node0.ID), b)
 		So(err, ShouldBeNil)
-		So(common.BytesToInt(b), ShouldEqual, NodeLiveCycle)
+		So(live, ShouldEqual, NodeLiveCycle)
+		So(node.String(), ShouldEqual, addresses[0])
 
 		bn.putNode(addresses[0])
-		b, err = bn.nodeTable.Get([]byte(addresses[0]))
+		b, err = bn.nodeTable.Get([]byte(node0.ID))
 		So(err, ShouldBeNil)
-		So(common.BytesToInt(b), ShouldEqual, NodeLiveCycle)
+		_, live, _ = decodeNodeRecord([]byte(node0.ID), b)
+		So(live, ShouldEqual, NodeLiveCycle)
 
-		b, err = bn.nodeTable.Get([]byte(addresses[1]))
+		node1, _ := discover.ParseNode(addresses[1])
+		b, err = bn.nodeTable.Get([]byte(node1.ID))
 		So(err, ShouldNotBeNil)
-		So(common.BytesToInt(b), ShouldEqual, 0)
+
+		bn.putNode("127.0.0.1:30312")
+		arr, _ := bn.AllNodesExcludeAddr("")
+		So(len(arr), ShouldEqual, 1)
+		cleanLDB()
+	})
+}
+
+func TestHandshake(t *testing.T) {
+	Convey("handshake", t, func() {
+		key1, _ := discover.GenNodeKey()
+		key2, _ := discover.GenNodeKey()
+		id1 := discover.PubkeyID(key1.Public().(ed25519.PublicKey))
+		id2 := discover.PubkeyID(key2.Public().(ed25519.PublicKey))
+
+		Convey("authenticates both sides and encrypts", func() {
+			c1, c2 := net.Pipe()
+			type result struct {
+				conn net.Conn
+				node *discover.Node
+				err  error
+			}
+			ch := make(chan result)
+			go func() {
+				conn, node, err := handshake(c2, key2, 30302, false, "")
+				ch <- result{conn, node, err}
+			}()
+			conn1, node2, err := handshake(c1, key1, 30301, true, id2)
+			So(err, ShouldBeNil)
+			res := <-ch
+			So(res.err, ShouldBeNil)
+			So(node2.ID, ShouldEqual, id2)
+			So(node2.TCP, ShouldEqual, 30302)
+			So(res.node.ID, ShouldEqual, id1)
+			So(res.node.TCP, ShouldEqual, 30301)
+
+			go conn1.Write([]byte("hello"))
+			buf := make([]byte, 5)
+			_, err = io.ReadFull(res.conn, buf)
+			So(err, ShouldBeNil)
+			So(string(buf), ShouldEqual, "hello")
+		})
+
+		Convey("rejects an unexpected identity", func() {
+			c1, c2 := net.Pipe()
+			go handshake(c2, key2, 30302, false, "")
+			_, _, err := handshake(c1, key1, 30301, true, id1)
+			So(err, ShouldEqual, ErrUnexpectedIdentity)
+			c1.Close()
+			c2.Close()
+		})
+	})
+}
+
+func TestHello(t *testing.T) {
+	Convey("hello", t, func() {
+		cleanLDB()
+		bn1, _ := NewBaseNetwork(&NetConfig{ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_1", ChainID: 1024,
+			ChainInfo: func() ([]byte, uint64) { return []byte("genesis"), 10 }})
+		defer bn1.Stop()
+		bn2, _ := NewBaseNetwork(&NetConfig{ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_2", ChainID: 1024})
+		defer bn2.Stop()
+		hello := bn1.localHello()
+		So(hello.HeadHeight, ShouldEqual, 10)
+
+		Convey("negotiates version and capabilities", func() {
+			hello.Version = ProtocolVersion + 1
+			hello.Caps = []Cap{{Name: "tx", Version: 2}, {Name: "state", Version: 1}}
+			version, caps, err := bn2.checkHello(hello, bn1.localNode)
+			So(err, ShouldBeNil)
+			So(version, ShouldEqual, ProtocolVersion)
+			So(len(caps), ShouldEqual, 1)
+			So(caps["tx"], ShouldEqual, 1)
+			So(caps.has(int32(ReqPublishTx)), ShouldBeTrue)
+			So(caps.has(int32(ReqNewBlock)), ShouldBeFalse)
+		})
+
+		Convey("rejects incompatible peers", func() {
+			_, _, err := bn2.checkHello(hello, bn2.localNode)
+			So(err, ShouldEqual, DiscUnexpectedIdentity)
+
+			hello.ChainID = 1
+			_, _, err = bn2.checkHello(hello, bn1.localNode)
+			So(err, ShouldEqual, DiscIncompatibleChain)
+
+			hello.ChainID = 1024
+			hello.Caps = []Cap{{Name: "state", Version: 1}}
+			_, _, err = bn2.checkHello(hello, bn1.localNode)
+			So(err, ShouldEqual, DiscUselessPeer)
+		})
+
+		Convey("exchanges hellos on a connection", func() {
+			c1, c2 := net.Pipe()
+			ch := make(chan error)
+			go func() {
+				_, err := bn2.hello(c2, bn1.localNode)
+				ch <- err
+			}()
+			sess, err := bn1.hello(c1, bn2.localNode)
+			So(err, ShouldBeNil)
+			So(<-ch, ShouldBeNil)
+			So(sess.version, ShouldEqual, ProtocolVersion)
+			So(len(sess.caps), ShouldEqual, len(LocalCaps))
+			So(bn2.GetNodeHeightMap(bn1.localNode.String()), ShouldEqual, 10)
+		})
+
+		Convey("disconnects peers on another chain", func() {
+			bn2.chainID = 1
+			c1, c2 := net.Pipe()
+			ch := make(chan error)
+			go func() {
+				_, err := bn2.hello(c2, bn1.localNode)
+				ch <- err
+			}()
+			_, err := bn1.hello(c1, bn2.localNode)
+			So(err, ShouldEqual, DiscIncompatibleChain)
+			So(<-ch, ShouldEqual, DiscIncompatibleChain)
+		})
+		cleanLDB()
+	})
+}
+
+func TestReputation(t *testing.T) {
+	Convey("reputation", t, func() {
+		cleanLDB()
+		bn, _ := NewBaseNetwork(&NetConfig{ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_"})
+		defer bn.Stop()
+		node, _ := discover.ParseNode(addresses[0])
+
+		Convey("limits the rate of a peer", func() {
+			b := newTokenBucket(1, 3)
+			So(b.take(2), ShouldBeTrue)
+			So(b.take(2), ShouldBeFalse)
+			So(b.take(1), ShouldBeTrue)
+
+			for i := 0; i < int(MsgBurst); i++ {
+				bn.rep.allow(node.ID, 1)
+			}
+			So(bn.rep.allow(node.ID, 1), ShouldBeFalse)
+		})
+
+		Convey("bans a peer once its score is low", func() {
+			bn.putNode(addresses[0])
+			bn.Penalize(addresses[0], PenaltyInvalidBlock)
+			So(bn.rep.score(node.ID), ShouldEqual, -PenaltyInvalidBlock)
+			So(bn.IsBanned(node.ID), ShouldBeFalse)
+
+			bn.Penalize(addresses[0], PenaltyInvalidBlock)
+			So(bn.IsBanned(node.ID), ShouldBeTrue)
+			So(bn.rep.score(node.ID), ShouldEqual, 0)
+			arr, _ := bn.AllNodesExcludeAddr("")
+			So(len(arr), ShouldEqual, 0)
+			bn.putNode(addresses[0])
+			arr, _ = bn.AllNodesExcludeAddr("")
+			So(len(arr), ShouldEqual, 0)
+			_, err := bn.dial(addresses[0])
+			So(err, ShouldNotBeNil)
+
+			bn.Ban(node, -time.Second)
+			So(bn.IsBanned(node.ID), ShouldBeFalse)
+		})
+
+		Convey("bounds the size of messages", func() {
+			So(MaxMsgSize(ReqNewBlock), ShouldBeGreaterThan, MaxMsgSize(ReqPublishTx))
+			req := newRequest(Message, "a", []byte("body"))
+			req.Length = 4
+			pack, _ := req.Pack()
+			So(new(Request).Unpack(bytes.NewReader(pack)), ShouldNotBeNil)
+		})
 		cleanLDB()
 	})
 }
 
+func TestGossip(t *testing.T) {
+	Convey("gossip", t, func() {
+		Convey("evicts the least recently used", func() {
+			c := newLRUCache(2)
+			So(c.add("a", 1), ShouldBeTrue)
+			So(c.add("b", 2), ShouldBeTrue)
+			So(c.add("a", 1), ShouldBeFalse)
+			So(c.add("c", 3), ShouldBeTrue)
+			So(c.len(), ShouldEqual, 2)
+			So(c.contains("b"), ShouldBeFalse)
+			v, ok := c.get("a")
+			So(ok, ShouldBeTrue)
+			So(v, ShouldEqual, 1)
+		})
+
+		Convey("keeps the id of a message over hops", func() {
+			msg := message.Message{Time: 1, From: "a", To: "b", ReqType: int32(ReqNewBlock), TTL: 3, Body: []byte("blk")}
+			relayed := msg
+			relayed.From, relayed.To, relayed.TTL = "b", "c", 2
+			So(MessageID(relayed), ShouldResemble, MessageID(msg))
+			msg.Body = []byte("other")
+			So(MessageID(relayed), ShouldNotResemble, MessageID(msg))
+		})
+
+		Convey("drops duplicates and bounds the fanout", func() {
+			cleanLDB()
+			bn, _ := NewBaseNetwork(&NetConfig{ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_", GossipFanout: 2})
+			defer bn.Stop()
+			msg := message.Message{Time: 1, ReqType: int32(ReqNewBlock), Body: []byte("blk")}
+			So(bn.receiveGossip(msg, addresses[1]), ShouldBeTrue)
+			So(bn.receiveGossip(msg, addresses[2]), ShouldBeFalse)
+			So(bn.gossip.hopOf(MessageID(msg)), ShouldEqual, addresses[1])
+			So(bn.gossip.want(MessageID(msg)), ShouldBeFalse)
+
+			id := []byte("id")
+			So(bn.gossip.want(id), ShouldBeTrue)
+			So(bn.gossip.want(id), ShouldBeFalse)
+
+			for _, addr := range addresses[:4] {
+				node, _ := discover.ParseNode(addr)
+				bn.neighbours.Store(string(node.ID), node)
+			}
+			msg.From = addresses[0]
+			targets, neighbours := bn.gossipTargets(msg)
+			So(neighbours, ShouldEqual, 4)
+			So(len(targets), ShouldEqual, 2)
+			So(targets, ShouldNotContain, addresses[0])
+			msg.ReqType = int32(ReqTxHashes)
+			targets, _ = bn.gossipTargets(msg)
+			So(len(targets), ShouldEqual, 3)
+			cleanLDB()
+		})
+
+		Convey("penalizes the relay of a message, not its origin", func() {
+			cleanLDB()
+			bn, _ := NewBaseNetwork(&NetConfig{ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_"})
+			defer bn.Stop()
+			origin, _ := discover.ParseNode(addresses[0])
+			hop, _ := discover.ParseNode(addresses[1])
+			msg := message.Message{Time: 1, From: addresses[0], ReqType: int32(ReqNewBlock), Body: []byte("blk")}
+			So(isRelayedOnceValid(msg.ReqType), ShouldBeTrue)
+			bn.receiveGossip(msg, addresses[1])
+			So(bn.Sender(msg), ShouldEqual, addresses[1])
+			bn.PenalizeSender(msg, PenaltyInvalidBlock)
+			So(bn.rep.score(hop.ID), ShouldEqual, -PenaltyInvalidBlock)
+			So(bn.rep.score(origin.ID), ShouldEqual, 0)
+
+			direct := message.Message{Time: 2, From: addresses[0], ReqType: int32(ReqSyncBlock), Body: []byte("blk")}
+			So(bn.Sender(direct), ShouldEqual, addresses[0])
+			bn.PenalizeSender(direct, PenaltyBadMsg)
+			So(bn.rep.score(origin.ID), ShouldEqual, -PenaltyBadMsg)
+			cleanLDB()
+		})
+
+		Convey("answers ihave and iwant through the send queues of the peer", func() {
+			cleanLDB()
+			bn, _ := NewBaseNetwork(&NetConfig{ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_"})
+			defer bn.Stop()
+			written := make(chan *Request, 4)
+			write := func(conn net.Conn, r *Request) error {
+				written <- r
+				return nil
+			}
+			node, _ := discover.ParseNode(addresses[1])
+			peer := &Peer{connQ: newSendQueue(nil, write, func() {}), blockQ: newSendQueue(nil, write, func() {})}
+			defer peer.stopQueues()
+			bn.peers.Set(node, peer)
+
+			msg := message.Message{Time: 1, ReqType: int32(ReqNewBlock), Body: []byte("blk")}
+			id := MessageID(msg)
+			data, _ := (&MsgIDs{ReqType: msg.ReqType, IDs: [][]byte{id}}).Marshal(nil)
+			bn.handleIHave(&Request{Type: GossipIHave, From: []byte(addresses[1]), Body: data})
+			r := <-written
+			So(r.Type, ShouldEqual, GossipIWant)
+			want, err := decodeMsgIDs(r.Body)
+			So(err, ShouldBeNil)
+			So(want.ReqType, ShouldEqual, msg.ReqType)
+			So(want.IDs, ShouldResemble, [][]byte{id})
+
+			bn.gossip.store(id, msg)
+			bn.handleIWant(&Request{Type: GossipIWant, From: []byte(addresses[1]), Body: data})
+			r = <-written
+			So(r.Type, ShouldEqual, BroadcastMessage)
+			cleanLDB()
+		})
+	})
+}
+
+func TestPeerLists(t *testing.T) {
+	Convey("peer lists", t, func() {
+		cleanLDB()
+		NetMode = PermissionedMode
+		defer func() { NetMode = "" }()
+		node0, _ := discover.ParseNode(addresses[0])
+		node1, _ := discover.ParseNode(addresses[1])
+		other, _ := discover.ParseNode(testNode("10.0.0.1:30301"))
+		bn, err := NewBaseNetwork(&NetConfig{ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_",
+			StaticNodes: addresses[:1], TrustedNodes: []string{string(node1.ID)}, AllowedPeers: []string{"127.0.0.0/8"}})
+		defer bn.Stop()
+		So(err, ShouldBeNil)
+
+		Convey("are parsed from the config", func() {
+			_, err := newPeerLists([]string{"127.0.0.1:30301"}, nil, nil)
+			So(err, ShouldNotBeNil)
+			_, err = newPeerLists(nil, nil, []string{"10.0.0.0/33"})
+			So(err, ShouldNotBeNil)
+			So(bn.AddPeer("unknown", addresses[2]), ShouldEqual, ErrUnknownPeerList)
+			lists := bn.PeerLists()
+			So(lists[StaticPeers], ShouldResemble, []string{addresses[0]})
+			So(lists[TrustedPeers], ShouldResemble, []string{string(node1.ID)})
+			So(lists[AllowedPeers], ShouldResemble, []string{"127.0.0.0/8"})
+		})
+
+		Convey("allow only the peers listed", func() {
+			So(bn.lists.allowed(node0), ShouldBeTrue)
+			So(bn.lists.allowed(other), ShouldBeFalse)
+			bn.putNode(other.String())
+			arr, _ := bn.AllNodesExcludeAddr("")
+			So(arr, ShouldNotContain, other.String())
+			_, err := bn.dial(other.String())
+			So(err, ShouldNotBeNil)
+
+			So(bn.AddPeer(AllowedPeers, string(other.ID)), ShouldBeNil)
+			So(bn.lists.allowed(other), ShouldBeTrue)
+			bn.putNode(other.String())
+			arr, _ = bn.AllNodesExcludeAddr("")
+			So(arr, ShouldContain, other.String())
+
+			So(bn.RemovePeer(AllowedPeers, string(other.ID)), ShouldBeNil)
+			arr, _ = bn.AllNodesExcludeAddr("")
+			So(arr, ShouldNotContain, other.String())
+
+			NetMode = PublicMode
+			So(bn.lists.allowed(other), ShouldBeTrue)
+		})
+
+		Convey("keep static peers and spare trusted ones", func() {
+			bn.findNeighbours()
+			_, ok := bn.neighbours.Load(addresses[0])
+			So(ok, ShouldBeTrue)
+			bn.putNode(addresses[0])
+			bn.deleteNode(addresses[0])
+			arr, _ := bn.AllNodesExcludeAddr("")
+			So(arr, ShouldContain, addresses[0])
+
+			for i := 0; i < 20; i++ {
+				bn.Penalize(addresses[1], PenaltyInvalidBlock)
+			}
+			So(bn.IsBanned(node1.ID), ShouldBeFalse)
+			So(bn.rep.score(node1.ID), ShouldEqual, 0)
+		})
+
+		Convey("keep their changes over restarts", func() {
+			So(bn.AddPeer(AllowedPeers, string(other.ID)), ShouldBeNil)
+			So(bn.RemovePeer(AllowedPeers, "127.0.0.0/8"), ShouldBeNil)
+			So(bn.RemovePeer(StaticPeers, string(node0.ID)), ShouldBeNil)
+			bn.Stop()
+
+			restarted, err := NewBaseNetwork(&NetConfig{ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_",
+				StaticNodes: addresses[:1], TrustedNodes: []string{string(node1.ID)}, AllowedPeers: []string{"127.0.0.0/8"}})
+			So(err, ShouldBeNil)
+			defer restarted.Stop()
+			lists := restarted.PeerLists()
+			So(lists[StaticPeers], ShouldBeEmpty)
+			So(lists[TrustedPeers], ShouldResemble, []string{string(node1.ID)})
+			So(lists[AllowedPeers], ShouldResemble, []string{string(other.ID)})
+		})
+
+		Convey("redial static peers until stopped", func() {
+			interval := StaticDialInterval
+			defer func() { StaticDialInterval = interval }()
+			StaticDialInterval = time.Millisecond
+			done := make(chan struct{})
+			go func() {
+				bn.staticLoop()
+				close(done)
+			}()
+			bn.Stop()
+			stopped := false
+			select {
+			case <-done:
+				stopped = true
+			case <-time.After(time.Second):
+			}
+			So(stopped, ShouldBeTrue)
+		})
+		cleanLDB()
+	})
+}
+
+func TestSendQueue(t *testing.T) {
+	Convey("send queue", t, func() {
+		release := make(chan struct{})
+		written := make(chan NetReqType, 16)
+		write := func(conn net.Conn, r *Request) error {
+			<-release
+			written <- r.Type
+			return nil
+		}
+		q := newSendQueue(nil, write, func() {})
+		defer q.stop()
+		req := func(typ NetReqType) *Request { return newRequest(typ, "a", nil) }
+
+		Convey("writes the higher priorities first", func() {
+			// the first request is held by the write
+			q.push(req(Message), PriorityTx)
+			time.Sleep(10 * time.Millisecond)
+			q.push(req(Message), PriorityTx)
+			q.push(req(BroadcastMessage), PriorityBlock)
+			q.push(req(Ping), PriorityConsensus)
+			close(release)
+			So(<-written, ShouldEqual, Message)
+			So(<-written, ShouldEqual, Ping)
+			So(<-written, ShouldEqual, BroadcastMessage)
+			So(<-written, ShouldEqual, Message)
+		})
+
+		Convey("drops requests once full", func() {
+			size := SendQueueSize
+			defer func() { SendQueueSize = size }()
+			SendQueueSize[PriorityTx], SendQueueSize[PriorityBlock] = 1, 1
+			q.push(req(Message), PriorityTx)
+			time.Sleep(10 * time.Millisecond)
+			So(q.push(req(Message), PriorityTx), ShouldBeTrue)
+			So(q.push(req(Ping), PriorityTx), ShouldBeFalse)
+			So(q.push(req(Message), PriorityBlock), ShouldBeTrue)
+			So(q.push(req(Pong), PriorityBlock), ShouldBeFalse)
+			So(q.len(PriorityTx), ShouldEqual, 1)
+			So(q.len(PriorityBlock), ShouldEqual, 1)
+			close(release)
+			<-written
+			So(<-written, ShouldEqual, Pong)
+			So(<-written, ShouldEqual, Message)
+		})
+
+		Convey("sends messages by their priority", func() {
+			So(priorityOf(int32(ReqNewBlock)), ShouldEqual, PriorityBlock)
+			So(priorityOf(int32(ReqSyncBlock)), ShouldEqual, PrioritySync)
+			So(priorityOf(int32(ReqPublishTx)), ShouldEqual, PriorityTx)
+			So(priorityOf(1000), ShouldEqual, PriorityTx)
+		})
+	})
+}
+
 func TestBaseNetwork_registerLoop(t *testing.T) {
 	Convey("registerLoop", t, func() {
 		cleanLDB()
@@ -12,14 +12,19 @@ import (
 type Peer struct {
 	conn      net.Conn
 	blockConn net.Conn
+	id        discover.NodeID
+	version   uint32 // the agreed protocol version
+	caps      capSet // the negotiated capabilities
 	local     string
 	remote    string
 	created   mclock.AbsTime
-	closed    chan struct{}
+	connQ     *sendQueue // requests to write to conn
+	blockQ    *sendQueue // requests to write to blockConn
 }
 
 // Disconnect disconnects a connection.
 func (p *Peer) Disconnect() {
+	p.stopQueues()
 	if p != nil && p.conn != nil {
 		p.conn.Close()
 	}
@@ -28,24 +33,69 @@ func (p *Peer) Disconnect() {
 	}
 }
 
-func newPeer(conn net.Conn, blockConn net.Conn, local, remote string) *Peer {
-	return &Peer{
+// newPeer returns the peer of conn and blockConn, the requests sent to it are queued and written
+// by write. onError is called if a write fails.
+func newPeer(conn net.Conn, blockConn net.Conn, local string, remote *discover.Node,
+	write func(net.Conn, *Request) error, onError func()) *Peer {
+	p := &Peer{
 		conn:      conn,
 		blockConn: blockConn,
+		id:        remote.ID,
 		local:     local,
-		remote:    remote,
+		remote:    remote.String(),
 		created:   mclock.Now(),
-		closed:    make(chan struct{}),
+	}
+	if conn != nil {
+		p.connQ = newSendQueue(conn, write, onError)
+	}
+	if blockConn != nil {
+		p.blockQ = newSendQueue(blockConn, write, onError)
+	}
+	return p
+}
+
+// send queues r, a request of a message of reqType, for the connection reqType is sent over. It
+// returns false if a request was dropped for the queue is full.
+func (p *Peer) send(r *Request, reqType int32) bool {
+	q := p.connQ
+	if isBlockReq(reqType) {
+		q = p.blockQ
+	}
+	if q == nil {
+		return false
+	}
+	return q.push(r, priorityOf(reqType))
+}
+
+// stopQueues stops the writing of the requests queued.
+func (p *Peer) stopQueues() {
+	if p == nil {
+		return
+	}
+	if p.connQ != nil {
+		p.connQ.stop()
+	}
+	if p.blockQ != nil {
+		p.blockQ.stop()
 	}
 }
 
-// peerSet represents the collection of active peers.
+// peerSet represents the collection of active peers, keyed by the authenticated node id.
 type peerSet struct {
-	peers  map[string]*Peer
+	peers  map[discover.NodeID]*Peer
+	addrs  map[string]discover.NodeID
 	lock   sync.Mutex
 	closed bool
 }
 
+// lookup returns the id of node, a node without id is looked up by its address.
+func (ps *peerSet) lookup(node *discover.Node) discover.NodeID {
+	if node.ID != "" {
+		return node.ID
+	}
+	return ps.addrs[node.Addr()]
+}
+
 // Get returns a connection with a node.
 func (ps *peerSet) Get(node *discover.Node) *Peer {
 	ps.lock.Lock()
@@ -53,40 +103,37 @@ func (ps *peerSet) Get(node *discover.Node) *Peer {
 	if ps.peers == nil {
 		return nil
 	}
-	peer, ok := ps.peers[node.String()]
+	peer, ok := ps.peers[ps.lookup(node)]
 	if !ok {
 		return nil
 	}
 	return peer
 }
 
-// SetAddr stores a peer in peerSet.
-func (ps *peerSet) SetAddr(addr string, p *Peer) {
-	ps.lock.Lock()
-	defer ps.lock.Unlock()
-	p.remote = addr
-	if ps.peers == nil {
-		ps.peers = make(map[string]*Peer)
-	}
-	ps.peers[addr] = p
-	return
-}
-
 // Set stores a peer in peerSet.
 func (ps *peerSet) Set(node *discover.Node, p *Peer) {
 	ps.lock.Lock()
 	defer ps.lock.Unlock()
+	p.id = node.ID
 	p.remote = node.String()
 	if ps.peers == nil {
-		ps.peers = make(map[string]*Peer)
+		ps.peers = make(map[discover.NodeID]*Peer)
+		ps.addrs = make(map[string]discover.NodeID)
+	}
+	if old, ok := ps.peers[node.ID]; ok && old != p {
+		old.stopQueues()
 	}
-	ps.peers[node.String()] = p
+	ps.peers[node.ID] = p
+	ps.addrs[node.Addr()] = node.ID
 	return
 }
 
 // RemoveByNodeStr removes a peer in peerSet by nodeStr.
 func (ps *peerSet) RemoveByNodeStr(nodeStr string) {
-	node, _ := discover.ParseNode(nodeStr)
+	node, err := discover.ParseNode(nodeStr)
+	if err != nil {
+		return
+	}
 	ps.Remove(node)
 }
 
@@ -94,7 +141,28 @@ func (ps *peerSet) RemoveByNodeStr(nodeStr string) {
 func (ps *peerSet) Remove(node *discover.Node) {
 	ps.lock.Lock()
 	defer ps.lock.Unlock()
-	ps.peers[node.String()].Disconnect()
-	delete(ps.peers, node.String())
+	id := ps.lookup(node)
+	peer, ok := ps.peers[id]
+	if !ok {
+		return
+	}
+	peer.Disconnect()
+	delete(ps.peers, id)
+	for addr, pid := range ps.addrs {
+		if pid == id {
+			delete(ps.addrs, addr)
+		}
+	}
 	return
 }
+
+// list returns the peers in peerSet.
+func (ps *peerSet) list() []*Peer {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+	peers := make([]*Peer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		peers = append(peers, p)
+	}
+	return peers
+}
@@ -16,6 +16,9 @@ const (
 	DiscUnexpectedIdentity
 	DiscSelf
 	DiscReadTimeout
+	DiscIncompatibleChain
+	DiscBanned
+	DiscNotAllowed
 	DiscSubprotocolError = 0x10
 )
 
@@ -32,5 +35,8 @@ var discReasonToString = [...]string{
 	DiscUnexpectedIdentity:  "unexpected identity",
 	DiscSelf:                "connected to self",
 	DiscReadTimeout:         "read timeout",
+	DiscIncompatibleChain:   "incompatible chain",
+	DiscBanned:              "banned",
+	DiscNotAllowed:          "not allowed",
 	DiscSubprotocolError:    "subprotocol error",
 }
@@ -0,0 +1,391 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/network/discover"
+)
+
+// the lists of peers managed by the admin RPC
+const (
+	// StaticPeers are always connected and redialed when the connection is lost
+	StaticPeers = "static"
+	// TrustedPeers are exempt from the rate limits, penalties and bans
+	TrustedPeers = "trusted"
+	// AllowedPeers are the node ids and CIDRs connections are accepted from and made to in
+	// PermissionedMode
+	AllowedPeers = "allowed"
+)
+
+// StaticDialInterval is how often static peers not connected are redialed.
+var StaticDialInterval = 10 * time.Second
+
+// the changes of the peer lists are kept in the node table under peerListPrefix+list+":"+the
+// canonical entry, the entry added or empty if removed
+const peerListPrefix = "peerlist:"
+
+// errors of the peer lists
+var (
+	ErrUnknownPeerList = errors.New("unknown peer list")
+	ErrNotAllowed      = errors.New("peer not allowed")
+)
+
+// peerLists are the static, trusted and allowed peers of a node. Static and trusted peers are
+// allowed too.
+type peerLists struct {
+	mu          sync.RWMutex
+	static      map[discover.NodeID]*discover.Node
+	trusted     map[discover.NodeID]bool
+	allowedIDs  map[discover.NodeID]bool
+	allowedNets map[string]*net.IPNet
+}
+
+func newPeerLists(static, trusted, allowed []string) (*peerLists, error) {
+	l := &peerLists{
+		static:      make(map[discover.NodeID]*discover.Node),
+		trusted:     make(map[discover.NodeID]bool),
+		allowedIDs:  make(map[discover.NodeID]bool),
+		allowedNets: make(map[string]*net.IPNet),
+	}
+	for list, entries := range map[string][]string{StaticPeers: static, TrustedPeers: trusted, AllowedPeers: allowed} {
+		for _, e := range entries {
+			if err := l.add(list, e); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return l, nil
+}
+
+// parseNodeID returns the node id of entry, a node id or a node.
+func parseNodeID(entry string) (discover.NodeID, error) {
+	id := discover.NodeID(entry)
+	if strings.Contains(entry, "@") {
+		node, err := discover.ParseNode(entry)
+		if err != nil {
+			return "", err
+		}
+		id = node.ID
+	}
+	if _, err := id.Pubkey(); err != nil {
+		return "", fmt.Errorf("%v: %v", err, entry)
+	}
+	return id, nil
+}
+
+// parseNet returns the network of entry, a CIDR or an IP, nil if entry is neither.
+func parseNet(entry string) (*net.IPNet, error) {
+	if strings.Contains(entry, "/") {
+		_, n, err := net.ParseCIDR(entry)
+		return n, err
+	}
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil, nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// add puts entry in list. Static peers are nodes "id@ip:port", trusted peers node ids and allowed
+// peers node ids, IPs or CIDRs.
+func (l *peerLists) add(list, entry string) error {
+	entry = strings.TrimSpace(entry)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch list {
+	case StaticPeers:
+		node, err := discover.ParseNode(entry)
+		if err != nil {
+			return err
+		}
+		if _, err := node.ID.Pubkey(); err != nil || node.IP == nil {
+			return fmt.Errorf("invalid static node %v", entry)
+		}
+		l.static[node.ID] = node
+	case TrustedPeers:
+		id, err := parseNodeID(entry)
+		if err != nil {
+			return err
+		}
+		l.trusted[id] = true
+	case AllowedPeers:
+		n, err := parseNet(entry)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			l.allowedNets[n.String()] = n
+			return nil
+		}
+		id, err := parseNodeID(entry)
+		if err != nil {
+			return err
+		}
+		l.allowedIDs[id] = true
+	default:
+		return ErrUnknownPeerList
+	}
+	return nil
+}
+
+// remove takes entry out of list.
+func (l *peerLists) remove(list, entry string) error {
+	entry = strings.TrimSpace(entry)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch list {
+	case StaticPeers:
+		id, err := parseNodeID(entry)
+		if err != nil {
+			return err
+		}
+		delete(l.static, id)
+	case TrustedPeers:
+		id, err := parseNodeID(entry)
+		if err != nil {
+			return err
+		}
+		delete(l.trusted, id)
+	case AllowedPeers:
+		n, err := parseNet(entry)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			delete(l.allowedNets, n.String())
+			return nil
+		}
+		id, err := parseNodeID(entry)
+		if err != nil {
+			return err
+		}
+		delete(l.allowedIDs, id)
+	default:
+		return ErrUnknownPeerList
+	}
+	return nil
+}
+
+// canonical returns the key entry is stored at in list: the node id, or the network of a CIDR.
+func canonical(list, entry string) (string, error) {
+	entry = strings.TrimSpace(entry)
+	switch list {
+	case StaticPeers, TrustedPeers:
+	case AllowedPeers:
+		n, err := parseNet(entry)
+		if err != nil {
+			return "", err
+		}
+		if n != nil {
+			return n.String(), nil
+		}
+	default:
+		return "", ErrUnknownPeerList
+	}
+	id, err := parseNodeID(entry)
+	return string(id), err
+}
+
+func isPeerListKey(k []byte) bool {
+	return strings.HasPrefix(string(k), peerListPrefix)
+}
+
+func (l *peerLists) isStatic(id discover.NodeID) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.static[id]
+	return ok
+}
+
+func (l *peerLists) isTrusted(id discover.NodeID) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.trusted[id]
+}
+
+// staticNodes returns the static peers.
+func (l *peerLists) staticNodes() []*discover.Node {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	nodes := make([]*discover.Node, 0, len(l.static))
+	for _, n := range l.static {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// allowed tells whether connections with node are allowed, every node is in modes other than
+// PermissionedMode.
+func (l *peerLists) allowed(node *discover.Node) bool {
+	if NetMode != PermissionedMode {
+		return true
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if node.ID != "" {
+		if _, ok := l.static[node.ID]; ok || l.trusted[node.ID] || l.allowedIDs[node.ID] {
+			return true
+		}
+	}
+	for _, n := range l.allowedNets {
+		if node.IP != nil && n.Contains(node.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// lists returns the entries of every list, sorted.
+func (l *peerLists) lists() map[string][]string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	lists := map[string][]string{
+		StaticPeers:  make([]string, 0, len(l.static)),
+		TrustedPeers: make([]string, 0, len(l.trusted)),
+		AllowedPeers: make([]string, 0, len(l.allowedIDs)+len(l.allowedNets)),
+	}
+	for _, n := range l.static {
+		lists[StaticPeers] = append(lists[StaticPeers], n.String())
+	}
+	for id := range l.trusted {
+		lists[TrustedPeers] = append(lists[TrustedPeers], string(id))
+	}
+	for id := range l.allowedIDs {
+		lists[AllowedPeers] = append(lists[AllowedPeers], string(id))
+	}
+	for cidr := range l.allowedNets {
+		lists[AllowedPeers] = append(lists[AllowedPeers], cidr)
+	}
+	for _, entries := range lists {
+		sort.Strings(entries)
+	}
+	return lists
+}
+
+// savePeerList keeps the change of entry in list over restarts, removed tells whether it was
+// taken out of list.
+func (bn *BaseNetwork) savePeerList(list, entry string, removed bool) {
+	key, err := canonical(list, entry)
+	if err != nil {
+		return
+	}
+	val := []byte(strings.TrimSpace(entry))
+	if removed {
+		val = []byte{}
+	}
+	if err := bn.nodeTable.Put([]byte(peerListPrefix+list+":"+key), val); err != nil {
+		bn.log.E("[net] save the %v peers got err:%v", list, err)
+	}
+}
+
+// loadPeerLists applies the changes of the peer lists saved to the lists of the config.
+func (bn *BaseNetwork) loadPeerLists() {
+	iter := bn.nodeTable.NewIterator()
+	defer iter.Release()
+	for iter.Next() {
+		if !isPeerListKey(iter.Key()) {
+			continue
+		}
+		k := string(iter.Key()[len(peerListPrefix):])
+		i := strings.Index(k, ":")
+		if i < 0 {
+			continue
+		}
+		list, key := k[:i], k[i+1:]
+		var err error
+		if len(iter.Value()) == 0 {
+			err = bn.lists.remove(list, key)
+		} else {
+			err = bn.lists.add(list, string(iter.Value()))
+		}
+		if err != nil {
+			bn.log.E("[net] load the %v peers got err:%v", list, err)
+		}
+	}
+}
+
+// AddPeer puts entry in the peer list named list, static peers are dialed at once. The change is
+// kept over restarts.
+func (bn *BaseNetwork) AddPeer(list, entry string) error {
+	if err := bn.lists.add(list, entry); err != nil {
+		return err
+	}
+	bn.savePeerList(list, entry, false)
+	bn.log.I("[net] add %v to the %v peers", entry, list)
+	if list == StaticPeers {
+		bn.putNode(entry)
+		go bn.dialStatic()
+	}
+	return nil
+}
+
+// RemovePeer takes entry out of the peer list named list, peers which are not allowed anymore
+// are disconnected. The change is kept over restarts.
+func (bn *BaseNetwork) RemovePeer(list, entry string) error {
+	if err := bn.lists.remove(list, entry); err != nil {
+		return err
+	}
+	bn.savePeerList(list, entry, true)
+	bn.log.I("[net] remove %v from the %v peers", entry, list)
+	bn.dropDisallowed()
+	return nil
+}
+
+// PeerLists returns the entries of the static, trusted and allowed peer lists.
+func (bn *BaseNetwork) PeerLists() map[string][]string {
+	return bn.lists.lists()
+}
+
+// dropDisallowed disconnects and forgets the nodes not allowed.
+func (bn *BaseNetwork) dropDisallowed() {
+	for _, p := range bn.peers.list() {
+		node, err := discover.ParseNode(p.remote)
+		if err == nil && !bn.lists.allowed(node) {
+			bn.log.I("[net] disconnect %v, not allowed", node)
+			bn.peers.Remove(node)
+		}
+	}
+	nodes, _ := bn.AllNodesExcludeAddr("")
+	for _, nodeStr := range nodes {
+		node, err := discover.ParseNode(nodeStr)
+		if err == nil && !bn.lists.allowed(node) {
+			bn.deleteNode(nodeStr)
+		}
+	}
+	bn.findNeighbours()
+}
+
+// dialStatic connects the static peers which are not connected.
+func (bn *BaseNetwork) dialStatic() {
+	for _, node := range bn.lists.staticNodes() {
+		if bn.peers.Get(node) != nil {
+			continue
+		}
+		if _, err := bn.dial(node.String()); err != nil {
+			bn.log.D("[net] dial static peer %v got err:%v", node, err)
+		}
+	}
+}
+
+// staticLoop redials the static peers until the network is stopped.
+func (bn *BaseNetwork) staticLoop() {
+	ticker := time.NewTicker(StaticDialInterval)
+	defer ticker.Stop()
+	for {
+		bn.dialStatic()
+		select {
+		case <-ticker.C:
+		case <-bn.quit:
+			return
+		}
+	}
+}
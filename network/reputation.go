@@ -0,0 +1,214 @@
+package network
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/common"
+	"github.com/iost-official/Go-IOS-Protocol/network/discover"
+)
+
+// ErrMsgTooLarge is returned when a peer sends a request larger than MaxRequestSize.
+var ErrMsgTooLarge = errors.New("message too large")
+
+// penalties deducted from the score of a peer
+const (
+	PenaltyRateLimit    = 5
+	PenaltyBadMsg       = 10 // the message can not be decoded
+	PenaltyInvalidTx    = 10 // e.g. a bad signature
+	PenaltyOversize     = 20
+	PenaltyInvalidBlock = 50
+)
+
+// limits of the messages of a peer
+var (
+	MaxRequestSize int32 = 32 << 20
+	// DefaultMaxMsgSize bounds the body of messages not in maxMsgSize
+	DefaultMaxMsgSize = 256 << 10
+
+	MsgRate   = 200.0 // messages per second
+	MsgBurst  = 1000.0
+	ByteRate  = float64(8 << 20) // bytes per second
+	ByteBurst = float64(64 << 20)
+
+	// BanScore is the score a peer is banned at for BanDuration, scores recover
+	// ScoreRecovery per minute up to 0
+	BanScore      = -100
+	BanDuration   = time.Hour
+	ScoreRecovery = 1
+)
+
+var maxMsgSize = map[ReqType]int{
+	ReqNewBlock:       16 << 20,
+	ReqSyncBlock:      16 << 20,
+	RecvBlockTxs:      16 << 20,
+	ReqCompactBlock:   4 << 20,
+	BlockHashResponse: 1 << 20,
+	ReqTxByHash:       1 << 20,
+
+	RecvSnapshotManifest: 4 << 20,
+	RecvSnapshotChunk:    1 << 20,
+}
+
+// MaxMsgSize returns the max body size of messages of reqType.
+func MaxMsgSize(reqType ReqType) int {
+	if size, ok := maxMsgSize[reqType]; ok {
+		return size
+	}
+	return DefaultMaxMsgSize
+}
+
+const (
+	banPrefix = "ban:"
+	// maxTracked bounds the peers with a reputation, idle ones are forgotten past it
+	maxTracked  = 10000
+	idleTracked = 10 * time.Minute
+)
+
+// tokenBucket holds up to burst tokens, refilled at rate per second.
+type tokenBucket struct {
+	rate, burst, tokens float64
+	last                time.Time
+}
+
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// take takes n tokens, it returns false if there are not enough.
+func (b *tokenBucket) take(n float64) bool {
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < n {
+		return false
+	}
+	b.tokens -= n
+	return true
+}
+
+// peerRep is the reputation of a peer.
+type peerRep struct {
+	score   int
+	updated time.Time // when the score last recovered
+	seen    time.Time
+	msgs    *tokenBucket
+	bytes   *tokenBucket
+}
+
+// reputation tracks the score and the rate of the messages of every peer.
+type reputation struct {
+	mu    sync.Mutex
+	peers map[discover.NodeID]*peerRep
+}
+
+func newReputation() *reputation {
+	return &reputation{peers: make(map[discover.NodeID]*peerRep)}
+}
+
+func (r *reputation) get(id discover.NodeID) *peerRep {
+	now := time.Now()
+	p, ok := r.peers[id]
+	if !ok {
+		if len(r.peers) >= maxTracked {
+			for k, v := range r.peers {
+				if now.Sub(v.seen) > idleTracked {
+					delete(r.peers, k)
+				}
+			}
+		}
+		p = &peerRep{
+			updated: now,
+			msgs:    newTokenBucket(MsgRate, MsgBurst),
+			bytes:   newTokenBucket(ByteRate, ByteBurst),
+		}
+		r.peers[id] = p
+	}
+	p.seen = now
+	if minutes := int(now.Sub(p.updated) / time.Minute); minutes > 0 {
+		p.score += minutes * ScoreRecovery
+		if p.score > 0 {
+			p.score = 0
+		}
+		p.updated = p.updated.Add(time.Duration(minutes) * time.Minute)
+	}
+	return p
+}
+
+// allow takes a message of size bytes from the buckets of id.
+func (r *reputation) allow(id discover.NodeID, size int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.get(id)
+	return p.msgs.take(1) && p.bytes.take(float64(size))
+}
+
+// penalize deducts penalty from the score of id and returns the score.
+func (r *reputation) penalize(id discover.NodeID, penalty int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.get(id)
+	p.score -= penalty
+	return p.score
+}
+
+// score returns the score of id.
+func (r *reputation) score(id discover.NodeID) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.get(id).score
+}
+
+// forget resets the reputation of id.
+func (r *reputation) forget(id discover.NodeID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.peers, id)
+}
+
+func isBanKey(k []byte) bool {
+	return strings.HasPrefix(string(k), banPrefix)
+}
+
+// Penalize deducts penalty from the score of the peer nodeStr, it is banned once its score
+// reaches BanScore. Trusted peers are not penalized.
+func (bn *BaseNetwork) Penalize(nodeStr string, penalty int) {
+	node, err := discover.ParseNode(nodeStr)
+	if err != nil || node.ID == "" || node.ID == bn.localNode.ID || bn.lists.isTrusted(node.ID) {
+		return
+	}
+	penalizedPeerCount.Inc()
+	score := bn.rep.penalize(node.ID, penalty)
+	bn.log.D("[net] penalize %v by %v, score %v", nodeStr, penalty, score)
+	if score <= BanScore {
+		bn.Ban(node, BanDuration)
+	}
+}
+
+// Ban disconnects node and refuses it for d, the ban is kept in the node table.
+func (bn *BaseNetwork) Ban(node *discover.Node, d time.Duration) {
+	bn.log.I("[net] ban %v for %v", node, d)
+	bannedPeerCount.Inc()
+	bn.nodeTable.Put([]byte(banPrefix+string(node.ID)), common.Int64ToBytes(time.Now().Add(d).Unix()))
+	bn.rep.forget(node.ID)
+	bn.peers.Remove(node)
+	bn.deleteNode(node.String())
+	bn.findNeighbours()
+}
+
+// IsBanned tells whether id is banned, expired bans are lifted.
+func (bn *BaseNetwork) IsBanned(id discover.NodeID) bool {
+	key := []byte(banPrefix + string(id))
+	b, err := bn.nodeTable.Get(key)
+	if err != nil || len(b) != 8 {
+		return false
+	}
+	if time.Now().Unix() >= common.BytesToInt64(b) {
+		bn.nodeTable.Delete(key)
+		return false
+	}
+	return true
+}
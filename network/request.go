@@ -3,6 +3,7 @@ package network
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/iost-official/Go-IOS-Protocol/common"
 	"github.com/iost-official/Go-IOS-Protocol/core/message"
+	"github.com/iost-official/Go-IOS-Protocol/network/discover"
 )
 
 // NetReqType defines a request's type.
@@ -26,6 +28,10 @@ const (
 	Pong
 	ReqNodeTable
 	NodeTable
+	ReqHello      // the first request on a connection
+	ReqDisconnect // tells why a connection is closed
+	GossipIHave   // ids of broadcast messages the sender has
+	GossipIWant   // ids of broadcast messages the sender asks for
 )
 
 // Request is the data structure exchanged by nodes.
@@ -89,6 +95,9 @@ func (r *Request) Unpack(reader io.Reader) error {
 	err = binary.Read(reader, binary.BigEndian, &r.Timestamp)
 	err = binary.Read(reader, binary.BigEndian, &r.Type)
 	err = binary.Read(reader, binary.BigEndian, &r.FromLen)
+	if err != nil || r.FromLen < 0 || r.Length < 8+2+2+int32(r.FromLen) {
+		return errors.New("invalid request length")
+	}
 	r.From = make([]byte, r.FromLen)
 	err = binary.Read(reader, binary.BigEndian, &r.From)
 	r.Body = make([]byte, r.Length-8-2-2-int32(r.FromLen))
@@ -116,42 +125,77 @@ func prometheusReceivedBlockTx(req *message.Message) {
 	}
 }
 
-func (r *Request) handle(base *BaseNetwork, conn net.Conn) {
+// appMessage decodes the message carried by r. Messages which can not be decoded or are too
+// large get the sender penalized, messages of capabilities not in caps are dropped. Messages sent
+// to the local node are from the authenticated peer, broadcast ones keep their origin.
+func (r *Request) appMessage(base *BaseNetwork, caps capSet) (appReq *message.Message, ok bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			base.log.E("[net] failed to unmarshal recv msg:%v, err:%v", r, e)
+			base.Penalize(string(r.From), PenaltyBadMsg)
+			appReq, ok = nil, false
+		}
+	}()
+	appReq = &message.Message{}
+	if _, err := appReq.Unmarshal(r.Body); err != nil {
+		base.log.E("[net] failed to unmarshal recv msg:%v, err:%v", r, err)
+		base.Penalize(string(r.From), PenaltyBadMsg)
+		return nil, false
+	}
+	if len(appReq.Body) > MaxMsgSize(ReqType(appReq.ReqType)) {
+		base.log.D("[net] drop msg of type %v, size %v, from %v", appReq.ReqType, len(appReq.Body), string(r.From))
+		base.Penalize(string(r.From), PenaltyOversize)
+		return nil, false
+	}
+	if !caps.has(appReq.ReqType) {
+		base.log.D("[net] drop msg of type %v not negotiated with %v", appReq.ReqType, string(r.From))
+		return nil, false
+	}
+	if r.Type == Message {
+		appReq.From = string(r.From)
+	}
+	return appReq, true
+}
+
+// handle handles a request read from conn, messages of capabilities not in caps are dropped.
+func (r *Request) handle(base *BaseNetwork, conn net.Conn, caps capSet) {
 	switch r.Type {
 	case Message:
-		appReq := &message.Message{}
-		if _, err := appReq.Unmarshal(r.Body); err == nil {
-			base.log.D("[net] msg from =%v, to = %v, typ = %v,  ttl = %v", appReq.From, appReq.To, appReq.ReqType, appReq.TTL)
-			base.RecvCh <- *appReq
-			prometheusReceivedBlockTx(appReq)
-
-		} else {
-			base.log.E("[net] failed to unmarshal recv msg:%v, err:%v", r, err)
+		appReq, ok := r.appMessage(base, caps)
+		if !ok {
+			return
 		}
+		base.log.D("[net] msg from =%v, to = %v, typ = %v,  ttl = %v", appReq.From, appReq.To, appReq.ReqType, appReq.TTL)
+		base.RecvCh <- *appReq
+		prometheusReceivedBlockTx(appReq)
 		r.msgHandle(base)
 	case MessageReceived:
 		base.log.D("[net] MessageReceived: %v", string(r.From), common.BytesToInt64(r.Body))
 	case BroadcastMessage:
-		appReq := &message.Message{}
-		if _, err := appReq.Unmarshal(r.Body); err == nil {
-			if appReq.ReqType == int32(ReqBlockHeight) {
-				appReq.From = string(r.From)
-			}
-			base.RecvCh <- *appReq
+		appReq, ok := r.appMessage(base, caps)
+		if !ok {
+			return
+		}
+		if !base.receiveGossip(*appReq, string(r.From)) {
+			return
+		}
+		base.RecvCh <- *appReq
 
-			prometheusReceivedBlockTx(appReq)
-			if appReq.ReqType != int32(ReqDownloadBlock) {
-				base.Broadcast(*appReq)
-			}
+		prometheusReceivedBlockTx(appReq)
+		if !isAnnouncement(appReq.ReqType) && !isRelayedOnceValid(appReq.ReqType) {
+			base.Broadcast(*appReq)
 		}
 		r.msgHandle(base)
 	case BroadcastMessageReceived:
 	//request for nodeTable
 	case ReqNodeTable:
 
-		if isValidNode(r, base) {
+		node, err := discover.ParseNode(string(r.From))
+		if err == nil && isValidNode(r, base) {
 			base.putNode(string(r.From))
-			base.peers.SetAddr(string(r.From), newPeer(conn, nil, base.localNode.Addr(), conn.RemoteAddr().String()))
+			peer := base.newPeer(conn, nil, node)
+			peer.caps = caps
+			base.peers.Set(node, peer)
 			base.sendNodeTable(r.From, conn)
 		} else {
 			conn.Close()
@@ -160,13 +204,24 @@ func (r *Request) handle(base *BaseNetwork, conn net.Conn) {
 	case NodeTable:
 		base.log.D("[net] response node table: %v", string(r.Body))
 		base.putNode(string(r.Body))
+	case GossipIHave:
+		base.handleIHave(r)
+	case GossipIWant:
+		base.handleIWant(r)
+	case ReqDisconnect:
+		base.log.D("[net] disconnected by %v: %v", string(r.From), decodeDisconnect(r.Body))
+		conn.Close()
 	default:
 		base.log.E("[net] wrong request :", r)
 	}
 }
 
 func isValidNode(r *Request, base *BaseNetwork) bool {
-	strs := strings.Split(string(r.From), ":")
+	from := string(r.From)
+	if i := strings.Index(from, "@"); i >= 0 {
+		from = from[i+1:]
+	}
+	strs := strings.Split(from, ":")
 	if NetMode == PublicMode && !common.IsPublicIP(net.ParseIP(strs[0])) {
 		base.log.D("[net] the node's ip is not public ip: %v", strs[0])
 		return false
@@ -179,6 +234,9 @@ func (r *Request) msgHandle(net *BaseNetwork) {
 	if _, err := msg.Unmarshal(r.Body); err == nil {
 		switch msg.ReqType {
 		case int32(RecvBlockHeight):
+			if len(msg.Body) < 8 {
+				return
+			}
 			var rh message.ResponseHeight
 			rh.Decode(msg.Body)
 			net.SetNodeHeightMap(string(r.From), rh.BlockHeight)
@@ -5,6 +5,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"log"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 func TestRequest_isValidNode(t *testing.T) {
 	Convey("register", t, func() {
 		bn, _ := NewBaseNetwork(&NetConfig{RegisterAddr: "127.0.0.1:30304", ListenAddr: "127.0.0.1", NodeTablePath: "iost_db_"})
+		defer bn.Stop()
 		isValid := isValidNode(&Request{From: []byte("127.0.0.1")}, bn)
 		So(isValid, ShouldBeTrue)
 		isValid = isValidNode(&Request{From: []byte("192.168.1.34")}, bn)
@@ -91,3 +94,114 @@ func reader(buf *bytes.Buffer, readerCh chan Request) {
 		log.Fatal("invalid data pack")
 	}
 }
+
+// bufConn is a connection reading what was written to it.
+type bufConn struct {
+	net.Conn
+	buf       *bytes.Buffer
+	deadlines int
+}
+
+func (c *bufConn) Read(b []byte) (int, error)  { return c.buf.Read(b) }
+func (c *bufConn) Write(b []byte) (int, error) { return c.buf.Write(b) }
+func (c *bufConn) SetWriteDeadline(t time.Time) error {
+	c.deadlines++
+	return nil
+}
+
+func TestFrame(t *testing.T) {
+	Convey("Test of framing v2", t, func() {
+		conn := &bufConn{buf: new(bytes.Buffer)}
+		fc := newFrameConn(conn, &session{caps: capSet{"frame": 2, "snappy": 1, "deflate": 1}})
+		So(fc.framing, ShouldEqual, 2)
+		So(fc.codec, ShouldEqual, codecSnappy)
+		bn := &BaseNetwork{}
+		code := []byte(strings.Repeat("@PutHM iost account f1000.0\n", 20000))
+		big, _ := newRequest(Message, "0.0.0.0", code).Pack()
+		small, _ := newRequest(Message, "0.0.0.0", []byte("small")).Pack()
+
+		Convey("compresses and cuts large requests", func() {
+			So(fc.writeFrames(big), ShouldBeNil)
+			So(conn.buf.Len(), ShouldBeLessThan, len(big)/4)
+			pack, err := bn.readMsg(fc)
+			So(err, ShouldBeNil)
+			So(bytes.Equal(pack, big), ShouldBeTrue)
+
+			FrameChunkSize = 1024
+			defer func() { FrameChunkSize = 64 << 10 }()
+			frames := packFrames(big, codecDeflate, 7)
+			So(len(frames), ShouldBeGreaterThan, 1)
+			for _, f := range frames {
+				conn.Write(f)
+			}
+			pack, err = bn.readMsg(fc)
+			So(err, ShouldBeNil)
+			So(bytes.Equal(pack, big), ShouldBeTrue)
+		})
+
+		Convey("interleaves the frames of requests", func() {
+			FrameChunkSize = 1024
+			defer func() { FrameChunkSize = 64 << 10 }()
+			frames := packFrames(big, codecNone, 100)
+			conn.Write(frames[0])
+			fc.writeFrames(small)
+			for _, f := range frames[1:] {
+				conn.Write(f)
+			}
+			pack, err := bn.readMsg(fc)
+			So(err, ShouldBeNil)
+			So(bytes.Equal(pack, small), ShouldBeTrue)
+			pack, err = bn.readMsg(fc)
+			So(err, ShouldBeNil)
+			So(bytes.Equal(pack, big), ShouldBeTrue)
+		})
+
+		Convey("refuses corrupted frames", func() {
+			frame := packFrames(small, codecNone, 1)[0]
+			frame[len(frame)-1] ^= 1
+			conn.Write(frame)
+			_, err := bn.readMsg(fc)
+			So(err, ShouldEqual, ErrFrameChecksum)
+		})
+
+		Convey("refuses oversized frames and bounds the requests partially read", func() {
+			frame := packFrames(small, codecNone, 1)[0]
+			binary.BigEndian.PutUint32(frame[9:], uint32(FrameChunkSize+1))
+			conn.Write(frame)
+			_, err := bn.readMsg(fc)
+			So(err, ShouldEqual, ErrMsgTooLarge)
+			conn.buf.Reset()
+
+			FrameChunkSize = 1024
+			MaxPartialSize = 4096
+			defer func() { FrameChunkSize, MaxPartialSize = 64<<10, int(MaxRequestSize) }()
+			fc = newFrameConn(conn, &session{caps: capSet{"frame": 2}})
+			for stream := uint32(1); stream <= 5; stream++ {
+				conn.Write(packFrames(big, codecNone, stream)[0])
+			}
+			_, err = bn.readMsg(fc)
+			So(err, ShouldEqual, ErrMsgTooLarge)
+			So(fc.partialSize, ShouldEqual, 4096)
+		})
+
+		Convey("still reads the v1 framing", func() {
+			conn.Write(small)
+			pack, err := bn.readMsg(fc)
+			So(err, ShouldBeNil)
+			So(bytes.Equal(pack, small), ShouldBeTrue)
+
+			v1 := newFrameConn(conn, &session{caps: capSet{"tx": 1}})
+			So(v1.framing, ShouldEqual, 1)
+			bn.send(v1, newRequest(Message, "0.0.0.0", []byte("small")))
+			So(isNetVersionMatch(conn.buf.Bytes()), ShouldBeTrue)
+
+			// large requests get a deadline per chunk
+			conn.buf.Reset()
+			conn.deadlines = 0
+			So(writeChunked(conn, big), ShouldBeNil)
+			So(conn.deadlines, ShouldEqual, (len(big)+FrameChunkSize-1)/FrameChunkSize)
+			So(conn.deadlines, ShouldBeGreaterThan, 1)
+			So(bytes.Equal(conn.buf.Bytes(), big), ShouldBeTrue)
+		})
+	})
+}
@@ -16,6 +16,13 @@ var (
 		},
 	)
 
+	sendBlockBytes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "send_block_bytes",
+			Help: "Bytes of block sent by current node, compact blocks included",
+		},
+	)
+
 	sendTransactionCount = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: "send_transaction_count",
@@ -29,12 +36,96 @@ var (
 			Help: "Count of received broad transaction by current node",
 		},
 	)
+
+	penalizedPeerCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "penalized_peer_count",
+			Help: "Count of penalties given to peers",
+		},
+	)
+
+	bannedPeerCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "banned_peer_count",
+			Help: "Count of peers banned by current node",
+		},
+	)
+
+	gossipReceivedCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "gossip_received_count",
+			Help: "Count of broadcast messages received for the first time",
+		},
+	)
+
+	gossipDuplicateCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "gossip_duplicate_count",
+			Help: "Count of broadcast messages received again and dropped",
+		},
+	)
+
+	gossipIHaveCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "gossip_ihave_count",
+			Help: "Count of message ids announced instead of pushing the message",
+		},
+	)
+
+	gossipIWantCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "gossip_iwant_count",
+			Help: "Count of announced messages asked for",
+		},
+	)
+
+	gossipCoverage = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "gossip_coverage",
+			Help:    "Share of the neighbours reached by a broadcast message",
+			Buckets: prometheus.LinearBuckets(0.1, 0.1, 10),
+		},
+	)
+
+	frameSavedBytes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "frame_saved_bytes",
+			Help: "Bytes saved by compressing requests sent",
+		},
+	)
+
+	sendQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "send_queue_depth",
+			Help: "Requests queued to be sent to peers, by priority",
+		},
+		[]string{"priority"},
+	)
+
+	sendQueueDrops = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "send_queue_drops",
+			Help: "Count of requests dropped for the send queue of a peer is full, by priority",
+		},
+		[]string{"priority"},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(sendBlockCount)
+	prometheus.MustRegister(sendBlockBytes)
 	prometheus.MustRegister(sendTransactionCount)
 	prometheus.MustRegister(receivedBroadTransactionCount)
+	prometheus.MustRegister(penalizedPeerCount)
+	prometheus.MustRegister(bannedPeerCount)
+	prometheus.MustRegister(gossipReceivedCount)
+	prometheus.MustRegister(gossipDuplicateCount)
+	prometheus.MustRegister(gossipIHaveCount)
+	prometheus.MustRegister(gossipIWantCount)
+	prometheus.MustRegister(gossipCoverage)
+	prometheus.MustRegister(frameSavedBytes)
+	prometheus.MustRegister(sendQueueDepth)
+	prometheus.MustRegister(sendQueueDrops)
 }
 
 // go:generate mockgen -destination mocks/mock_router.go -package protocol_mock github.com/iost-official/Go-IOS-Protocol/network Router
@@ -52,6 +143,17 @@ const (
 	BlockHashQuery
 	BlockHashResponse
 	ReqSyncBlock
+	ReqTxHashes // announce hashes of txs, peers request the bodies they lack
+	ReqTxByHash // request tx bodies by hash
+	ReqCompactBlock // a new block carrying short tx ids instead of txs
+	ReqBlockTxs     // request txs of a compact block by index
+	RecvBlockTxs    // txs of a compact block
+	ReqBlockHeaders  // request the headers of a range of blocks
+	RecvBlockHeaders // headers of a range of blocks
+	ReqSnapshotManifest  // request the manifest of the latest state snapshot
+	RecvSnapshotManifest // manifest of a state snapshot
+	ReqSnapshotChunk     // request a chunk of a state snapshot
+	RecvSnapshotChunk    // chunk of a state snapshot
 
 	MsgMaxTTL = 2
 )
@@ -68,6 +170,15 @@ type Router interface {
 	CancelDownload(start, end uint64) error
 	AskABlock(height uint64, to string) error
 	QueryBlockHash(start uint64, end uint64) error
+	NeighbourNum() int
+	PeerHeights() map[string]uint64
+	Penalize(nodeStr string, penalty int)
+	PenalizeSender(msg message.Message, penalty int)
+	Sender(msg message.Message) string
+	Relay(msg message.Message)
+	AddPeer(list, entry string) error
+	RemovePeer(list, entry string) error
+	PeerLists() map[string][]string
 }
 
 // Route is a global Router instance.
@@ -153,7 +264,7 @@ func (r *RouterImpl) receiveLoop() {
 	for true {
 		select {
 		case <-r.ExitSignal:
-			r.base.Close(r.port)
+			r.base.(*BaseNetwork).Stop()
 			return
 		case req := <-r.chIn:
 			for i, f := range r.filterList {
@@ -182,16 +293,36 @@ func (r *RouterImpl) Send(req message.Message) {
 	r.base.Send(req)
 }
 
-// Broadcast to all known members.
+// Broadcast gossips req to the network.
 func (r *RouterImpl) Broadcast(req message.Message) {
-	req.TTL = MsgMaxTTL
+	req.TTL = GossipMaxTTL
 
 	r.base.Broadcast(req)
 }
 
 // LocalID returns local node's ID.
 func (r *RouterImpl) LocalID() string {
-	return r.base.(*BaseNetwork).localNode.Addr()
+	return r.base.(*BaseNetwork).localNode.String()
+}
+
+// Penalize deducts penalty from the score of a peer which sent an invalid message.
+func (r *RouterImpl) Penalize(nodeStr string, penalty int) {
+	r.base.(*BaseNetwork).Penalize(nodeStr, penalty)
+}
+
+// PenalizeSender penalizes the peer an invalid message was received from.
+func (r *RouterImpl) PenalizeSender(msg message.Message, penalty int) {
+	r.base.(*BaseNetwork).PenalizeSender(msg, penalty)
+}
+
+// Sender returns the peer a message was received from, which replies should go to.
+func (r *RouterImpl) Sender(msg message.Message) string {
+	return r.base.(*BaseNetwork).Sender(msg)
+}
+
+// Relay gossips on a broadcast message once it is validated.
+func (r *RouterImpl) Relay(msg message.Message) {
+	r.base.(*BaseNetwork).Relay(msg)
 }
 
 // Download downloads blocks whose height is greater than start argument and less than end argument.
@@ -218,6 +349,24 @@ func (r *RouterImpl) QueryBlockHash(start uint64, end uint64) error {
 	return r.base.QueryBlockHash(start, end)
 }
 
+// NeighbourNum returns the number of neighbours messages are broadcast to.
+func (r *RouterImpl) NeighbourNum() int {
+	bn, ok := r.base.(*BaseNetwork)
+	if !ok {
+		return 0
+	}
+	return bn.NeighbourNum()
+}
+
+// PeerHeights returns the known head heights of the peers.
+func (r *RouterImpl) PeerHeights() map[string]uint64 {
+	bn, ok := r.base.(*BaseNetwork)
+	if !ok {
+		return nil
+	}
+	return bn.PeerHeights()
+}
+
 //Filter is filter used by Router.
 // Rulers :
 //     1. if both white list and black list are nil, this filter is all-pass
@@ -288,3 +437,30 @@ func reqTypeContain(a int32, c []ReqType) bool {
 	return false
 
 }
+
+// AddPeer puts entry in the peer list named list.
+func (r *RouterImpl) AddPeer(list, entry string) error {
+	bn, ok := r.base.(*BaseNetwork)
+	if !ok {
+		return ErrUnknownPeerList
+	}
+	return bn.AddPeer(list, entry)
+}
+
+// RemovePeer takes entry out of the peer list named list.
+func (r *RouterImpl) RemovePeer(list, entry string) error {
+	bn, ok := r.base.(*BaseNetwork)
+	if !ok {
+		return ErrUnknownPeerList
+	}
+	return bn.RemovePeer(list, entry)
+}
+
+// PeerLists returns the static, trusted and allowed peers.
+func (r *RouterImpl) PeerLists() map[string][]string {
+	bn, ok := r.base.(*BaseNetwork)
+	if !ok {
+		return nil
+	}
+	return bn.PeerLists()
+}
@@ -14,6 +14,7 @@ func TestRouterImpl_Init(t *testing.T) {
 	//broadcast(t)
 	router, _ := RouterFactory("base")
 	baseNet, _ := NewBaseNetwork(&NetConfig{ListenAddr: "0.0.0.0"})
+	defer baseNet.Stop()
 	router.Init(baseNet, 30601)
 	Convey("init", t, func() {
 		So(router.(*RouterImpl).port, ShouldEqual, 30601)
@@ -0,0 +1,210 @@
+package network
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/iost-official/Go-IOS-Protocol/common"
+	"github.com/iost-official/Go-IOS-Protocol/network/discover"
+)
+
+// HandshakeTimeout bounds the handshake of a new connection.
+var HandshakeTimeout = 5 * time.Second
+
+// errors of the secure transport
+var (
+	ErrHandshake          = errors.New("handshake failed")
+	ErrUnexpectedIdentity = errors.New("unexpected identity")
+	ErrFrameTooLarge      = errors.New("frame too large")
+)
+
+const (
+	handshakeLabel = "iost-handshake-v1"
+	// maxFrame is the max plaintext length of one encrypted frame, longer writes are split
+	maxFrame = 64 << 10
+	// auth is the static public key, the listen port and the signature of the transcript
+	authLength = ed25519.PublicKeySize + 2 + ed25519.SignatureSize
+)
+
+// secureConn encrypts and authenticates everything written to the underlying connection with
+// AES-GCM, every frame is the big endian length of the ciphertext followed by the ciphertext
+type secureConn struct {
+	net.Conn
+	enc, dec       cipher.AEAD
+	wnonce, rnonce uint64
+	wmu            sync.Mutex
+	rbuf           []byte
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func nonceOf(aead cipher.AEAD, n uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], n)
+	return nonce
+}
+
+// Write encrypts b as one or more frames.
+func (c *secureConn) Write(b []byte) (int, error) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	written := 0
+	for len(b) > 0 {
+		n := len(b)
+		if n > maxFrame {
+			n = maxFrame
+		}
+		sealed := c.enc.Seal(nil, nonceOf(c.enc, c.wnonce), b[:n], nil)
+		c.wnonce++
+		frame := make([]byte, 4+len(sealed))
+		binary.BigEndian.PutUint32(frame, uint32(len(sealed)))
+		copy(frame[4:], sealed)
+		if _, err := c.Conn.Write(frame); err != nil {
+			return written, err
+		}
+		written += n
+		b = b[n:]
+	}
+	return written, nil
+}
+
+// Read decrypts frames into b, a frame which fails authentication breaks the connection.
+func (c *secureConn) Read(b []byte) (int, error) {
+	if len(c.rbuf) == 0 {
+		head := make([]byte, 4)
+		if _, err := io.ReadFull(c.Conn, head); err != nil {
+			return 0, err
+		}
+		length := binary.BigEndian.Uint32(head)
+		if length > maxFrame+uint32(c.dec.Overhead()) {
+			return 0, ErrFrameTooLarge
+		}
+		sealed := make([]byte, length)
+		if _, err := io.ReadFull(c.Conn, sealed); err != nil {
+			return 0, err
+		}
+		plain, err := c.dec.Open(nil, nonceOf(c.dec, c.rnonce), sealed, nil)
+		if err != nil {
+			return 0, err
+		}
+		c.rnonce++
+		c.rbuf = plain
+	}
+	n := copy(b, c.rbuf)
+	c.rbuf = c.rbuf[n:]
+	return n, nil
+}
+
+// transcript is what a side signs to prove its identity in the handshake, it binds the key to both
+// ephemeral keys and to the role of the side so a signature can not be replayed or reflected
+func transcript(initiator bool, own, other []byte) []byte {
+	role := []byte("r")
+	if initiator {
+		role = []byte("i")
+	}
+	return common.Sha256(bytes.Join([][]byte{[]byte(handshakeLabel), role, own, other}, nil))
+}
+
+func sessionKey(shared, ei, er []byte, dir string) []byte {
+	return common.Sha256(bytes.Join([][]byte{[]byte(handshakeLabel), shared, ei, er, []byte(dir)}, nil))
+}
+
+// exchange writes out while reading len(in) bytes into in, so that it works whichever side writes
+// first even on an unbuffered connection.
+func exchange(conn net.Conn, out, in []byte) error {
+	werr := make(chan error, 1)
+	go func() {
+		_, err := conn.Write(out)
+		werr <- err
+	}()
+	if _, err := io.ReadFull(conn, in); err != nil {
+		return err
+	}
+	return <-werr
+}
+
+// handshake authenticates conn and sets up its encryption. Both sides exchange ephemeral X25519
+// keys, derive one key per direction, then prove their node keys over the encrypted channel.
+// If expect is not empty the remote node must be expect. Returns the encrypted connection and the
+// remote node, at the remote address and its listen port
+func handshake(conn net.Conn, key ed25519.PrivateKey, port uint16, initiator bool, expect discover.NodeID) (net.Conn, *discover.Node, error) {
+	conn.SetDeadline(time.Now().Add(HandshakeTimeout))
+	defer conn.SetDeadline(time.Time{})
+
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	own := eph.PublicKey().Bytes()
+	other := make([]byte, len(own))
+	if err := exchange(conn, own, other); err != nil {
+		return nil, nil, err
+	}
+	otherKey, err := ecdh.X25519().NewPublicKey(other)
+	if err != nil {
+		return nil, nil, ErrHandshake
+	}
+	shared, err := eph.ECDH(otherKey)
+	if err != nil {
+		return nil, nil, ErrHandshake
+	}
+
+	ei, er := own, other
+	if !initiator {
+		ei, er = other, own
+	}
+	i2r, err := newAEAD(sessionKey(shared, ei, er, "i2r"))
+	if err != nil {
+		return nil, nil, err
+	}
+	r2i, err := newAEAD(sessionKey(shared, ei, er, "r2i"))
+	if err != nil {
+		return nil, nil, err
+	}
+	sc := &secureConn{Conn: conn, enc: i2r, dec: r2i}
+	if !initiator {
+		sc.enc, sc.dec = r2i, i2r
+	}
+
+	auth := make([]byte, 0, authLength)
+	auth = append(auth, key.Public().(ed25519.PublicKey)...)
+	auth = append(auth, byte(port>>8), byte(port))
+	auth = append(auth, ed25519.Sign(key, transcript(initiator, own, other))...)
+	remoteAuth := make([]byte, authLength)
+	if err := exchange(sc, auth, remoteAuth); err != nil {
+		return nil, nil, err
+	}
+	pub := ed25519.PublicKey(remoteAuth[:ed25519.PublicKeySize])
+	remotePort := uint16(remoteAuth[ed25519.PublicKeySize])<<8 | uint16(remoteAuth[ed25519.PublicKeySize+1])
+	sig := remoteAuth[ed25519.PublicKeySize+2:]
+	if !ed25519.Verify(pub, transcript(!initiator, other, own), sig) {
+		return nil, nil, ErrHandshake
+	}
+	id := discover.PubkeyID(pub)
+	if expect != "" && id != expect {
+		return nil, nil, ErrUnexpectedIdentity
+	}
+
+	var ip net.IP
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		ip = addr.IP
+	}
+	return sc, discover.NewNode(id, ip, remotePort, remotePort), nil
+}
@@ -0,0 +1,189 @@
+package network
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// send priorities of requests, a peer is sent the requests of a lower priority first
+const (
+	PriorityConsensus = iota
+	PriorityBlock
+	PrioritySync
+	PriorityTx
+	numPriorities
+)
+
+var priorityNames = [numPriorities]string{"consensus", "block", "sync", "tx"}
+
+// limits of the send queues of a peer
+var (
+	// SendQueueSize bounds the requests queued for a connection, by priority
+	SendQueueSize = [numPriorities]int{
+		PriorityConsensus: 256,
+		PriorityBlock:     64,
+		PrioritySync:      256,
+		PriorityTx:        1024,
+	}
+	// DropOldest tells, by priority, whether the oldest request queued is dropped for a new one
+	// once the queue is full, or the new one is dropped. New blocks supersede the old ones, while
+	// the txs and sync requests queued first are answered first.
+	DropOldest = [numPriorities]bool{
+		PriorityConsensus: true,
+		PriorityBlock:     true,
+	}
+	// WriteTimeout bounds the write of FrameChunkSize bytes of a request, a frame in framing v2,
+	// the peer is disconnected past it
+	WriteTimeout = 800 * time.Millisecond
+)
+
+// priorityOfReqType is the priority every ReqType is sent at, other types are sent at
+// PriorityTx.
+var priorityOfReqType = map[ReqType]int{
+	ReqBlockHeight:  PriorityConsensus,
+	RecvBlockHeight: PriorityConsensus,
+
+	ReqNewBlock:     PriorityBlock,
+	ReqCompactBlock: PriorityBlock,
+	ReqBlockTxs:     PriorityBlock,
+	RecvBlockTxs:    PriorityBlock,
+
+	ReqDownloadBlock:     PrioritySync,
+	BlockHashQuery:       PrioritySync,
+	BlockHashResponse:    PrioritySync,
+	ReqSyncBlock:         PrioritySync,
+	ReqBlockHeaders:      PrioritySync,
+	RecvBlockHeaders:     PrioritySync,
+	ReqSnapshotManifest:  PrioritySync,
+	RecvSnapshotManifest: PrioritySync,
+	ReqSnapshotChunk:     PrioritySync,
+	RecvSnapshotChunk:    PrioritySync,
+}
+
+// RegisterPriority makes types sent at priority.
+func RegisterPriority(priority int, types ...ReqType) {
+	for _, t := range types {
+		priorityOfReqType[t] = priority
+	}
+}
+
+// priorityOf returns the priority messages of reqType are sent at.
+func priorityOf(reqType int32) int {
+	if p, ok := priorityOfReqType[ReqType(reqType)]; ok {
+		return p
+	}
+	return PriorityTx
+}
+
+// sendQueue is the requests waiting to be written to a connection, they are written by its own
+// goroutine so that a slow peer does not hold up the sender.
+type sendQueue struct {
+	mu      sync.Mutex
+	queued  [numPriorities][]*Request
+	signal  chan struct{}
+	done    chan struct{}
+	stopped bool
+
+	conn    net.Conn
+	write   func(net.Conn, *Request) error
+	onError func()
+}
+
+func newSendQueue(conn net.Conn, write func(net.Conn, *Request) error, onError func()) *sendQueue {
+	q := &sendQueue{
+		signal:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		conn:    conn,
+		write:   write,
+		onError: onError,
+	}
+	go q.writeLoop()
+	return q
+}
+
+// push queues r at priority, it returns false if a request was dropped for the queue is full.
+func (q *sendQueue) push(r *Request, priority int) bool {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return false
+	}
+	dropped := false
+	if len(q.queued[priority]) >= SendQueueSize[priority] {
+		dropped = true
+		sendQueueDrops.WithLabelValues(priorityNames[priority]).Inc()
+		if !DropOldest[priority] {
+			q.mu.Unlock()
+			return false
+		}
+		q.queued[priority][0] = nil
+		q.queued[priority] = q.queued[priority][1:]
+		sendQueueDepth.WithLabelValues(priorityNames[priority]).Dec()
+	}
+	q.queued[priority] = append(q.queued[priority], r)
+	sendQueueDepth.WithLabelValues(priorityNames[priority]).Inc()
+	q.mu.Unlock()
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+	return !dropped
+}
+
+// pop returns the first request of the highest priority, nil if none is queued.
+func (q *sendQueue) pop() *Request {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for p := range q.queued {
+		if len(q.queued[p]) == 0 {
+			continue
+		}
+		r := q.queued[p][0]
+		q.queued[p][0] = nil
+		q.queued[p] = q.queued[p][1:]
+		sendQueueDepth.WithLabelValues(priorityNames[p]).Dec()
+		return r
+	}
+	return nil
+}
+
+// len returns the number of requests queued at priority.
+func (q *sendQueue) len(priority int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queued[priority])
+}
+
+// stop stops the writing, the requests queued are dropped.
+func (q *sendQueue) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped {
+		return
+	}
+	q.stopped = true
+	for p := range q.queued {
+		sendQueueDepth.WithLabelValues(priorityNames[p]).Sub(float64(len(q.queued[p])))
+		q.queued[p] = nil
+	}
+	close(q.done)
+}
+
+func (q *sendQueue) writeLoop() {
+	for {
+		select {
+		case <-q.signal:
+		case <-q.done:
+			return
+		}
+		for r := q.pop(); r != nil; r = q.pop() {
+			if err := q.write(q.conn, r); err != nil {
+				q.stop()
+				q.onError()
+				return
+			}
+		}
+	}
+}
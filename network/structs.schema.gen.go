@@ -0,0 +1,627 @@
+package network
+
+import (
+	"io"
+	"time"
+	"unsafe"
+)
+
+var (
+	_ = unsafe.Sizeof(0)
+	_ = io.ReadFull
+	_ = time.Now()
+)
+
+type Cap struct {
+	Name    string
+	Version uint32
+}
+
+func (d *Cap) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Name))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 4
+	return
+}
+func (d *Cap) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Name))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Name)
+		i += l
+	}
+	{
+
+		buf[i+0+0] = byte(d.Version >> 0)
+
+		buf[i+1+0] = byte(d.Version >> 8)
+
+		buf[i+2+0] = byte(d.Version >> 16)
+
+		buf[i+3+0] = byte(d.Version >> 24)
+
+	}
+	return buf[:i+4], nil
+}
+
+func (d *Cap) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Name = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+
+		d.Version = 0 | (uint32(buf[i+0+0]) << 0) | (uint32(buf[i+1+0]) << 8) | (uint32(buf[i+2+0]) << 16) | (uint32(buf[i+3+0]) << 24)
+
+	}
+	return i + 4, nil
+}
+
+type Hello struct {
+	Version    uint32
+	Caps       []Cap
+	ChainID    int64
+	Genesis    []byte
+	HeadHeight uint64
+	NodeID     string
+}
+
+func (d *Hello) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Caps))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Caps {
+
+			{
+				s += d.Caps[k0].Size()
+			}
+
+		}
+
+	}
+	{
+		l := uint64(len(d.Genesis))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.NodeID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 20
+	return
+}
+func (d *Hello) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+
+		buf[0+0] = byte(d.Version >> 0)
+
+		buf[1+0] = byte(d.Version >> 8)
+
+		buf[2+0] = byte(d.Version >> 16)
+
+		buf[3+0] = byte(d.Version >> 24)
+
+	}
+	{
+		l := uint64(len(d.Caps))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+4] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+4] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Caps {
+
+			{
+				nbuf, err := d.Caps[k0].Marshal(buf[i+4:])
+				if err != nil {
+					return nil, err
+				}
+				i += uint64(len(nbuf))
+			}
+
+		}
+	}
+	{
+
+		buf[i+0+4] = byte(d.ChainID >> 0)
+
+		buf[i+1+4] = byte(d.ChainID >> 8)
+
+		buf[i+2+4] = byte(d.ChainID >> 16)
+
+		buf[i+3+4] = byte(d.ChainID >> 24)
+
+		buf[i+4+4] = byte(d.ChainID >> 32)
+
+		buf[i+5+4] = byte(d.ChainID >> 40)
+
+		buf[i+6+4] = byte(d.ChainID >> 48)
+
+		buf[i+7+4] = byte(d.ChainID >> 56)
+
+	}
+	{
+		l := uint64(len(d.Genesis))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+12] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+12] = byte(t)
+			i++
+
+		}
+		copy(buf[i+12:], d.Genesis)
+		i += l
+	}
+	{
+
+		buf[i+0+12] = byte(d.HeadHeight >> 0)
+
+		buf[i+1+12] = byte(d.HeadHeight >> 8)
+
+		buf[i+2+12] = byte(d.HeadHeight >> 16)
+
+		buf[i+3+12] = byte(d.HeadHeight >> 24)
+
+		buf[i+4+12] = byte(d.HeadHeight >> 32)
+
+		buf[i+5+12] = byte(d.HeadHeight >> 40)
+
+		buf[i+6+12] = byte(d.HeadHeight >> 48)
+
+		buf[i+7+12] = byte(d.HeadHeight >> 56)
+
+	}
+	{
+		l := uint64(len(d.NodeID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+20] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+20] = byte(t)
+			i++
+
+		}
+		copy(buf[i+20:], d.NodeID)
+		i += l
+	}
+	return buf[:i+20], nil
+}
+
+func (d *Hello) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+
+		d.Version = 0 | (uint32(buf[i+0+0]) << 0) | (uint32(buf[i+1+0]) << 8) | (uint32(buf[i+2+0]) << 16) | (uint32(buf[i+3+0]) << 24)
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+4] & 0x7F)
+			for buf[i+4]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+4]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Caps)) >= l {
+			d.Caps = d.Caps[:l]
+		} else {
+			d.Caps = make([]Cap, l)
+		}
+		for k0 := range d.Caps {
+
+			{
+				ni, err := d.Caps[k0].Unmarshal(buf[i+4:])
+				if err != nil {
+					return 0, err
+				}
+				i += ni
+			}
+
+		}
+	}
+	{
+
+		d.ChainID = 0 | (int64(buf[i+0+4]) << 0) | (int64(buf[i+1+4]) << 8) | (int64(buf[i+2+4]) << 16) | (int64(buf[i+3+4]) << 24) | (int64(buf[i+4+4]) << 32) | (int64(buf[i+5+4]) << 40) | (int64(buf[i+6+4]) << 48) | (int64(buf[i+7+4]) << 56)
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+12] & 0x7F)
+			for buf[i+12]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+12]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Genesis)) >= l {
+			d.Genesis = d.Genesis[:l]
+		} else {
+			d.Genesis = make([]byte, l)
+		}
+		copy(d.Genesis, buf[i+12:])
+		i += l
+	}
+	{
+
+		d.HeadHeight = 0 | (uint64(buf[i+0+12]) << 0) | (uint64(buf[i+1+12]) << 8) | (uint64(buf[i+2+12]) << 16) | (uint64(buf[i+3+12]) << 24) | (uint64(buf[i+4+12]) << 32) | (uint64(buf[i+5+12]) << 40) | (uint64(buf[i+6+12]) << 48) | (uint64(buf[i+7+12]) << 56)
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+20] & 0x7F)
+			for buf[i+20]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+20]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.NodeID = string(buf[i+20 : i+20+l])
+		i += l
+	}
+	return i + 20, nil
+}
+
+type Disconnect struct {
+	Reason uint32
+}
+
+func (d *Disconnect) Size() (s uint64) {
+
+	s += 4
+	return
+}
+func (d *Disconnect) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+
+		buf[0+0] = byte(d.Reason >> 0)
+
+		buf[1+0] = byte(d.Reason >> 8)
+
+		buf[2+0] = byte(d.Reason >> 16)
+
+		buf[3+0] = byte(d.Reason >> 24)
+
+	}
+	return buf[:i+4], nil
+}
+
+func (d *Disconnect) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+
+		d.Reason = 0 | (uint32(buf[0+0]) << 0) | (uint32(buf[1+0]) << 8) | (uint32(buf[2+0]) << 16) | (uint32(buf[3+0]) << 24)
+
+	}
+	return i + 4, nil
+}
+
+type MsgIDs struct {
+	ReqType int32
+	IDs     [][]byte
+}
+
+func (d *MsgIDs) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.IDs))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.IDs {
+
+			{
+				l := uint64(len(d.IDs[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	s += 4
+	return
+}
+func (d *MsgIDs) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+
+		buf[0+0] = byte(d.ReqType >> 0)
+
+		buf[1+0] = byte(d.ReqType >> 8)
+
+		buf[2+0] = byte(d.ReqType >> 16)
+
+		buf[3+0] = byte(d.ReqType >> 24)
+
+	}
+	{
+		l := uint64(len(d.IDs))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+4] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+4] = byte(t)
+			i++
+
+		}
+		for k0 := range d.IDs {
+
+			{
+				l := uint64(len(d.IDs[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+4] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+4] = byte(t)
+					i++
+
+				}
+				copy(buf[i+4:], d.IDs[k0])
+				i += l
+			}
+
+		}
+	}
+	return buf[:i+4], nil
+}
+
+func (d *MsgIDs) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+
+		d.ReqType = 0 | (int32(buf[i+0+0]) << 0) | (int32(buf[i+1+0]) << 8) | (int32(buf[i+2+0]) << 16) | (int32(buf[i+3+0]) << 24)
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+4] & 0x7F)
+			for buf[i+4]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+4]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.IDs)) >= l {
+			d.IDs = d.IDs[:l]
+		} else {
+			d.IDs = make([][]byte, l)
+		}
+		for k0 := range d.IDs {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+4] & 0x7F)
+					for buf[i+4]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+4]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				if uint64(cap(d.IDs[k0])) >= l {
+					d.IDs[k0] = d.IDs[k0][:l]
+				} else {
+					d.IDs[k0] = make([]byte, l)
+				}
+				copy(d.IDs[k0], buf[i+4:])
+				i += l
+			}
+
+		}
+	}
+	return i + 4, nil
+}
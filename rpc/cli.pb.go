@@ -36,7 +36,7 @@ func (m *TransInfo) Reset()         { *m = TransInfo{} }
 func (m *TransInfo) String() string { return proto.CompactTextString(m) }
 func (*TransInfo) ProtoMessage()    {}
 func (*TransInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{0}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{0}
 }
 func (m *TransInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TransInfo.Unmarshal(m, b)
@@ -88,7 +88,7 @@ func (m *Transaction) Reset()         { *m = Transaction{} }
 func (m *Transaction) String() string { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()    {}
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{1}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{1}
 }
 func (m *Transaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Transaction.Unmarshal(m, b)
@@ -127,7 +127,7 @@ func (m *PublishRet) Reset()         { *m = PublishRet{} }
 func (m *PublishRet) String() string { return proto.CompactTextString(m) }
 func (*PublishRet) ProtoMessage()    {}
 func (*PublishRet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{2}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{2}
 }
 func (m *PublishRet) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PublishRet.Unmarshal(m, b)
@@ -172,7 +172,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{3}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{3}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Response.Unmarshal(m, b)
@@ -211,7 +211,7 @@ func (m *TransactionKey) Reset()         { *m = TransactionKey{} }
 func (m *TransactionKey) String() string { return proto.CompactTextString(m) }
 func (*TransactionKey) ProtoMessage()    {}
 func (*TransactionKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{4}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{4}
 }
 func (m *TransactionKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TransactionKey.Unmarshal(m, b)
@@ -256,7 +256,7 @@ func (m *TransactionHash) Reset()         { *m = TransactionHash{} }
 func (m *TransactionHash) String() string { return proto.CompactTextString(m) }
 func (*TransactionHash) ProtoMessage()    {}
 func (*TransactionHash) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{5}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{5}
 }
 func (m *TransactionHash) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TransactionHash.Unmarshal(m, b)
@@ -294,7 +294,7 @@ func (m *Key) Reset()         { *m = Key{} }
 func (m *Key) String() string { return proto.CompactTextString(m) }
 func (*Key) ProtoMessage()    {}
 func (*Key) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{6}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{6}
 }
 func (m *Key) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Key.Unmarshal(m, b)
@@ -332,7 +332,7 @@ func (m *Value) Reset()         { *m = Value{} }
 func (m *Value) String() string { return proto.CompactTextString(m) }
 func (*Value) ProtoMessage()    {}
 func (*Value) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{7}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{7}
 }
 func (m *Value) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Value.Unmarshal(m, b)
@@ -370,7 +370,7 @@ func (m *BlockKey) Reset()         { *m = BlockKey{} }
 func (m *BlockKey) String() string { return proto.CompactTextString(m) }
 func (*BlockKey) ProtoMessage()    {}
 func (*BlockKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{8}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{8}
 }
 func (m *BlockKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockKey.Unmarshal(m, b)
@@ -416,7 +416,7 @@ func (m *Head) Reset()         { *m = Head{} }
 func (m *Head) String() string { return proto.CompactTextString(m) }
 func (*Head) ProtoMessage()    {}
 func (*Head) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{9}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{9}
 }
 func (m *Head) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Head.Unmarshal(m, b)
@@ -512,7 +512,7 @@ func (m *BlockInfo) Reset()         { *m = BlockInfo{} }
 func (m *BlockInfo) String() string { return proto.CompactTextString(m) }
 func (*BlockInfo) ProtoMessage()    {}
 func (*BlockInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_441c81ee1c4e0f3c, []int{10}
+	return fileDescriptor_cli_8b82976ac9544e07, []int{10}
 }
 func (m *BlockInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockInfo.Unmarshal(m, b)
@@ -553,6 +553,610 @@ func (m *BlockInfo) GetTxList() []*TransactionKey {
 	return nil
 }
 
+type NFTList struct {
+	Tokens               []string `protobuf:"bytes,1,rep,name=tokens" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NFTList) Reset()         { *m = NFTList{} }
+func (m *NFTList) String() string { return proto.CompactTextString(m) }
+func (*NFTList) ProtoMessage()    {}
+func (*NFTList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{11}
+}
+func (m *NFTList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NFTList.Unmarshal(m, b)
+}
+func (m *NFTList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NFTList.Marshal(b, m, deterministic)
+}
+func (dst *NFTList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NFTList.Merge(dst, src)
+}
+func (m *NFTList) XXX_Size() int {
+	return xxx_messageInfo_NFTList.Size(m)
+}
+func (m *NFTList) XXX_DiscardUnknown() {
+	xxx_messageInfo_NFTList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NFTList proto.InternalMessageInfo
+
+func (m *NFTList) GetTokens() []string {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type NFTInfo struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Owner                string   `protobuf:"bytes,2,opt,name=owner" json:"owner,omitempty"`
+	Issuer               string   `protobuf:"bytes,3,opt,name=issuer" json:"issuer,omitempty"`
+	Metadata             []byte   `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NFTInfo) Reset()         { *m = NFTInfo{} }
+func (m *NFTInfo) String() string { return proto.CompactTextString(m) }
+func (*NFTInfo) ProtoMessage()    {}
+func (*NFTInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{12}
+}
+func (m *NFTInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NFTInfo.Unmarshal(m, b)
+}
+func (m *NFTInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NFTInfo.Marshal(b, m, deterministic)
+}
+func (dst *NFTInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NFTInfo.Merge(dst, src)
+}
+func (m *NFTInfo) XXX_Size() int {
+	return xxx_messageInfo_NFTInfo.Size(m)
+}
+func (m *NFTInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_NFTInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NFTInfo proto.InternalMessageInfo
+
+func (m *NFTInfo) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NFTInfo) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *NFTInfo) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *NFTInfo) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type TxPoolStats struct {
+	Total                int64    `protobuf:"varint,1,opt,name=total" json:"total,omitempty"`
+	Pending              int64    `protobuf:"varint,2,opt,name=pending" json:"pending,omitempty"`
+	Future               int64    `protobuf:"varint,3,opt,name=future" json:"future,omitempty"`
+	Senders              int64    `protobuf:"varint,4,opt,name=senders" json:"senders,omitempty"`
+	Evicted              int64    `protobuf:"varint,5,opt,name=evicted" json:"evicted,omitempty"`
+	Replaced             int64    `protobuf:"varint,6,opt,name=replaced" json:"replaced,omitempty"`
+	SenderQueued         int64    `protobuf:"varint,7,opt,name=senderQueued" json:"senderQueued,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxPoolStats) Reset()         { *m = TxPoolStats{} }
+func (m *TxPoolStats) String() string { return proto.CompactTextString(m) }
+func (*TxPoolStats) ProtoMessage()    {}
+func (*TxPoolStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{13}
+}
+func (m *TxPoolStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxPoolStats.Unmarshal(m, b)
+}
+func (m *TxPoolStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxPoolStats.Marshal(b, m, deterministic)
+}
+func (dst *TxPoolStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxPoolStats.Merge(dst, src)
+}
+func (m *TxPoolStats) XXX_Size() int {
+	return xxx_messageInfo_TxPoolStats.Size(m)
+}
+func (m *TxPoolStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxPoolStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxPoolStats proto.InternalMessageInfo
+
+func (m *TxPoolStats) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *TxPoolStats) GetPending() int64 {
+	if m != nil {
+		return m.Pending
+	}
+	return 0
+}
+
+func (m *TxPoolStats) GetFuture() int64 {
+	if m != nil {
+		return m.Future
+	}
+	return 0
+}
+
+func (m *TxPoolStats) GetSenders() int64 {
+	if m != nil {
+		return m.Senders
+	}
+	return 0
+}
+
+func (m *TxPoolStats) GetEvicted() int64 {
+	if m != nil {
+		return m.Evicted
+	}
+	return 0
+}
+
+func (m *TxPoolStats) GetReplaced() int64 {
+	if m != nil {
+		return m.Replaced
+	}
+	return 0
+}
+
+func (m *TxPoolStats) GetSenderQueued() int64 {
+	if m != nil {
+		return m.SenderQueued
+	}
+	return 0
+}
+
+type RewardReceipt struct {
+	Number               int64    `protobuf:"varint,1,opt,name=number" json:"number,omitempty"`
+	Witness              string   `protobuf:"bytes,2,opt,name=witness" json:"witness,omitempty"`
+	Fees                 float64  `protobuf:"fixed64,3,opt,name=fees" json:"fees,omitempty"`
+	Reward               float64  `protobuf:"fixed64,4,opt,name=reward" json:"reward,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RewardReceipt) Reset()         { *m = RewardReceipt{} }
+func (m *RewardReceipt) String() string { return proto.CompactTextString(m) }
+func (*RewardReceipt) ProtoMessage()    {}
+func (*RewardReceipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{14}
+}
+func (m *RewardReceipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RewardReceipt.Unmarshal(m, b)
+}
+func (m *RewardReceipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RewardReceipt.Marshal(b, m, deterministic)
+}
+func (dst *RewardReceipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RewardReceipt.Merge(dst, src)
+}
+func (m *RewardReceipt) XXX_Size() int {
+	return xxx_messageInfo_RewardReceipt.Size(m)
+}
+func (m *RewardReceipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_RewardReceipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RewardReceipt proto.InternalMessageInfo
+
+func (m *RewardReceipt) GetNumber() int64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *RewardReceipt) GetWitness() string {
+	if m != nil {
+		return m.Witness
+	}
+	return ""
+}
+
+func (m *RewardReceipt) GetFees() float64 {
+	if m != nil {
+		return m.Fees
+	}
+	return 0
+}
+
+func (m *RewardReceipt) GetReward() float64 {
+	if m != nil {
+		return m.Reward
+	}
+	return 0
+}
+
+type Rewards struct {
+	Witness              string           `protobuf:"bytes,1,opt,name=witness" json:"witness,omitempty"`
+	Total                float64          `protobuf:"fixed64,2,opt,name=total" json:"total,omitempty"`
+	Receipts             []*RewardReceipt `protobuf:"bytes,3,rep,name=receipts" json:"receipts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Rewards) Reset()         { *m = Rewards{} }
+func (m *Rewards) String() string { return proto.CompactTextString(m) }
+func (*Rewards) ProtoMessage()    {}
+func (*Rewards) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{15}
+}
+func (m *Rewards) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Rewards.Unmarshal(m, b)
+}
+func (m *Rewards) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Rewards.Marshal(b, m, deterministic)
+}
+func (dst *Rewards) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rewards.Merge(dst, src)
+}
+func (m *Rewards) XXX_Size() int {
+	return xxx_messageInfo_Rewards.Size(m)
+}
+func (m *Rewards) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rewards.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rewards proto.InternalMessageInfo
+
+func (m *Rewards) GetWitness() string {
+	if m != nil {
+		return m.Witness
+	}
+	return ""
+}
+
+func (m *Rewards) GetTotal() float64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *Rewards) GetReceipts() []*RewardReceipt {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
+type ServiScore struct {
+	Account              string   `protobuf:"bytes,1,opt,name=account" json:"account,omitempty"`
+	Score                float64  `protobuf:"fixed64,2,opt,name=score" json:"score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiScore) Reset()         { *m = ServiScore{} }
+func (m *ServiScore) String() string { return proto.CompactTextString(m) }
+func (*ServiScore) ProtoMessage()    {}
+func (*ServiScore) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{16}
+}
+func (m *ServiScore) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiScore.Unmarshal(m, b)
+}
+func (m *ServiScore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiScore.Marshal(b, m, deterministic)
+}
+func (dst *ServiScore) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiScore.Merge(dst, src)
+}
+func (m *ServiScore) XXX_Size() int {
+	return xxx_messageInfo_ServiScore.Size(m)
+}
+func (m *ServiScore) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiScore.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiScore proto.InternalMessageInfo
+
+func (m *ServiScore) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *ServiScore) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+type ServiScores struct {
+	Height               int64         `protobuf:"varint,1,opt,name=height" json:"height,omitempty"`
+	Scores               []*ServiScore `protobuf:"bytes,2,rep,name=scores" json:"scores,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ServiScores) Reset()         { *m = ServiScores{} }
+func (m *ServiScores) String() string { return proto.CompactTextString(m) }
+func (*ServiScores) ProtoMessage()    {}
+func (*ServiScores) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{17}
+}
+func (m *ServiScores) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiScores.Unmarshal(m, b)
+}
+func (m *ServiScores) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiScores.Marshal(b, m, deterministic)
+}
+func (dst *ServiScores) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiScores.Merge(dst, src)
+}
+func (m *ServiScores) XXX_Size() int {
+	return xxx_messageInfo_ServiScores.Size(m)
+}
+func (m *ServiScores) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiScores.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiScores proto.InternalMessageInfo
+
+func (m *ServiScores) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *ServiScores) GetScores() []*ServiScore {
+	if m != nil {
+		return m.Scores
+	}
+	return nil
+}
+
+type SyncProgress struct {
+	Syncing              bool     `protobuf:"varint,1,opt,name=syncing" json:"syncing,omitempty"`
+	Start                int64    `protobuf:"varint,2,opt,name=start" json:"start,omitempty"`
+	Current              int64    `protobuf:"varint,3,opt,name=current" json:"current,omitempty"`
+	Headers              int64    `protobuf:"varint,4,opt,name=headers" json:"headers,omitempty"`
+	Target               int64    `protobuf:"varint,5,opt,name=target" json:"target,omitempty"`
+	Peers                int64    `protobuf:"varint,6,opt,name=peers" json:"peers,omitempty"`
+	Rate                 float64  `protobuf:"fixed64,7,opt,name=rate" json:"rate,omitempty"`
+	Snapshot             int64    `protobuf:"varint,8,opt,name=snapshot" json:"snapshot,omitempty"`
+	Chunks               int64    `protobuf:"varint,9,opt,name=chunks" json:"chunks,omitempty"`
+	ChunksDone           int64    `protobuf:"varint,10,opt,name=chunks_done,json=chunksDone" json:"chunks_done,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SyncProgress) Reset()         { *m = SyncProgress{} }
+func (m *SyncProgress) String() string { return proto.CompactTextString(m) }
+func (*SyncProgress) ProtoMessage()    {}
+func (*SyncProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{18}
+}
+func (m *SyncProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncProgress.Unmarshal(m, b)
+}
+func (m *SyncProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncProgress.Marshal(b, m, deterministic)
+}
+func (dst *SyncProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncProgress.Merge(dst, src)
+}
+func (m *SyncProgress) XXX_Size() int {
+	return xxx_messageInfo_SyncProgress.Size(m)
+}
+func (m *SyncProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncProgress proto.InternalMessageInfo
+
+func (m *SyncProgress) GetSyncing() bool {
+	if m != nil {
+		return m.Syncing
+	}
+	return false
+}
+
+func (m *SyncProgress) GetStart() int64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetCurrent() int64 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetHeaders() int64 {
+	if m != nil {
+		return m.Headers
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetTarget() int64 {
+	if m != nil {
+		return m.Target
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetPeers() int64 {
+	if m != nil {
+		return m.Peers
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetRate() float64 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetSnapshot() int64 {
+	if m != nil {
+		return m.Snapshot
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetChunks() int64 {
+	if m != nil {
+		return m.Chunks
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetChunksDone() int64 {
+	if m != nil {
+		return m.ChunksDone
+	}
+	return 0
+}
+
+type PeerEntry struct {
+	List                 string   `protobuf:"bytes,1,opt,name=list" json:"list,omitempty"`
+	Entry                string   `protobuf:"bytes,2,opt,name=entry" json:"entry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerEntry) Reset()         { *m = PeerEntry{} }
+func (m *PeerEntry) String() string { return proto.CompactTextString(m) }
+func (*PeerEntry) ProtoMessage()    {}
+func (*PeerEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{19}
+}
+func (m *PeerEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerEntry.Unmarshal(m, b)
+}
+func (m *PeerEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerEntry.Marshal(b, m, deterministic)
+}
+func (dst *PeerEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerEntry.Merge(dst, src)
+}
+func (m *PeerEntry) XXX_Size() int {
+	return xxx_messageInfo_PeerEntry.Size(m)
+}
+func (m *PeerEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerEntry proto.InternalMessageInfo
+
+func (m *PeerEntry) GetList() string {
+	if m != nil {
+		return m.List
+	}
+	return ""
+}
+
+func (m *PeerEntry) GetEntry() string {
+	if m != nil {
+		return m.Entry
+	}
+	return ""
+}
+
+type PeerLists struct {
+	Static               []string `protobuf:"bytes,1,rep,name=static" json:"static,omitempty"`
+	Trusted              []string `protobuf:"bytes,2,rep,name=trusted" json:"trusted,omitempty"`
+	Allowed              []string `protobuf:"bytes,3,rep,name=allowed" json:"allowed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerLists) Reset()         { *m = PeerLists{} }
+func (m *PeerLists) String() string { return proto.CompactTextString(m) }
+func (*PeerLists) ProtoMessage()    {}
+func (*PeerLists) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_8b82976ac9544e07, []int{20}
+}
+func (m *PeerLists) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerLists.Unmarshal(m, b)
+}
+func (m *PeerLists) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerLists.Marshal(b, m, deterministic)
+}
+func (dst *PeerLists) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerLists.Merge(dst, src)
+}
+func (m *PeerLists) XXX_Size() int {
+	return xxx_messageInfo_PeerLists.Size(m)
+}
+func (m *PeerLists) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerLists.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerLists proto.InternalMessageInfo
+
+func (m *PeerLists) GetStatic() []string {
+	if m != nil {
+		return m.Static
+	}
+	return nil
+}
+
+func (m *PeerLists) GetTrusted() []string {
+	if m != nil {
+		return m.Trusted
+	}
+	return nil
+}
+
+func (m *PeerLists) GetAllowed() []string {
+	if m != nil {
+		return m.Allowed
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*TransInfo)(nil), "rpc.TransInfo")
 	proto.RegisterType((*Transaction)(nil), "rpc.Transaction")
@@ -565,6 +1169,16 @@ func init() {
 	proto.RegisterType((*BlockKey)(nil), "rpc.BlockKey")
 	proto.RegisterType((*Head)(nil), "rpc.Head")
 	proto.RegisterType((*BlockInfo)(nil), "rpc.BlockInfo")
+	proto.RegisterType((*NFTList)(nil), "rpc.NFTList")
+	proto.RegisterType((*NFTInfo)(nil), "rpc.NFTInfo")
+	proto.RegisterType((*TxPoolStats)(nil), "rpc.TxPoolStats")
+	proto.RegisterType((*RewardReceipt)(nil), "rpc.RewardReceipt")
+	proto.RegisterType((*Rewards)(nil), "rpc.Rewards")
+	proto.RegisterType((*ServiScore)(nil), "rpc.ServiScore")
+	proto.RegisterType((*ServiScores)(nil), "rpc.ServiScores")
+	proto.RegisterType((*SyncProgress)(nil), "rpc.SyncProgress")
+	proto.RegisterType((*PeerEntry)(nil), "rpc.PeerEntry")
+	proto.RegisterType((*PeerLists)(nil), "rpc.PeerLists")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -587,6 +1201,16 @@ type CliClient interface {
 	GetBlock(ctx context.Context, in *BlockKey, opts ...grpc.CallOption) (*BlockInfo, error)
 	GetBlockByHeight(ctx context.Context, in *BlockKey, opts ...grpc.CallOption) (*BlockInfo, error)
 	Transfer(ctx context.Context, in *TransInfo, opts ...grpc.CallOption) (*PublishRet, error)
+	GetNFTsByOwner(ctx context.Context, in *Key, opts ...grpc.CallOption) (*NFTList, error)
+	GetNFTMetadata(ctx context.Context, in *Key, opts ...grpc.CallOption) (*NFTInfo, error)
+	GetNonce(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Value, error)
+	GetTxPoolStats(ctx context.Context, in *Key, opts ...grpc.CallOption) (*TxPoolStats, error)
+	GetRewards(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Rewards, error)
+	GetServi(ctx context.Context, in *Key, opts ...grpc.CallOption) (*ServiScores, error)
+	GetSyncProgress(ctx context.Context, in *Key, opts ...grpc.CallOption) (*SyncProgress, error)
+	GetPeerLists(ctx context.Context, in *Key, opts ...grpc.CallOption) (*PeerLists, error)
+	AddPeer(ctx context.Context, in *PeerEntry, opts ...grpc.CallOption) (*PeerLists, error)
+	RemovePeer(ctx context.Context, in *PeerEntry, opts ...grpc.CallOption) (*PeerLists, error)
 }
 
 type cliClient struct {
@@ -669,6 +1293,96 @@ func (c *cliClient) Transfer(ctx context.Context, in *TransInfo, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *cliClient) GetNFTsByOwner(ctx context.Context, in *Key, opts ...grpc.CallOption) (*NFTList, error) {
+	out := new(NFTList)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/GetNFTsByOwner", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliClient) GetNFTMetadata(ctx context.Context, in *Key, opts ...grpc.CallOption) (*NFTInfo, error) {
+	out := new(NFTInfo)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/GetNFTMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliClient) GetNonce(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Value, error) {
+	out := new(Value)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/GetNonce", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliClient) GetTxPoolStats(ctx context.Context, in *Key, opts ...grpc.CallOption) (*TxPoolStats, error) {
+	out := new(TxPoolStats)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/GetTxPoolStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliClient) GetRewards(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Rewards, error) {
+	out := new(Rewards)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/GetRewards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliClient) GetServi(ctx context.Context, in *Key, opts ...grpc.CallOption) (*ServiScores, error) {
+	out := new(ServiScores)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/GetServi", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliClient) GetSyncProgress(ctx context.Context, in *Key, opts ...grpc.CallOption) (*SyncProgress, error) {
+	out := new(SyncProgress)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/GetSyncProgress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliClient) GetPeerLists(ctx context.Context, in *Key, opts ...grpc.CallOption) (*PeerLists, error) {
+	out := new(PeerLists)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/GetPeerLists", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliClient) AddPeer(ctx context.Context, in *PeerEntry, opts ...grpc.CallOption) (*PeerLists, error) {
+	out := new(PeerLists)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/AddPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliClient) RemovePeer(ctx context.Context, in *PeerEntry, opts ...grpc.CallOption) (*PeerLists, error) {
+	out := new(PeerLists)
+	err := c.cc.Invoke(ctx, "/rpc.Cli/RemovePeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Cli service
 
 type CliServer interface {
@@ -680,6 +1394,16 @@ type CliServer interface {
 	GetBlock(context.Context, *BlockKey) (*BlockInfo, error)
 	GetBlockByHeight(context.Context, *BlockKey) (*BlockInfo, error)
 	Transfer(context.Context, *TransInfo) (*PublishRet, error)
+	GetNFTsByOwner(context.Context, *Key) (*NFTList, error)
+	GetNFTMetadata(context.Context, *Key) (*NFTInfo, error)
+	GetNonce(context.Context, *Key) (*Value, error)
+	GetTxPoolStats(context.Context, *Key) (*TxPoolStats, error)
+	GetRewards(context.Context, *Key) (*Rewards, error)
+	GetServi(context.Context, *Key) (*ServiScores, error)
+	GetSyncProgress(context.Context, *Key) (*SyncProgress, error)
+	GetPeerLists(context.Context, *Key) (*PeerLists, error)
+	AddPeer(context.Context, *PeerEntry) (*PeerLists, error)
+	RemovePeer(context.Context, *PeerEntry) (*PeerLists, error)
 }
 
 func RegisterCliServer(s *grpc.Server, srv CliServer) {
@@ -830,6 +1554,186 @@ func _Cli_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cli_GetNFTsByOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).GetNFTsByOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/GetNFTsByOwner",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).GetNFTsByOwner(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cli_GetNFTMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).GetNFTMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/GetNFTMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).GetNFTMetadata(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cli_GetNonce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).GetNonce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/GetNonce",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).GetNonce(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cli_GetTxPoolStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).GetTxPoolStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/GetTxPoolStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).GetTxPoolStats(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cli_GetRewards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).GetRewards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/GetRewards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).GetRewards(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cli_GetServi_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).GetServi(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/GetServi",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).GetServi(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cli_GetSyncProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).GetSyncProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/GetSyncProgress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).GetSyncProgress(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cli_GetPeerLists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).GetPeerLists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/GetPeerLists",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).GetPeerLists(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cli_AddPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerEntry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).AddPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/AddPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).AddPeer(ctx, req.(*PeerEntry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cli_RemovePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerEntry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliServer).RemovePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Cli/RemovePeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliServer).RemovePeer(ctx, req.(*PeerEntry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Cli_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "rpc.Cli",
 	HandlerType: (*CliServer)(nil),
@@ -866,49 +1770,126 @@ var _Cli_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Transfer",
 			Handler:    _Cli_Transfer_Handler,
 		},
+		{
+			MethodName: "GetNFTsByOwner",
+			Handler:    _Cli_GetNFTsByOwner_Handler,
+		},
+		{
+			MethodName: "GetNFTMetadata",
+			Handler:    _Cli_GetNFTMetadata_Handler,
+		},
+		{
+			MethodName: "GetNonce",
+			Handler:    _Cli_GetNonce_Handler,
+		},
+		{
+			MethodName: "GetTxPoolStats",
+			Handler:    _Cli_GetTxPoolStats_Handler,
+		},
+		{
+			MethodName: "GetRewards",
+			Handler:    _Cli_GetRewards_Handler,
+		},
+		{
+			MethodName: "GetServi",
+			Handler:    _Cli_GetServi_Handler,
+		},
+		{
+			MethodName: "GetSyncProgress",
+			Handler:    _Cli_GetSyncProgress_Handler,
+		},
+		{
+			MethodName: "GetPeerLists",
+			Handler:    _Cli_GetPeerLists_Handler,
+		},
+		{
+			MethodName: "AddPeer",
+			Handler:    _Cli_AddPeer_Handler,
+		},
+		{
+			MethodName: "RemovePeer",
+			Handler:    _Cli_RemovePeer_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "cli.proto",
 }
 
-func init() { proto.RegisterFile("cli.proto", fileDescriptor_cli_441c81ee1c4e0f3c) }
-
-var fileDescriptor_cli_441c81ee1c4e0f3c = []byte{
-	// 572 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x5d, 0x6f, 0x12, 0x41,
-	0x14, 0x65, 0x59, 0xa0, 0xbb, 0xb7, 0x95, 0x36, 0xd3, 0x46, 0x37, 0xc4, 0x36, 0xcd, 0x44, 0x13,
-	0x92, 0x46, 0x62, 0xa8, 0x2f, 0xbe, 0x19, 0x34, 0x01, 0x53, 0x1f, 0xcc, 0x8a, 0xbe, 0x0f, 0xcb,
-	0xa5, 0x3b, 0xe9, 0x32, 0xbb, 0x99, 0x19, 0x10, 0x7e, 0x85, 0xff, 0xd5, 0x5f, 0x60, 0xe6, 0xee,
-	0xf2, 0xd1, 0x8a, 0xf1, 0x6d, 0xce, 0xfd, 0x3a, 0xf7, 0x9e, 0x3d, 0x00, 0x61, 0x92, 0xc9, 0x5e,
-	0xa1, 0x73, 0x9b, 0x33, 0x5f, 0x17, 0x09, 0xff, 0x0e, 0xe1, 0x58, 0x0b, 0x65, 0x3e, 0xab, 0x59,
-	0xce, 0x9e, 0x43, 0xcb, 0x60, 0xf2, 0x80, 0xeb, 0xc8, 0xbb, 0xf6, 0xba, 0x61, 0x5c, 0x21, 0x76,
-	0x01, 0x4d, 0x95, 0xab, 0x04, 0xa3, 0xfa, 0xb5, 0xd7, 0xf5, 0xe3, 0x12, 0xb0, 0x0e, 0x04, 0x49,
-	0xae, 0xac, 0x16, 0x89, 0x8d, 0x7c, 0xaa, 0xdf, 0x62, 0x7e, 0x09, 0xc7, 0x34, 0x56, 0x24, 0x56,
-	0xe6, 0x8a, 0xb5, 0xa1, 0x6e, 0x57, 0x34, 0xf4, 0x24, 0xae, 0xdb, 0x15, 0x7f, 0x07, 0xf0, 0x75,
-	0x31, 0xc9, 0xa4, 0x49, 0x63, 0xb4, 0x8c, 0x41, 0x23, 0xc9, 0xa7, 0x48, 0xf9, 0x66, 0x4c, 0x6f,
-	0x17, 0x4b, 0x85, 0x49, 0x89, 0xf1, 0x24, 0xa6, 0x37, 0xbf, 0x82, 0x20, 0x46, 0x53, 0xe4, 0xca,
-	0xe0, 0xa1, 0x1e, 0xfe, 0x09, 0xda, 0x7b, 0xa4, 0x77, 0xb8, 0x66, 0x2f, 0x21, 0x2c, 0x4a, 0x1e,
-	0xd4, 0x15, 0xfd, 0x2e, 0x70, 0xf8, 0x2c, 0xfe, 0x1a, 0x4e, 0xf7, 0xa6, 0x8c, 0x84, 0x49, 0xb7,
-	0xcb, 0x78, 0x7b, 0xcb, 0x9c, 0x83, 0xef, 0x18, 0x4e, 0xc0, 0x33, 0x95, 0x5a, 0x9e, 0xe1, 0x2f,
-	0xa0, 0xf9, 0x43, 0x64, 0x0b, 0x74, 0x07, 0x9b, 0x25, 0xcd, 0x0d, 0xe3, 0xba, 0x59, 0xf2, 0x6b,
-	0x08, 0x06, 0x59, 0x9e, 0x3c, 0xdc, 0x95, 0x6a, 0x66, 0x62, 0x5d, 0x2d, 0xe4, 0xc7, 0x25, 0xe0,
-	0xbf, 0x3d, 0x68, 0x8c, 0x50, 0x4c, 0x59, 0x04, 0x47, 0x4b, 0xd4, 0x46, 0xe6, 0xaa, 0x2a, 0xd8,
-	0x40, 0x76, 0x05, 0x50, 0x08, 0x8d, 0xca, 0x8e, 0x76, 0xca, 0xec, 0x45, 0xdc, 0x07, 0xb1, 0x1a,
-	0x91, 0xb2, 0x3e, 0x65, 0xb7, 0xd8, 0x29, 0x31, 0x71, 0x0b, 0x50, 0xb2, 0x51, 0x2a, 0xb1, 0x0d,
-	0xb8, 0x03, 0xa5, 0x9a, 0xe5, 0x51, 0xb3, 0x3c, 0x50, 0x56, 0x66, 0x50, 0x8b, 0xf9, 0x04, 0x75,
-	0xd4, 0xa2, 0x35, 0x2a, 0xe4, 0xf6, 0xfb, 0x29, 0xad, 0x42, 0x63, 0xa2, 0x23, 0xba, 0x6f, 0x03,
-	0x1d, 0x87, 0x91, 0xf7, 0x4a, 0xd8, 0x85, 0xc6, 0x28, 0x28, 0x39, 0xb6, 0x01, 0xc7, 0x61, 0xe5,
-	0x1c, 0xa3, 0x90, 0xa6, 0xd1, 0x9b, 0xcf, 0x21, 0x24, 0x59, 0xc8, 0x7d, 0x97, 0xd0, 0x48, 0x51,
-	0x4c, 0xe9, 0xea, 0xe3, 0x7e, 0xd8, 0xd3, 0x45, 0xd2, 0x73, 0x8a, 0xc4, 0x14, 0x76, 0xb2, 0x8d,
-	0x57, 0x89, 0xb2, 0x9b, 0xaf, 0x45, 0x80, 0xdd, 0x40, 0xcb, 0xae, 0xbe, 0x48, 0xe3, 0x2c, 0xe8,
-	0x77, 0x8f, 0xfb, 0xe7, 0xd4, 0xf6, 0xd8, 0x06, 0x71, 0x55, 0xd2, 0xff, 0xe5, 0x83, 0xff, 0x31,
-	0x93, 0xec, 0x2d, 0x84, 0x95, 0xfd, 0xc6, 0x2b, 0x76, 0xf6, 0xb4, 0xa3, 0x73, 0x4a, 0x91, 0x9d,
-	0x41, 0x79, 0x8d, 0xbd, 0x87, 0xf6, 0x10, 0xed, 0xbe, 0xa5, 0x0f, 0x11, 0x75, 0xfe, 0x9a, 0xc5,
-	0x6b, 0xec, 0x03, 0x5c, 0x3c, 0x6e, 0x1d, 0xac, 0x49, 0xf3, 0x8b, 0xa7, 0xb5, 0x2e, 0x7a, 0x70,
-	0xc2, 0x2b, 0x80, 0x21, 0xda, 0x81, 0xc8, 0x84, 0xfb, 0xd9, 0x05, 0x54, 0xe1, 0xd8, 0x80, 0x5e,
-	0x64, 0x38, 0x5e, 0x63, 0x1c, 0x82, 0x21, 0xda, 0x6f, 0x56, 0xd8, 0x7f, 0xd7, 0xdc, 0x50, 0x0d,
-	0x49, 0xce, 0x9e, 0x51, 0x66, 0xe3, 0xca, 0x4e, 0x7b, 0x07, 0xdd, 0xd7, 0xe0, 0x35, 0x76, 0x0b,
-	0x67, 0x9b, 0xe2, 0xc1, 0x7a, 0x84, 0xf2, 0x3e, 0xb5, 0xff, 0x6f, 0x7a, 0x03, 0x01, 0x2d, 0x3f,
-	0x43, 0xcd, 0xda, 0xbb, 0x5b, 0x5c, 0xf6, 0x80, 0xae, 0x93, 0x16, 0xfd, 0x15, 0xdd, 0xfe, 0x09,
-	0x00, 0x00, 0xff, 0xff, 0x7f, 0xc1, 0xab, 0x86, 0x97, 0x04, 0x00, 0x00,
+func init() { proto.RegisterFile("cli.proto", fileDescriptor_cli_8b82976ac9544e07) }
+
+var fileDescriptor_cli_8b82976ac9544e07 = []byte{
+	// 1155 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0xdb, 0x6e, 0x1c, 0x45,
+	0x13, 0xf6, 0xec, 0xf8, 0xb0, 0x5b, 0xde, 0x38, 0xf9, 0x3b, 0xd1, 0xcf, 0xca, 0x22, 0x21, 0xb4,
+	0x38, 0x58, 0x44, 0x58, 0x51, 0x02, 0x17, 0x48, 0x5c, 0x80, 0x09, 0xb1, 0x51, 0xc0, 0x98, 0xb6,
+	0x81, 0x4b, 0xd4, 0x9e, 0x29, 0x7b, 0x47, 0x1e, 0x77, 0xaf, 0xba, 0x7b, 0x6d, 0xef, 0x23, 0xf0,
+	0x66, 0xf0, 0x3a, 0x3c, 0x01, 0xaa, 0xea, 0x9e, 0x83, 0x93, 0x0d, 0x70, 0xd7, 0x5f, 0x57, 0x75,
+	0x1d, 0xbe, 0x3a, 0xcc, 0xc0, 0xa8, 0xa8, 0xab, 0xdd, 0x99, 0xb3, 0xc1, 0x8a, 0xdc, 0xcd, 0x0a,
+	0xf9, 0x33, 0x8c, 0x4e, 0x9c, 0x36, 0xfe, 0x3b, 0x73, 0x66, 0xc5, 0xff, 0x61, 0xdd, 0x63, 0x71,
+	0x81, 0x8b, 0x49, 0xf6, 0x38, 0xdb, 0x19, 0xa9, 0x84, 0xc4, 0x03, 0x58, 0x33, 0xd6, 0x14, 0x38,
+	0x19, 0x3c, 0xce, 0x76, 0x72, 0x15, 0x81, 0xd8, 0x86, 0x61, 0x61, 0x4d, 0x70, 0xba, 0x08, 0x93,
+	0x9c, 0xf5, 0x5b, 0x2c, 0x1f, 0xc2, 0x26, 0x9b, 0xd5, 0x45, 0xa8, 0xac, 0x11, 0x5b, 0x30, 0x08,
+	0x37, 0x6c, 0x74, 0xac, 0x06, 0xe1, 0x46, 0x7e, 0x06, 0x70, 0x34, 0x3f, 0xad, 0x2b, 0x3f, 0x55,
+	0x18, 0x84, 0x80, 0xd5, 0xc2, 0x96, 0xc8, 0xf2, 0x35, 0xc5, 0x67, 0xba, 0x9b, 0x6a, 0x3f, 0x65,
+	0x8f, 0x63, 0xc5, 0x67, 0xf9, 0x08, 0x86, 0x0a, 0xfd, 0xcc, 0x1a, 0x8f, 0xcb, 0xde, 0xc8, 0x17,
+	0xb0, 0xd5, 0x73, 0xfa, 0x0a, 0x17, 0xe2, 0x5d, 0x18, 0xcd, 0xa2, 0x1f, 0x74, 0xc9, 0x7d, 0x77,
+	0xb1, 0x3c, 0x2d, 0xf9, 0x21, 0xdc, 0xed, 0x59, 0x39, 0xd0, 0x7e, 0xda, 0x06, 0x93, 0xf5, 0x82,
+	0xb9, 0x0f, 0x39, 0x79, 0x18, 0x43, 0xe6, 0x13, 0x5b, 0x99, 0x97, 0xef, 0xc0, 0xda, 0x2f, 0xba,
+	0x9e, 0x23, 0x25, 0xec, 0xaf, 0xd8, 0xee, 0x48, 0x0d, 0xfc, 0x95, 0x7c, 0x0c, 0xc3, 0xbd, 0xda,
+	0x16, 0x17, 0xaf, 0x22, 0x9b, 0xb5, 0x5e, 0xa4, 0x80, 0x72, 0x15, 0x81, 0xfc, 0x2b, 0x83, 0xd5,
+	0x03, 0xd4, 0xa5, 0x98, 0xc0, 0xc6, 0x15, 0x3a, 0x5f, 0x59, 0x93, 0x14, 0x1a, 0x28, 0x1e, 0x01,
+	0xcc, 0xb4, 0x43, 0x13, 0x0e, 0x3a, 0x66, 0x7a, 0x37, 0x54, 0x90, 0xe0, 0x10, 0x59, 0x9a, 0xb3,
+	0xb4, 0xc5, 0xc4, 0xc4, 0x29, 0x05, 0xc0, 0xc2, 0xd5, 0xc8, 0x44, 0x7b, 0x41, 0x09, 0x56, 0xe6,
+	0xcc, 0x4e, 0xd6, 0x62, 0x82, 0x55, 0x6a, 0x06, 0x33, 0xbf, 0x3c, 0x45, 0x37, 0x59, 0xe7, 0x30,
+	0x12, 0xa2, 0xf8, 0xae, 0xab, 0x60, 0xd0, 0xfb, 0xc9, 0x06, 0xe7, 0xd7, 0x40, 0xf2, 0xe1, 0xab,
+	0x73, 0xa3, 0xc3, 0xdc, 0xe1, 0x64, 0x18, 0x7d, 0xb4, 0x17, 0xe4, 0x23, 0x54, 0x97, 0x38, 0x19,
+	0xb1, 0x35, 0x3e, 0xcb, 0x4b, 0x18, 0x31, 0x2d, 0xdc, 0x7d, 0x0f, 0x61, 0x75, 0x8a, 0xba, 0xe4,
+	0xac, 0x37, 0x9f, 0x8d, 0x76, 0xdd, 0xac, 0xd8, 0x25, 0x46, 0x14, 0x5f, 0x13, 0x6d, 0x27, 0x37,
+	0x85, 0x09, 0x4d, 0xb5, 0x18, 0x88, 0x27, 0xb0, 0x1e, 0x6e, 0xbe, 0xaf, 0x3c, 0xb5, 0x60, 0xbe,
+	0xb3, 0xf9, 0xec, 0x3e, 0x3f, 0xbb, 0xdd, 0x06, 0x2a, 0xa9, 0xc8, 0xf7, 0x61, 0xe3, 0xf0, 0xe5,
+	0x09, 0x1d, 0x29, 0xbb, 0x60, 0x2f, 0xd0, 0x50, 0xf1, 0x72, 0x6a, 0xf5, 0x88, 0x64, 0xc1, 0x2a,
+	0x1c, 0xcf, 0x16, 0x0c, 0xaa, 0x32, 0xd5, 0x76, 0x50, 0x71, 0x00, 0xf6, 0xda, 0xa0, 0x4b, 0x65,
+	0x8d, 0x80, 0x0c, 0x55, 0xde, 0xcf, 0xd1, 0xa5, 0x19, 0x48, 0x88, 0x8a, 0x71, 0x89, 0x41, 0x97,
+	0x3a, 0xe8, 0xc4, 0x77, 0x8b, 0xe5, 0x9f, 0x19, 0x6c, 0x9e, 0xdc, 0x1c, 0x59, 0x5b, 0x1f, 0x07,
+	0x1d, 0x3c, 0x59, 0x0e, 0x36, 0xe8, 0xba, 0xe9, 0x08, 0x06, 0x44, 0xf4, 0x0c, 0x4d, 0x59, 0x99,
+	0xf3, 0x94, 0x72, 0x03, 0xc9, 0xe7, 0xd9, 0x9c, 0x59, 0xce, 0x63, 0x69, 0x22, 0xa2, 0x17, 0x1e,
+	0x4d, 0x89, 0xce, 0xb3, 0xcb, 0x5c, 0x35, 0x90, 0x24, 0x78, 0x55, 0x15, 0x01, 0x4b, 0xae, 0x71,
+	0xae, 0x1a, 0x48, 0x71, 0x3a, 0x9c, 0xd5, 0xba, 0xc0, 0x32, 0x15, 0xba, 0xc5, 0x42, 0xc2, 0x38,
+	0x1a, 0xf8, 0x69, 0x8e, 0x73, 0x2c, 0xb9, 0xde, 0xb9, 0xba, 0x75, 0x27, 0x2f, 0xe1, 0x8e, 0xc2,
+	0x6b, 0xed, 0x4a, 0x85, 0x05, 0x56, 0xb3, 0xd0, 0xeb, 0x9b, 0xec, 0x6d, 0x7d, 0x33, 0xb8, 0xdd,
+	0x37, 0x02, 0x56, 0xcf, 0x10, 0x3d, 0x27, 0x93, 0x29, 0x3e, 0x93, 0x15, 0xc7, 0x66, 0x39, 0x93,
+	0x4c, 0x25, 0x24, 0x2b, 0xd8, 0x88, 0xee, 0x7c, 0xdf, 0x60, 0x76, 0xdb, 0x60, 0xcb, 0xe7, 0x80,
+	0xdf, 0x46, 0x20, 0x76, 0x29, 0x53, 0x8e, 0xd1, 0xa7, 0x66, 0x11, 0xdc, 0x2c, 0xb7, 0xc2, 0x57,
+	0xad, 0x8e, 0xfc, 0x12, 0xe0, 0x18, 0xdd, 0x55, 0x75, 0x5c, 0xd8, 0xc8, 0xad, 0x2e, 0x0a, 0x3b,
+	0x37, 0xa1, 0xf1, 0x96, 0x20, 0x79, 0xf3, 0xa4, 0xd2, 0x78, 0x63, 0x20, 0x0f, 0x61, 0xb3, 0x7b,
+	0xcd, 0xf9, 0x4c, 0xb1, 0x3a, 0x9f, 0x86, 0x86, 0x95, 0x88, 0xc4, 0xc7, 0xb0, 0xce, 0xfa, 0x44,
+	0x0a, 0x85, 0x74, 0x97, 0x43, 0xea, 0x5e, 0xaa, 0x24, 0x96, 0xbf, 0x0f, 0x60, 0x7c, 0xbc, 0x30,
+	0xc5, 0x91, 0xb3, 0xe7, 0x8e, 0x92, 0xa4, 0x62, 0x2f, 0x4c, 0x41, 0xed, 0x41, 0x26, 0x87, 0xaa,
+	0x81, 0x1c, 0x50, 0xd0, 0xae, 0x9d, 0x14, 0x06, 0xa4, 0x5f, 0xcc, 0x1d, 0x2d, 0x8b, 0xd4, 0x35,
+	0x0d, 0x24, 0x09, 0x4d, 0x58, 0xaf, 0x6d, 0x12, 0xe4, 0x29, 0xd1, 0xee, 0x1c, 0x43, 0xea, 0x9a,
+	0x84, 0xc8, 0xc3, 0x0c, 0x49, 0x3f, 0x76, 0x4c, 0x04, 0x54, 0x47, 0xa7, 0x03, 0x72, 0x9b, 0x64,
+	0x8a, 0xcf, 0xd4, 0x5e, 0xde, 0xe8, 0x99, 0x9f, 0xda, 0xc0, 0x2b, 0x21, 0x57, 0x2d, 0x26, 0xeb,
+	0xc5, 0x74, 0x6e, 0x2e, 0x7c, 0xda, 0x09, 0x09, 0x89, 0xf7, 0x60, 0x33, 0x9e, 0x7e, 0x2b, 0xad,
+	0xc1, 0x09, 0xb0, 0x10, 0xe2, 0xd5, 0x0b, 0x6b, 0x50, 0x7e, 0x0e, 0xa3, 0x23, 0x44, 0xf7, 0xad,
+	0x09, 0x6e, 0x41, 0x5e, 0x6b, 0x9a, 0xff, 0x58, 0x15, 0x3e, 0x53, 0x7c, 0x48, 0xc2, 0x66, 0x54,
+	0x19, 0xc8, 0x5f, 0xe3, 0x33, 0x9a, 0x7f, 0x4e, 0xcd, 0x07, 0x1d, 0xaa, 0xa2, 0x59, 0x00, 0x11,
+	0x11, 0x19, 0xc1, 0xcd, 0x3d, 0x4d, 0xca, 0x80, 0x05, 0x0d, 0x24, 0x89, 0xae, 0x6b, 0x7b, 0x8d,
+	0x25, 0xb7, 0xcf, 0x48, 0x35, 0xf0, 0xd9, 0x1f, 0xeb, 0x90, 0x7f, 0x53, 0x57, 0xe2, 0x29, 0x8c,
+	0xd2, 0x67, 0xed, 0xe4, 0x46, 0xdc, 0x7b, 0x7d, 0x13, 0x6d, 0xc7, 0xda, 0x76, 0x1f, 0x3e, 0xb9,
+	0x22, 0xbe, 0x80, 0xad, 0x7d, 0x0c, 0x3d, 0x25, 0xb1, 0x6c, 0x81, 0x6d, 0xbf, 0x61, 0x4b, 0xae,
+	0x88, 0xaf, 0xe0, 0xc1, 0xed, 0xa7, 0x7b, 0x0b, 0xde, 0xe5, 0x0f, 0x5e, 0xd7, 0xa5, 0xdb, 0xa5,
+	0x16, 0x3e, 0x00, 0xd8, 0xc7, 0xb0, 0xa7, 0x6b, 0x4d, 0x9f, 0xf3, 0x21, 0x6b, 0x90, 0x37, 0xe0,
+	0x13, 0x7f, 0xc8, 0xe4, 0x8a, 0x90, 0x30, 0xdc, 0xc7, 0x40, 0x8b, 0xea, 0xed, 0x3a, 0x4f, 0x58,
+	0x87, 0x57, 0xb9, 0xb8, 0xc3, 0x92, 0xe6, 0x6b, 0xb7, 0xbd, 0xd5, 0x41, 0xda, 0xaa, 0x72, 0x45,
+	0x3c, 0x87, 0x7b, 0x8d, 0xf2, 0xde, 0xe2, 0x20, 0x8e, 0xc1, 0xbf, 0x3e, 0xfa, 0x14, 0x86, 0x1c,
+	0xfc, 0x19, 0x3a, 0xb1, 0xd5, 0xe5, 0x42, 0xd2, 0x65, 0xbc, 0x7e, 0xc2, 0xbc, 0x1e, 0xbe, 0x3c,
+	0xf1, 0x7b, 0x8b, 0x1f, 0x79, 0x4f, 0x77, 0xa1, 0x8f, 0xf9, 0x94, 0x3e, 0x04, 0x7d, 0xdd, 0x1f,
+	0xd2, 0x7e, 0x5e, 0xa6, 0x9b, 0xc2, 0x88, 0x64, 0x1c, 0xda, 0x7f, 0x22, 0x6c, 0x37, 0xd6, 0xb4,
+	0xb7, 0xdf, 0x3b, 0xcd, 0x54, 0x86, 0x4e, 0x26, 0x57, 0xc4, 0x47, 0x5c, 0x86, 0x66, 0xab, 0xbd,
+	0xee, 0x3b, 0xdd, 0xcb, 0x15, 0xb1, 0x13, 0x0b, 0x41, 0xab, 0xe1, 0x0d, 0x8b, 0xbd, 0x55, 0x23,
+	0x57, 0xc4, 0x53, 0xb8, 0x4b, 0x9a, 0xfd, 0x6d, 0xd1, 0x3d, 0xf8, 0x5f, 0x7c, 0xd0, 0x13, 0x32,
+	0x07, 0xe3, 0x7d, 0x0c, 0xdd, 0x74, 0x74, 0xea, 0x91, 0xec, 0x56, 0xc2, 0xc5, 0xde, 0xf8, 0xba,
+	0x2c, 0x8f, 0xb0, 0xad, 0x44, 0x3b, 0x8b, 0x4b, 0x94, 0x77, 0x01, 0x14, 0x5e, 0xda, 0x2b, 0xfc,
+	0x6f, 0xfa, 0xa7, 0xeb, 0xfc, 0x6f, 0xfa, 0xfc, 0xef, 0x01, 0x00, 0x73, 0xb4, 0xe5, 0x39, 0xa8,
+	0x0a, 0x00, 0x00,
 }
@@ -3,19 +3,23 @@ package rpc
 import (
 	"context"
 	"fmt"
+	"net"
 	"reflect"
+	"strconv"
 
 	"github.com/iost-official/Go-IOS-Protocol/account"
 	"github.com/iost-official/Go-IOS-Protocol/common"
 	"github.com/iost-official/Go-IOS-Protocol/consensus"
 	"github.com/iost-official/Go-IOS-Protocol/core/block"
-	"github.com/iost-official/Go-IOS-Protocol/core/message"
 	"github.com/iost-official/Go-IOS-Protocol/core/state"
 	"github.com/iost-official/Go-IOS-Protocol/core/tx"
 	"github.com/iost-official/Go-IOS-Protocol/core/txpool"
 	"github.com/iost-official/Go-IOS-Protocol/network"
+	"github.com/iost-official/Go-IOS-Protocol/verifier"
 	"github.com/iost-official/Go-IOS-Protocol/vm"
+	"github.com/iost-official/Go-IOS-Protocol/vm/host"
 	"github.com/iost-official/Go-IOS-Protocol/vm/lua"
+	"google.golang.org/grpc/peer"
 )
 
 //go:generate mockgen -destination mock_rpc/mock_rpc.go -package rpc_mock github.com/iost-official/Go-IOS-Protocol/rpc CliServer
@@ -73,23 +77,20 @@ func (s *RpcServer) Transfer(ctx context.Context, txinfo *TransInfo) (*PublishRe
 	}
 
 	// add servi
-	tx.RecordTx(stx, tx.Data.Self())
+	stx, _ = tx.RecordTx(stx, tx.Data.Self())
 
-	//broadcast the tx
-	router := network.Route
-	if router == nil {
-		panic(fmt.Errorf("network.Router shouldn't be nil"))
-	}
-	broadTx := message.Message{
-		Body:    stx.Encode(),
-		ReqType: int32(network.ReqPublishTx),
-	}
-	router.Broadcast(broadTx)
 	Cons := consensus.Cons
 	if Cons == nil {
 		panic(fmt.Errorf("Consensus is nil"))
 	}
-	txpool.TxPoolS.AddTransaction(&broadTx)
+	err = txpool.TxPoolS.AddTx(&stx)
+	if err != nil {
+		ret.Code = txpool.RejectCode(err)
+		return &ret, nil
+	}
+
+	//broadcast the tx, or announce its hash in large networks
+	txpool.TxPoolS.Relay(&stx)
 	ret.Code = 0
 	ret.Hash = stx.Hash()
 	return &ret, nil
@@ -112,23 +113,21 @@ func (s *RpcServer) PublishTx(ctx context.Context, _tx *Transaction) (*PublishRe
 	}
 
 	// add servi
-	tx.RecordTx(tx1, tx.Data.Self())
+	tx1, _ = tx.RecordTx(tx1, tx.Data.Self())
 
-	//broadcast the tx
-	router := network.Route
-	if router == nil {
-		panic(fmt.Errorf("network.Router shouldn't be nil"))
-	}
-	broadTx := message.Message{
-		Body:    tx1.Encode(),
-		ReqType: int32(network.ReqPublishTx),
-	}
-	router.Broadcast(broadTx)
 	Cons := consensus.Cons
 	if Cons == nil {
 		panic(fmt.Errorf("Consensus is nil"))
 	}
-	txpool.TxPoolS.AddTransaction(&broadTx)
+	// rejections are reported by code, so clients can tell why
+	err = txpool.TxPoolS.AddTx(&tx1)
+	if err != nil {
+		ret.Code = txpool.RejectCode(err)
+		return &ret, nil
+	}
+
+	//broadcast the tx, or announce its hash in large networks
+	txpool.TxPoolS.Relay(&tx1)
 	ret.Code = 0
 	ret.Hash = tx1.Hash()
 	return &ret, nil
@@ -190,6 +189,199 @@ func (s *RpcServer) GetBalance(ctx context.Context, iak *Key) (*Value, error) {
 	return &Value{Sv: balance}, nil
 }
 
+// GetNonce returns the last nonce used by account, the next tx of it must use this plus one
+func (s *RpcServer) GetNonce(ctx context.Context, iak *Key) (*Value, error) {
+	if iak == nil {
+		return nil, fmt.Errorf("argument cannot be nil pointer")
+	}
+	if state.StdPool == nil {
+		panic(fmt.Errorf("state.StdPool shouldn't be nil"))
+	}
+	nonce := verifier.NonceOf(vm.IOSTAccount(iak.S), state.StdPool)
+	return &Value{Sv: strconv.FormatInt(nonce, 10)}, nil
+}
+
+// GetTxPoolStats returns statistics of the tx pool, if key is not empty the number of txs queued
+// by that account is filled as well
+func (s *RpcServer) GetTxPoolStats(ctx context.Context, iak *Key) (*TxPoolStats, error) {
+	if iak == nil {
+		return nil, fmt.Errorf("argument cannot be nil pointer")
+	}
+	if txpool.TxPoolS == nil {
+		return nil, fmt.Errorf("tx pool not started")
+	}
+	st := txpool.TxPoolS.Stats()
+	ret := &TxPoolStats{
+		Total:    int64(st.Total),
+		Pending:  int64(st.Pending),
+		Future:   int64(st.Future),
+		Senders:  int64(st.Senders),
+		Evicted:  st.Evicted,
+		Replaced: st.Replaced,
+	}
+	if iak.S != "" {
+		ret.SenderQueued = int64(txpool.TxPoolS.SenderTxNum(vm.IOSTAccount(iak.S)))
+	}
+	return ret, nil
+}
+
+// RewardReceiptScan is the number of recent blocks GetRewards looks receipts up in, state keeps
+// no older receipts
+var RewardReceiptScan = int(verifier.RewardReceiptsKept)
+
+// GetRewards returns the total rewards of a witness, the witness of the top block if the key is
+// empty, with the receipts of the recent blocks it produced
+func (s *RpcServer) GetRewards(ctx context.Context, wk *Key) (*Rewards, error) {
+	if wk == nil {
+		return nil, fmt.Errorf("argument cannot be nil pointer")
+	}
+	stPool := state.StdPool
+	if stPool == nil {
+		panic(fmt.Errorf("state.StdPool shouldn't be nil"))
+	}
+	bc := block.BChain
+	if bc == nil {
+		panic(fmt.Errorf("block.BChain cannot be nil"))
+	}
+
+	witness := wk.S
+	if witness == "" && bc.Top() != nil {
+		witness = bc.Top().Head.Witness
+	}
+	ret := &Rewards{
+		Witness:  witness,
+		Total:    verifier.RewardOf(stPool, vm.IOSTAccount(witness)),
+		Receipts: make([]*RewardReceipt, 0),
+	}
+	for i := 0; i < RewardReceiptScan && uint64(i) < bc.Length(); i++ {
+		receipt, err := verifier.GetRewardReceipt(stPool, int64(bc.Length())-1-int64(i))
+		if err != nil || receipt.Witness != witness {
+			continue
+		}
+		ret.Receipts = append(ret.Receipts, &RewardReceipt{
+			Number:  receipt.Number,
+			Witness: receipt.Witness,
+			Fees:    receipt.Fees,
+			Reward:  receipt.Reward,
+		})
+	}
+	return ret, nil
+}
+
+// GetServi returns the servi score of the recorder of the key, or the best recorders if the key is empty
+func (s *RpcServer) GetServi(ctx context.Context, sk *Key) (*ServiScores, error) {
+	if sk == nil {
+		return nil, fmt.Errorf("argument cannot be nil pointer")
+	}
+	stPool := state.StdPool
+	if stPool == nil {
+		panic(fmt.Errorf("state.StdPool shouldn't be nil"))
+	}
+	bc := block.BChain
+	if bc == nil {
+		panic(fmt.Errorf("block.BChain cannot be nil"))
+	}
+
+	height := int64(bc.Length()) - 1
+	ret := &ServiScores{Height: height, Scores: make([]*ServiScore, 0)}
+	if sk.S != "" {
+		ret.Scores = append(ret.Scores, &ServiScore{
+			Account: sk.S,
+			Score:   verifier.ServiOf(stPool, vm.IOSTAccount(sk.S), height),
+		})
+		return ret, nil
+	}
+	for _, score := range verifier.ServiTopOf(stPool, height) {
+		ret.Scores = append(ret.Scores, &ServiScore{Account: score.Account, Score: score.Score})
+	}
+	return ret, nil
+}
+
+// GetSyncProgress returns the progress of the block synchronization
+func (s *RpcServer) GetSyncProgress(ctx context.Context, k *Key) (*SyncProgress, error) {
+	if consensus.Cons == nil {
+		return nil, fmt.Errorf("consensus not started")
+	}
+	p := consensus.Cons.SyncProgress()
+	return &SyncProgress{
+		Syncing: p.Syncing,
+		Start:   int64(p.Start),
+		Current: int64(p.Current),
+		Headers: int64(p.Headers),
+		Target:  int64(p.Target),
+		Peers:   int64(p.Peers),
+		Rate:    p.Rate,
+
+		Snapshot:   int64(p.Snapshot),
+		Chunks:     int64(p.Chunks),
+		ChunksDone: int64(p.ChunksDone),
+	}, nil
+}
+
+// adminOnly refuses the admin calls of clients which are not on the local host.
+func adminOnly(ctx context.Context) error {
+	if p, ok := peer.FromContext(ctx); ok {
+		if addr, ok := p.Addr.(*net.TCPAddr); ok && addr.IP.IsLoopback() {
+			return nil
+		}
+	}
+	return fmt.Errorf("admin calls are only accepted from the local host")
+}
+
+func peerLists() (*PeerLists, error) {
+	if network.Route == nil {
+		return nil, fmt.Errorf("network not started")
+	}
+	l := network.Route.PeerLists()
+	return &PeerLists{
+		Static:  l[network.StaticPeers],
+		Trusted: l[network.TrustedPeers],
+		Allowed: l[network.AllowedPeers],
+	}, nil
+}
+
+// GetPeerLists returns the static, trusted and allowed peers
+func (s *RpcServer) GetPeerLists(ctx context.Context, k *Key) (*PeerLists, error) {
+	if err := adminOnly(ctx); err != nil {
+		return nil, err
+	}
+	return peerLists()
+}
+
+// AddPeer puts a node, or a CIDR for the allowed peers, in a peer list
+func (s *RpcServer) AddPeer(ctx context.Context, e *PeerEntry) (*PeerLists, error) {
+	if err := adminOnly(ctx); err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, fmt.Errorf("argument cannot be nil pointer")
+	}
+	if network.Route == nil {
+		return nil, fmt.Errorf("network not started")
+	}
+	if err := network.Route.AddPeer(e.List, e.Entry); err != nil {
+		return nil, fmt.Errorf("AddPeer Error: [%v]", err)
+	}
+	return peerLists()
+}
+
+// RemovePeer takes an entry out of a peer list, peers not allowed anymore are disconnected
+func (s *RpcServer) RemovePeer(ctx context.Context, e *PeerEntry) (*PeerLists, error) {
+	if err := adminOnly(ctx); err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, fmt.Errorf("argument cannot be nil pointer")
+	}
+	if network.Route == nil {
+		return nil, fmt.Errorf("network not started")
+	}
+	if err := network.Route.RemovePeer(e.List, e.Entry); err != nil {
+		return nil, fmt.Errorf("RemovePeer Error: [%v]", err)
+	}
+	return peerLists()
+}
+
 func (s *RpcServer) GetState(ctx context.Context, stkey *Key) (*Value, error) {
 	fmt.Println("GetState begin")
 	if stkey == nil {
@@ -300,3 +492,35 @@ func (s *RpcServer) GetBlockByHeight(ctx context.Context, bk *BlockKey) (*BlockI
 		TxList: txList,
 	}, nil
 }
+
+func (s *RpcServer) GetNFTsByOwner(ctx context.Context, iak *Key) (*NFTList, error) {
+	if iak == nil {
+		return nil, fmt.Errorf("argument cannot be nil pointer")
+	}
+	stPool := state.StdPool
+	if stPool == nil {
+		panic(fmt.Errorf("state.StdPool shouldn't be nil"))
+	}
+	return &NFTList{Tokens: host.NFTsOf(stPool, iak.S)}, nil
+}
+
+func (s *RpcServer) GetNFTMetadata(ctx context.Context, idk *Key) (*NFTInfo, error) {
+	if idk == nil {
+		return nil, fmt.Errorf("argument cannot be nil pointer")
+	}
+	stPool := state.StdPool
+	if stPool == nil {
+		panic(fmt.Errorf("state.StdPool shouldn't be nil"))
+	}
+	owner := host.NFTOwner(stPool, idk.S)
+	if owner == "" {
+		return nil, fmt.Errorf("token %v not found", idk.S)
+	}
+	metadata, _ := host.NFTMetadata(stPool, idk.S)
+	return &NFTInfo{
+		Id:       idk.S,
+		Owner:    owner,
+		Issuer:   host.NFTIssuer(stPool, idk.S),
+		Metadata: metadata,
+	}, nil
+}
@@ -35,6 +35,19 @@ func (m *MockCliServer) EXPECT() *MockCliServerMockRecorder {
 	return m.recorder
 }
 
+// AddPeer mocks base method
+func (m *MockCliServer) AddPeer(arg0 context.Context, arg1 *rpc.PeerEntry) (*rpc.PeerLists, error) {
+	ret := m.ctrl.Call(m, "AddPeer", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.PeerLists)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPeer indicates an expected call of AddPeer
+func (mr *MockCliServerMockRecorder) AddPeer(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPeer", reflect.TypeOf((*MockCliServer)(nil).AddPeer), arg0, arg1)
+}
+
 // GetBalance mocks base method
 func (m *MockCliServer) GetBalance(arg0 context.Context, arg1 *rpc.Key) (*rpc.Value, error) {
 	ret := m.ctrl.Call(m, "GetBalance", arg0, arg1)
@@ -74,6 +87,84 @@ func (mr *MockCliServerMockRecorder) GetBlockByHeight(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByHeight", reflect.TypeOf((*MockCliServer)(nil).GetBlockByHeight), arg0, arg1)
 }
 
+// GetNFTMetadata mocks base method
+func (m *MockCliServer) GetNFTMetadata(arg0 context.Context, arg1 *rpc.Key) (*rpc.NFTInfo, error) {
+	ret := m.ctrl.Call(m, "GetNFTMetadata", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.NFTInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNFTMetadata indicates an expected call of GetNFTMetadata
+func (mr *MockCliServerMockRecorder) GetNFTMetadata(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNFTMetadata", reflect.TypeOf((*MockCliServer)(nil).GetNFTMetadata), arg0, arg1)
+}
+
+// GetNFTsByOwner mocks base method
+func (m *MockCliServer) GetNFTsByOwner(arg0 context.Context, arg1 *rpc.Key) (*rpc.NFTList, error) {
+	ret := m.ctrl.Call(m, "GetNFTsByOwner", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.NFTList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNFTsByOwner indicates an expected call of GetNFTsByOwner
+func (mr *MockCliServerMockRecorder) GetNFTsByOwner(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNFTsByOwner", reflect.TypeOf((*MockCliServer)(nil).GetNFTsByOwner), arg0, arg1)
+}
+
+// GetNonce mocks base method
+func (m *MockCliServer) GetNonce(arg0 context.Context, arg1 *rpc.Key) (*rpc.Value, error) {
+	ret := m.ctrl.Call(m, "GetNonce", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.Value)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNonce indicates an expected call of GetNonce
+func (mr *MockCliServerMockRecorder) GetNonce(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNonce", reflect.TypeOf((*MockCliServer)(nil).GetNonce), arg0, arg1)
+}
+
+// GetPeerLists mocks base method
+func (m *MockCliServer) GetPeerLists(arg0 context.Context, arg1 *rpc.Key) (*rpc.PeerLists, error) {
+	ret := m.ctrl.Call(m, "GetPeerLists", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.PeerLists)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeerLists indicates an expected call of GetPeerLists
+func (mr *MockCliServerMockRecorder) GetPeerLists(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeerLists", reflect.TypeOf((*MockCliServer)(nil).GetPeerLists), arg0, arg1)
+}
+
+// GetRewards mocks base method
+func (m *MockCliServer) GetRewards(arg0 context.Context, arg1 *rpc.Key) (*rpc.Rewards, error) {
+	ret := m.ctrl.Call(m, "GetRewards", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.Rewards)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewards indicates an expected call of GetRewards
+func (mr *MockCliServerMockRecorder) GetRewards(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewards", reflect.TypeOf((*MockCliServer)(nil).GetRewards), arg0, arg1)
+}
+
+// GetServi mocks base method
+func (m *MockCliServer) GetServi(arg0 context.Context, arg1 *rpc.Key) (*rpc.ServiScores, error) {
+	ret := m.ctrl.Call(m, "GetServi", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.ServiScores)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServi indicates an expected call of GetServi
+func (mr *MockCliServerMockRecorder) GetServi(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServi", reflect.TypeOf((*MockCliServer)(nil).GetServi), arg0, arg1)
+}
+
 // GetState mocks base method
 func (m *MockCliServer) GetState(arg0 context.Context, arg1 *rpc.Key) (*rpc.Value, error) {
 	ret := m.ctrl.Call(m, "GetState", arg0, arg1)
@@ -87,6 +178,19 @@ func (mr *MockCliServerMockRecorder) GetState(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetState", reflect.TypeOf((*MockCliServer)(nil).GetState), arg0, arg1)
 }
 
+// GetSyncProgress mocks base method
+func (m *MockCliServer) GetSyncProgress(arg0 context.Context, arg1 *rpc.Key) (*rpc.SyncProgress, error) {
+	ret := m.ctrl.Call(m, "GetSyncProgress", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.SyncProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncProgress indicates an expected call of GetSyncProgress
+func (mr *MockCliServerMockRecorder) GetSyncProgress(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncProgress", reflect.TypeOf((*MockCliServer)(nil).GetSyncProgress), arg0, arg1)
+}
+
 // GetTransaction mocks base method
 func (m *MockCliServer) GetTransaction(arg0 context.Context, arg1 *rpc.TransactionKey) (*rpc.Transaction, error) {
 	ret := m.ctrl.Call(m, "GetTransaction", arg0, arg1)
@@ -113,10 +217,23 @@ func (mr *MockCliServerMockRecorder) GetTransactionByHash(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByHash", reflect.TypeOf((*MockCliServer)(nil).GetTransactionByHash), arg0, arg1)
 }
 
+// GetTxPoolStats mocks base method
+func (m *MockCliServer) GetTxPoolStats(arg0 context.Context, arg1 *rpc.Key) (*rpc.TxPoolStats, error) {
+	ret := m.ctrl.Call(m, "GetTxPoolStats", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.TxPoolStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTxPoolStats indicates an expected call of GetTxPoolStats
+func (mr *MockCliServerMockRecorder) GetTxPoolStats(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxPoolStats", reflect.TypeOf((*MockCliServer)(nil).GetTxPoolStats), arg0, arg1)
+}
+
 // PublishTx mocks base method
-func (m *MockCliServer) PublishTx(arg0 context.Context, arg1 *rpc.Transaction) (*rpc.Response, error) {
+func (m *MockCliServer) PublishTx(arg0 context.Context, arg1 *rpc.Transaction) (*rpc.PublishRet, error) {
 	ret := m.ctrl.Call(m, "PublishTx", arg0, arg1)
-	ret0, _ := ret[0].(*rpc.Response)
+	ret0, _ := ret[0].(*rpc.PublishRet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -125,3 +242,29 @@ func (m *MockCliServer) PublishTx(arg0 context.Context, arg1 *rpc.Transaction) (
 func (mr *MockCliServerMockRecorder) PublishTx(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishTx", reflect.TypeOf((*MockCliServer)(nil).PublishTx), arg0, arg1)
 }
+
+// RemovePeer mocks base method
+func (m *MockCliServer) RemovePeer(arg0 context.Context, arg1 *rpc.PeerEntry) (*rpc.PeerLists, error) {
+	ret := m.ctrl.Call(m, "RemovePeer", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.PeerLists)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemovePeer indicates an expected call of RemovePeer
+func (mr *MockCliServerMockRecorder) RemovePeer(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePeer", reflect.TypeOf((*MockCliServer)(nil).RemovePeer), arg0, arg1)
+}
+
+// Transfer mocks base method
+func (m *MockCliServer) Transfer(arg0 context.Context, arg1 *rpc.TransInfo) (*rpc.PublishRet, error) {
+	ret := m.ctrl.Call(m, "Transfer", arg0, arg1)
+	ret0, _ := ret[0].(*rpc.PublishRet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transfer indicates an expected call of Transfer
+func (mr *MockCliServerMockRecorder) Transfer(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockCliServer)(nil).Transfer), arg0, arg1)
+}
@@ -0,0 +1,63 @@
+package verifier
+
+import (
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+)
+
+// RunDeferred applies to pool the calls due at the block of cv.Context, before its txs, and returns
+// the gas limits of the calls it ran, which count against the gas of the block. The calls of a block
+// are bounded by vm.DeferredLimits, calls left over stay due for the next blocks. Every call
+// runs on behalf of its owner and is charged the gas it used, a call which fails or exceeds its gas
+// limit leaves no change but forfeits its deposit. Fees go to the witness like fees of txs
+func (cv *CacheVerifier) RunDeferred(pool state.Pool) uint64 {
+	if cv.Context == nil {
+		return 0
+	}
+	maxGas, count := vm.DeferredLimits(pool)
+	var gas int64
+	for _, id := range vm.DueDeferred(pool, cv.Context.BlockHeight, cv.Context.Timestamp, count) {
+		call, err := vm.GetDeferred(pool, id)
+		if err != nil {
+			continue
+		}
+		if call.GasLimit > maxGas {
+			// scheduled under larger bounds, it would never run
+			vm.DelDeferred(pool, id)
+			setBalanceOfSender(call.Owner, pool, balanceOfSender(call.Owner, pool)+call.Deposit())
+			continue
+		}
+		if gas+call.GasLimit > maxGas {
+			break
+		}
+		gas += call.GasLimit
+		vm.DelDeferred(pool, id)
+		cv.runDeferred(call, pool)
+	}
+	return uint64(gas)
+}
+
+func (cv *CacheVerifier) runDeferred(call *vm.DeferredCall, pool state.Pool) {
+	fee := call.Deposit()
+	if pool2, gas, err := cv.callDeferred(call, pool.Copy()); err == nil && gas <= uint64(call.GasLimit) {
+		pool2.MergeParent()
+		fee = float64(gas) * call.Price
+	}
+	setBalanceOfSender(call.Owner, pool, balanceOfSender(call.Owner, pool)+call.Deposit()-fee)
+	cv.Fees += fee
+}
+
+func (cv *CacheVerifier) callDeferred(call *vm.DeferredCall, pool state.Pool) (state.Pool, uint64, error) {
+	method, info, err := cv.GetMethod(call.Contract, call.Method)
+	if err != nil {
+		return pool, 0, err
+	}
+	if method.Privilege() == vm.Private && info.Publisher != call.Owner {
+		return pool, 0, ErrForbiddenCall
+	}
+
+	ctx := vm.NewContext(cv.Context)
+	ctx.Publisher = call.Owner
+	_, pool, gas, err := cv.Call(ctx, pool, call.Contract, call.Method, call.Args...)
+	return pool, gas, err
+}
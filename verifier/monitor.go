@@ -31,6 +31,8 @@ type vmMonitor struct {
 	vms              map[string]vmHolder
 	hotVM            *vmHolder
 	needRestartHotVM bool
+	// gasInside is the gas used by calls of each contract since the last contract verified started
+	gasInside map[string]uint64
 }
 
 func newVMMonitor() vmMonitor {
@@ -38,6 +40,7 @@ func newVMMonitor() vmMonitor {
 		vms:              make(map[string]vmHolder),
 		hotVM:            nil,
 		needRestartHotVM: false,
+		gasInside:        make(map[string]uint64),
 	}
 }
 
@@ -175,6 +178,7 @@ func (m *vmMonitor) Call(ctx *vm.Context, pool state.Pool, contractPrefix, metho
 	defer func() { holder.IsRunning = false }()
 	rtn, pool2, err := holder.Call(ctx, pool, methodName, args...)
 	gas := holder.PC()
+	m.gasInside[contractPrefix] += gas
 	return rtn, pool2, gas, err
 }
 
@@ -0,0 +1,117 @@
+package verifier
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+)
+
+// Inflation schedule written into chain parameters at genesis: every block pays BlockReward to
+// its witness, halved every RewardHalving blocks, 0 means the reward never halves
+var (
+	BlockReward         = 5.0
+	RewardHalving int64 = 10512000 // about a year of 3 second slots
+)
+
+// Chain parameter keys of the inflation schedule, and the state maps of rewards
+const (
+	BlockRewardKey   state.Key = "block-reward"
+	RewardHalvingKey state.Key = "reward-halving"
+	// RewardKey maps witnesses to the total rewards they earned
+	RewardKey state.Key = "reward"
+	// RewardReceiptKey maps block numbers to their RewardReceipt
+	RewardReceiptKey state.Key = "reward-receipt"
+)
+
+// RewardReceiptsKept is the number of recent blocks whose receipts are kept in state, older
+// receipts are pruned as blocks are rewarded
+const RewardReceiptsKept int64 = 100
+
+var ErrNoReceipt = errors.New("reward receipt not found")
+
+func (r *RewardReceipt) Encode() []byte {
+	b, err := r.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (r *RewardReceipt) Decode(bin []byte) error {
+	_, err := r.Unmarshal(bin)
+	return err
+}
+
+// BlockRewardAt returns the block reward of height according to the schedule recorded in pool
+func BlockRewardAt(pool state.Pool, height int64) float64 {
+	if height <= 0 {
+		return 0
+	}
+	reward, halving := 0.0, int64(0)
+	if val, err := pool.Get(BlockRewardKey); err == nil {
+		if v, ok := val.(*state.VFloat); ok {
+			reward = v.ToFloat64()
+		}
+	}
+	if val, err := pool.Get(RewardHalvingKey); err == nil {
+		if v, ok := val.(*state.VInt); ok {
+			halving = int64(v.ToInt())
+		}
+	}
+	if halving > 0 {
+		reward = reward / math.Pow(2, float64((height-1)/halving))
+	}
+	return reward
+}
+
+// RewardOf returns the total rewards witness earned
+func RewardOf(pool state.Pool, witness vm.IOSTAccount) float64 {
+	val, err := pool.GetHM(RewardKey, state.Key(witness))
+	if err != nil {
+		return 0
+	}
+	v, ok := val.(*state.VFloat)
+	if !ok {
+		return 0
+	}
+	return v.ToFloat64()
+}
+
+// PayBlockReward credits witness of block height with fees collected in the block plus the block
+// reward, records the receipt of the block and prunes the receipt which is no longer kept
+func PayBlockReward(pool state.Pool, witness vm.IOSTAccount, height int64, fees float64) *RewardReceipt {
+	receipt := &RewardReceipt{
+		Number:  height,
+		Witness: string(witness),
+		Fees:    fees,
+		Reward:  BlockRewardAt(pool, height),
+	}
+	total := receipt.Fees + receipt.Reward
+	setBalanceOfSender(witness, pool, balanceOfSender(witness, pool)+total)
+	pool.PutHM(RewardKey, state.Key(witness), state.MakeVFloat(RewardOf(pool, witness)+total))
+	pool.PutHM(RewardReceiptKey, state.Key(strconv.FormatInt(height, 10)), state.MakeVByte(receipt.Encode()))
+	if height > RewardReceiptsKept {
+		pool.PutHM(RewardReceiptKey, state.Key(strconv.FormatInt(height-RewardReceiptsKept, 10)), state.VDelete)
+	}
+	return receipt
+}
+
+// GetRewardReceipt returns the receipt of block height
+func GetRewardReceipt(pool state.Pool, height int64) (*RewardReceipt, error) {
+	val, err := pool.GetHM(RewardReceiptKey, state.Key(strconv.FormatInt(height, 10)))
+	if err != nil {
+		return nil, err
+	}
+	b, ok := val.(*state.VBytes)
+	if !ok {
+		return nil, ErrNoReceipt
+	}
+	var receipt RewardReceipt
+	if err := receipt.Decode(b.ToBytes()); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
@@ -0,0 +1,239 @@
+package verifier
+
+import (
+	"math"
+	"sort"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+)
+
+// Recorder reward schedule written into chain parameters at genesis. Every publisher a recorder
+// served in a period adds to its servi score once, scores decay by ServiDecay at the end of every period of ServiPeriod
+// blocks, when ServiReward per block of the period is shared by the ServiWinners best recorders
+var (
+	ServiReward       = 1.0
+	ServiPeriod int64 = 1200 // an hour of 3 second slots
+)
+
+const (
+	ServiDecay   = 0.9
+	ServiBase    = 1.0
+	ServiWinners = 10
+)
+
+// Chain parameter keys of the recorder reward schedule, and the state of servi scores
+const (
+	ServiRewardKey state.Key = "servi-reward"
+	ServiPeriodKey state.Key = "servi-period"
+	// ServiKey maps recorders to their ServiScore
+	ServiKey state.Key = "servi"
+	// ServiTopKey holds the ServiTop of the best recorders
+	ServiTopKey state.Key = "servi-top"
+	// ServiServedKey maps recorder and publisher pairs to the last period the recorder was credited for the publisher
+	ServiServedKey state.Key = "servi-served"
+)
+
+// Recording is a tx of a block signed by a recorder other than its publisher
+type Recording struct {
+	Recorder  vm.IOSTAccount
+	Publisher vm.IOSTAccount
+}
+
+func (s *ServiScore) Encode() []byte {
+	b, err := s.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (s *ServiScore) Decode(bin []byte) error {
+	_, err := s.Unmarshal(bin)
+	return err
+}
+
+func (t *ServiTop) Encode() []byte {
+	b, err := t.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (t *ServiTop) Decode(bin []byte) error {
+	_, err := t.Unmarshal(bin)
+	return err
+}
+
+// servi period index of height, heights 1 to period make the first period
+func serviPeriodOf(height, period int64) int64 {
+	if height <= 0 {
+		return 0
+	}
+	return (height - 1) / period
+}
+
+// At returns the score decayed up to height
+func (s *ServiScore) At(height, period int64) float64 {
+	if period <= 0 {
+		return s.Score
+	}
+	n := serviPeriodOf(height, period) - serviPeriodOf(s.Height, period)
+	if n <= 0 {
+		return s.Score
+	}
+	return s.Score * math.Pow(ServiDecay, float64(n))
+}
+
+// ServiSchedule returns the reward per block and the period recorded in pool, a period of 0
+// means recorders are not rewarded
+func ServiSchedule(pool state.Pool) (reward float64, period int64) {
+	if val, err := pool.Get(ServiRewardKey); err == nil {
+		if v, ok := val.(*state.VFloat); ok {
+			reward = v.ToFloat64()
+		}
+	}
+	if val, err := pool.Get(ServiPeriodKey); err == nil {
+		if v, ok := val.(*state.VInt); ok {
+			period = int64(v.ToInt())
+		}
+	}
+	return
+}
+
+// ServiOf returns the score of recorder decayed up to height
+func ServiOf(pool state.Pool, recorder vm.IOSTAccount, height int64) float64 {
+	_, period := ServiSchedule(pool)
+	s := getServi(pool, recorder)
+	return s.At(height, period)
+}
+
+func getServi(pool state.Pool, recorder vm.IOSTAccount) *ServiScore {
+	s := &ServiScore{Account: string(recorder)}
+	val, err := pool.GetHM(ServiKey, state.Key(recorder))
+	if err != nil {
+		return s
+	}
+	b, ok := val.(*state.VBytes)
+	if !ok {
+		return s
+	}
+	if err := s.Decode(b.ToBytes()); err != nil {
+		return &ServiScore{Account: string(recorder)}
+	}
+	return s
+}
+
+// ServiTopOf returns the best recorders with scores decayed up to height, best first
+func ServiTopOf(pool state.Pool, height int64) []ServiScore {
+	_, period := ServiSchedule(pool)
+	top := getServiTop(pool)
+	scores := make([]ServiScore, 0, len(top.Scores))
+	for _, s := range top.Scores {
+		scores = append(scores, ServiScore{Account: s.Account, Score: s.At(height, period), Height: height})
+	}
+	return scores
+}
+
+func getServiTop(pool state.Pool) *ServiTop {
+	top := &ServiTop{}
+	val, err := pool.Get(ServiTopKey)
+	if err != nil {
+		return top
+	}
+	b, ok := val.(*state.VBytes)
+	if !ok {
+		return top
+	}
+	if err := top.Decode(b.ToBytes()); err != nil {
+		return &ServiTop{}
+	}
+	return top
+}
+
+// RecordServi credits recorders of the txs in block height and rewards the best recorders at the
+// end of a period. A recorder is credited once per publisher and period, so that a publisher
+// recording its own txs with another key gains no more than by sending a single tx.
+// The order of recordings does not matter
+func RecordServi(pool state.Pool, recordings []Recording, height int64) {
+	_, period := ServiSchedule(pool)
+	if period <= 0 {
+		return
+	}
+	current := serviPeriodOf(height, period)
+
+	counts := make(map[vm.IOSTAccount]int)
+	accounts := make([]vm.IOSTAccount, 0)
+	for _, r := range recordings {
+		served := state.Key(r.Recorder + "-" + r.Publisher)
+		if val, err := pool.GetHM(ServiServedKey, served); err == nil {
+			if v, ok := val.(*state.VInt); ok && int64(v.ToInt()) == current {
+				continue
+			}
+		}
+		pool.PutHM(ServiServedKey, served, state.MakeVInt(int(current)))
+		if counts[r.Recorder] == 0 {
+			accounts = append(accounts, r.Recorder)
+		}
+		counts[r.Recorder]++
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i] < accounts[j] })
+
+	top := getServiTop(pool)
+	for _, r := range accounts {
+		s := getServi(pool, r)
+		s.Score = s.At(height, period) + ServiBase*float64(counts[r])
+		s.Height = height
+		pool.PutHM(ServiKey, state.Key(r), state.MakeVByte(s.Encode()))
+		top.put(*s)
+	}
+	top.rank(height, period)
+	pool.Put(ServiTopKey, state.MakeVByte(top.Encode()))
+
+	if height%period == 0 {
+		payServiReward(pool, top, height, period)
+	}
+}
+
+func (t *ServiTop) put(s ServiScore) {
+	for i := range t.Scores {
+		if t.Scores[i].Account == s.Account {
+			t.Scores[i] = s
+			return
+		}
+	}
+	t.Scores = append(t.Scores, s)
+}
+
+// rank sorts the recorders by score at height and keeps the ServiWinners best
+func (t *ServiTop) rank(height, period int64) {
+	sort.Slice(t.Scores, func(i, j int) bool {
+		si, sj := t.Scores[i].At(height, period), t.Scores[j].At(height, period)
+		if si == sj {
+			return t.Scores[i].Account < t.Scores[j].Account
+		}
+		return si > sj
+	})
+	if len(t.Scores) > ServiWinners {
+		t.Scores = t.Scores[:ServiWinners]
+	}
+}
+
+// payServiReward shares the reward of the period ending at height by the scores of the best recorders
+func payServiReward(pool state.Pool, top *ServiTop, height, period int64) {
+	reward, _ := ServiSchedule(pool)
+	total := 0.0
+	for _, s := range top.Scores {
+		total += s.At(height, period)
+	}
+	if reward <= 0 || total <= 0 {
+		return
+	}
+	for _, s := range top.Scores {
+		share := reward * float64(period) * s.At(height, period) / total
+		recorder := vm.IOSTAccount(s.Account)
+		setBalanceOfSender(recorder, pool, balanceOfSender(recorder, pool)+share)
+		pool.PutHM(RewardKey, state.Key(recorder), state.MakeVFloat(RewardOf(pool, recorder)+share))
+	}
+}
@@ -0,0 +1,57 @@
+package verifier
+
+import (
+	"math"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+)
+
+// GasPayer returns who pays gas of info, sponsored tells that it is paid from the budget of a
+// sponsor rather than the iost balance of the publisher. Only sponsors which signed the tx pay
+// for it, the others refund the publisher once the tx ran, see sponsorRefund
+func GasPayer(pool state.Pool, info vm.ContractInfo) (payer vm.IOSTAccount, sponsored bool) {
+	if info.Sponsor != "" && vm.SponsorSigned(pool, info) {
+		return info.Sponsor, true
+	}
+	return info.Publisher, false
+}
+
+// GasBalanceOf returns what the payer of info can spend on gas
+func GasBalanceOf(info vm.ContractInfo, pool state.Pool) float64 {
+	payer, sponsored := GasPayer(pool, info)
+	if sponsored {
+		return vm.SponsorBudget(pool, payer)
+	}
+	return balanceOfSender(payer, pool)
+}
+
+func setGasBalanceOf(info vm.ContractInfo, pool state.Pool, amount float64) {
+	payer, sponsored := GasPayer(pool, info)
+	if sponsored {
+		vm.SetSponsorBudget(pool, payer, amount)
+		return
+	}
+	setBalanceOfSender(payer, pool, amount)
+}
+
+// sponsorRefund returns what the sponsor of info gives back to the publisher of a tx which used gas,
+// inside of it the gas used by calls of the contract of the sponsor. A sponsor which did not sign
+// refunds no more than that gas, its limit and its budget
+func sponsorRefund(pool state.Pool, info vm.ContractInfo, gas, inside uint64) float64 {
+	if info.Sponsor == "" {
+		return 0
+	}
+	if _, sponsored := GasPayer(pool, info); sponsored {
+		return 0
+	}
+	limit, err := vm.GetSponsorLimit(pool, info.Sponsor)
+	if err != nil {
+		return 0
+	}
+	if inside > gas {
+		inside = gas
+	}
+	refund := math.Min(float64(inside)*info.Price, limit)
+	return math.Max(math.Min(refund, vm.SponsorBudget(pool, info.Sponsor)), 0)
+}
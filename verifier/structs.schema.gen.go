@@ -192,3 +192,416 @@ func (d *VerifyLogRaw) Unmarshal(buf []byte) (uint64, error) {
 	}
 	return i + 0, nil
 }
+
+type RewardReceipt struct {
+	Number  int64
+	Witness string
+	Fees    float64
+	Reward  float64
+}
+
+func (d *RewardReceipt) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Witness))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 24
+	return
+}
+func (d *RewardReceipt) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+
+		buf[0+0] = byte(d.Number >> 0)
+
+		buf[1+0] = byte(d.Number >> 8)
+
+		buf[2+0] = byte(d.Number >> 16)
+
+		buf[3+0] = byte(d.Number >> 24)
+
+		buf[4+0] = byte(d.Number >> 32)
+
+		buf[5+0] = byte(d.Number >> 40)
+
+		buf[6+0] = byte(d.Number >> 48)
+
+		buf[7+0] = byte(d.Number >> 56)
+
+	}
+	{
+		l := uint64(len(d.Witness))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+8] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+8] = byte(t)
+			i++
+
+		}
+		copy(buf[i+8:], d.Witness)
+		i += l
+	}
+	{
+
+		v := *(*uint64)(unsafe.Pointer(&(d.Fees)))
+
+		buf[i+0+8] = byte(v >> 0)
+
+		buf[i+1+8] = byte(v >> 8)
+
+		buf[i+2+8] = byte(v >> 16)
+
+		buf[i+3+8] = byte(v >> 24)
+
+		buf[i+4+8] = byte(v >> 32)
+
+		buf[i+5+8] = byte(v >> 40)
+
+		buf[i+6+8] = byte(v >> 48)
+
+		buf[i+7+8] = byte(v >> 56)
+
+	}
+	{
+
+		v := *(*uint64)(unsafe.Pointer(&(d.Reward)))
+
+		buf[i+0+16] = byte(v >> 0)
+
+		buf[i+1+16] = byte(v >> 8)
+
+		buf[i+2+16] = byte(v >> 16)
+
+		buf[i+3+16] = byte(v >> 24)
+
+		buf[i+4+16] = byte(v >> 32)
+
+		buf[i+5+16] = byte(v >> 40)
+
+		buf[i+6+16] = byte(v >> 48)
+
+		buf[i+7+16] = byte(v >> 56)
+
+	}
+	return buf[:i+24], nil
+}
+
+func (d *RewardReceipt) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+
+		d.Number = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+8] & 0x7F)
+			for buf[i+8]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+8]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Witness = string(buf[i+8 : i+8+l])
+		i += l
+	}
+	{
+
+		v := 0 | (uint64(buf[i+0+8]) << 0) | (uint64(buf[i+1+8]) << 8) | (uint64(buf[i+2+8]) << 16) | (uint64(buf[i+3+8]) << 24) | (uint64(buf[i+4+8]) << 32) | (uint64(buf[i+5+8]) << 40) | (uint64(buf[i+6+8]) << 48) | (uint64(buf[i+7+8]) << 56)
+		d.Fees = *(*float64)(unsafe.Pointer(&v))
+
+	}
+	{
+
+		v := 0 | (uint64(buf[i+0+16]) << 0) | (uint64(buf[i+1+16]) << 8) | (uint64(buf[i+2+16]) << 16) | (uint64(buf[i+3+16]) << 24) | (uint64(buf[i+4+16]) << 32) | (uint64(buf[i+5+16]) << 40) | (uint64(buf[i+6+16]) << 48) | (uint64(buf[i+7+16]) << 56)
+		d.Reward = *(*float64)(unsafe.Pointer(&v))
+
+	}
+	return i + 24, nil
+}
+
+type ServiScore struct {
+	Account string
+	Score   float64
+	Height  int64
+}
+
+func (d *ServiScore) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Account))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 16
+	return
+}
+func (d *ServiScore) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Account))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Account)
+		i += l
+	}
+	{
+
+		v := *(*uint64)(unsafe.Pointer(&(d.Score)))
+
+		buf[i+0+0] = byte(v >> 0)
+
+		buf[i+1+0] = byte(v >> 8)
+
+		buf[i+2+0] = byte(v >> 16)
+
+		buf[i+3+0] = byte(v >> 24)
+
+		buf[i+4+0] = byte(v >> 32)
+
+		buf[i+5+0] = byte(v >> 40)
+
+		buf[i+6+0] = byte(v >> 48)
+
+		buf[i+7+0] = byte(v >> 56)
+
+	}
+	{
+
+		buf[i+0+8] = byte(d.Height >> 0)
+
+		buf[i+1+8] = byte(d.Height >> 8)
+
+		buf[i+2+8] = byte(d.Height >> 16)
+
+		buf[i+3+8] = byte(d.Height >> 24)
+
+		buf[i+4+8] = byte(d.Height >> 32)
+
+		buf[i+5+8] = byte(d.Height >> 40)
+
+		buf[i+6+8] = byte(d.Height >> 48)
+
+		buf[i+7+8] = byte(d.Height >> 56)
+
+	}
+	return buf[:i+16], nil
+}
+
+func (d *ServiScore) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Account = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+
+		v := 0 | (uint64(buf[i+0+0]) << 0) | (uint64(buf[i+1+0]) << 8) | (uint64(buf[i+2+0]) << 16) | (uint64(buf[i+3+0]) << 24) | (uint64(buf[i+4+0]) << 32) | (uint64(buf[i+5+0]) << 40) | (uint64(buf[i+6+0]) << 48) | (uint64(buf[i+7+0]) << 56)
+		d.Score = *(*float64)(unsafe.Pointer(&v))
+
+	}
+	{
+
+		d.Height = 0 | (int64(buf[i+0+8]) << 0) | (int64(buf[i+1+8]) << 8) | (int64(buf[i+2+8]) << 16) | (int64(buf[i+3+8]) << 24) | (int64(buf[i+4+8]) << 32) | (int64(buf[i+5+8]) << 40) | (int64(buf[i+6+8]) << 48) | (int64(buf[i+7+8]) << 56)
+
+	}
+	return i + 16, nil
+}
+
+type ServiTop struct {
+	Scores []ServiScore
+}
+
+func (d *ServiTop) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Scores))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Scores {
+
+			{
+				s += d.Scores[k0].Size()
+			}
+
+		}
+
+	}
+	return
+}
+func (d *ServiTop) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Scores))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Scores {
+
+			{
+				nbuf, err := d.Scores[k0].Marshal(buf[i+0:])
+				if err != nil {
+					return nil, err
+				}
+				i += uint64(len(nbuf))
+			}
+
+		}
+	}
+	return buf[:i+0], nil
+}
+
+func (d *ServiTop) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Scores)) >= l {
+			d.Scores = d.Scores[:l]
+		} else {
+			d.Scores = make([]ServiScore, l)
+		}
+		for k0 := range d.Scores {
+
+			{
+				ni, err := d.Scores[k0].Unmarshal(buf[i+0:])
+				if err != nil {
+					return 0, err
+				}
+				i += ni
+			}
+
+		}
+	}
+	return i + 0, nil
+}
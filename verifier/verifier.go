@@ -16,11 +16,41 @@ import (
 	"github.com/iost-official/Go-IOS-Protocol/vm"
 )
 
+const TxBaseFee float64 = 0.01
+
+// Limits of blocks written into chain parameters at genesis, blocks are checked against the
+// parameters recorded in state rather than these values
+var (
+	MaxBlockGas  uint64 = 1000000
+	MaxBlockSize uint64 = 2 << 20
+)
+
+// Chain parameter keys of the block limits
 const (
-	MaxBlockGas uint64  = 1000000
-	TxBaseFee   float64 = 0.01
+	MaxBlockGasKey  state.Key = "max-block-gas"
+	MaxBlockSizeKey state.Key = "max-block-size"
 )
 
+// BlockLimits returns the gas and byte limits of blocks recorded in pool, MaxBlockGas and
+// MaxBlockSize if the chain has none
+func BlockLimits(pool state.Pool) (gas uint64, size uint64) {
+	gas, size = MaxBlockGas, MaxBlockSize
+	if pool == nil {
+		return
+	}
+	if val, err := pool.Get(MaxBlockGasKey); err == nil {
+		if v, ok := val.(*state.VInt); ok && v.ToInt() > 0 {
+			gas = uint64(v.ToInt())
+		}
+	}
+	if val, err := pool.Get(MaxBlockSizeKey); err == nil {
+		if v, ok := val.(*state.VInt); ok && v.ToInt() > 0 {
+			size = uint64(v.ToInt())
+		}
+	}
+	return
+}
+
 //go:generate gencode go -schema=structs.schema -package=verifier
 
 type Verifier struct {
@@ -33,12 +63,17 @@ func (v *Verifier) Verify(contract vm.Contract, pool state.Pool) (state.Pool, ui
 	if err != nil {
 		return pool, 0, err
 	}
+	for prefix := range v.gasInside {
+		delete(v.gasInside, prefix)
+	}
 	_, pool, gas, err := v.Call(v.Context, pool, contract.Info().Prefix, "main")
 	return pool, gas, err
 }
 
 type CacheVerifier struct {
 	Verifier
+	// Fees sums gas fees paid by the contracts verified since it was last reset, they go to the witness
+	Fees float64
 }
 
 func balanceOfSender(sender vm.IOSTAccount, pool state.Pool) float64 {
@@ -56,19 +91,59 @@ func balanceOfSender(sender vm.IOSTAccount, pool state.Pool) float64 {
 	return val.ToFloat64()
 }
 
+// BalanceOf returns the iost balance of sender in pool
+func BalanceOf(sender vm.IOSTAccount, pool state.Pool) float64 {
+	return balanceOfSender(sender, pool)
+}
+
 func setBalanceOfSender(sender vm.IOSTAccount, pool state.Pool, amount float64) {
 	pool.PutHM("iost", state.Key(sender), state.MakeVFloat(amount))
 }
 
+// NonceKey is the state map of the last nonce used by each account
+const NonceKey state.Key = "nonce"
+
+func NonceOf(sender vm.IOSTAccount, pool state.Pool) int64 {
+	val0, err := pool.GetHM(NonceKey, state.Key(sender))
+	if err != nil {
+		return 0
+	}
+	val, ok := val0.(*state.VInt)
+	if !ok {
+		return 0
+	}
+	return int64(val.ToInt())
+}
+
+// CheckNonce makes sure nonce is the next one of sender, so that txs of sender are applied in
+// order and none of them can be replayed
+func CheckNonce(sender vm.IOSTAccount, nonce int64, pool state.Pool) error {
+	expect := NonceOf(sender, pool) + 1
+	if nonce != expect {
+		return fmt.Errorf("wrong nonce: sender:%v nonce:%v expect:%v", string(sender), nonce, expect)
+	}
+	return nil
+}
+
+func SetNonce(sender vm.IOSTAccount, pool state.Pool, nonce int64) {
+	pool.PutHM(NonceKey, state.Key(sender), state.MakeVInt(int(nonce)))
+}
+
 func (cv *CacheVerifier) VerifyContract(contract vm.Contract, pool state.Pool) (state.Pool, error) {
 	if contract.Info().Price < 0 {
 		return pool, errors.New("illegal gas price")
 	}
 
-	sender := contract.Info().Publisher
-	bos := balanceOfSender(sender, pool)
-	if bos < float64(contract.Info().GasLimit)*contract.Info().Price+TxBaseFee {
-		return pool, fmt.Errorf("balance not enough: sender:%v balance:%f\n", string(sender), bos)
+	// gas is paid by the sponsor of the contract if it names one, within the limits the sponsor agreed to
+	info := contract.Info()
+	maxFee := float64(info.GasLimit)*info.Price + TxBaseFee
+	if err := vm.CheckSponsor(pool, contract); err != nil {
+		return pool, err
+	}
+	payer, _ := GasPayer(pool, info)
+	bos := GasBalanceOf(info, pool)
+	if bos < maxFee {
+		return pool, fmt.Errorf("balance not enough: payer:%v balance:%f\n", string(payer), bos)
 	}
 
 	_, err := cv.RestartVM(contract)
@@ -79,19 +154,25 @@ func (cv *CacheVerifier) VerifyContract(contract vm.Contract, pool state.Pool) (
 	if err != nil {
 		return pool, err
 	}
-
-	bos2 := balanceOfSender(sender, pool)
+	bos2 := GasBalanceOf(info, pool)
 
 	if gas > uint64(contract.Info().GasLimit) {
 		return pool, errors.New("gas overflow")
 	}
 
-	bos2 -= float64(gas)*contract.Info().Price + TxBaseFee
+	fee := float64(gas)*contract.Info().Price + TxBaseFee
+	bos2 -= fee
 	if bos2 < 0 {
 		return pool, fmt.Errorf("can not afford gas")
 	}
 
-	setBalanceOfSender(sender, pool, bos2)
+	setGasBalanceOf(info, pool, bos2)
+	// a sponsor which did not sign refunds the gas used inside its contract
+	if refund := sponsorRefund(pool, info, gas, cv.gasInside[string(info.Sponsor)]); refund > 0 {
+		vm.SetSponsorBudget(pool, info.Sponsor, vm.SponsorBudget(pool, info.Sponsor)-refund)
+		setBalanceOfSender(info.Publisher, pool, balanceOfSender(info.Publisher, pool)+refund)
+	}
+	cv.Fees += fee
 	return pool, nil
 }
 
@@ -41,6 +41,9 @@ func TestGenesisVerify(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(count, ShouldEqual, 2)
 			So(k, ShouldEqual, state.Key("iost"))
+			So(f, ShouldEqual, state.Key("def"))
+			So(v.(*state.VFloat).ToFloat64(), ShouldEqual, float64(1000))
+			So(k2, ShouldEqual, state.Key("hello"))
 			So(v2.EncodeString(), ShouldEqual, "sworld")
 
 		})
@@ -109,14 +112,7 @@ end`
 
 			pool.EXPECT().Get(gomock.Any()).AnyTimes().Return(state.MakeVFloat(3.14), nil)
 
-			var k2 state.Key
-			var f2 state.Key
-			var v2 state.Value
-			pool.EXPECT().PutHM(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(key, field state.Key, value state.Value) {
-				k2 = key
-				f2 = field
-				v2 = value
-			})
+			pool.EXPECT().PutHM(gomock.Any(), gomock.Any(), gomock.Any())
 			//v3 := state.MakeVFloat(float64(10000))
 			pool.EXPECT().GetHM(gomock.Any(), gomock.Any()).AnyTimes().Return(state.MakeVFloat(1000000), nil)
 			pool.EXPECT().Copy().AnyTimes().Return(pool)
@@ -599,3 +595,202 @@ end--f
 	}
 
 }
+
+func TestBlockLimits(t *testing.T) {
+	Convey("Test of BlockLimits", t, func() {
+		mockCtl := gomock.NewController(t)
+		pool := core_mock.NewMockPool(mockCtl)
+		pool.EXPECT().Get(MaxBlockGasKey).Return(state.MakeVInt(5000), nil)
+		pool.EXPECT().Get(MaxBlockSizeKey).Return(state.VNil, nil)
+
+		gas, size := BlockLimits(pool)
+		So(gas, ShouldEqual, 5000)
+		So(size, ShouldEqual, MaxBlockSize)
+
+		gas, _ = BlockLimits(nil)
+		So(gas, ShouldEqual, MaxBlockGas)
+	})
+}
+
+func TestPayBlockReward(t *testing.T) {
+	Convey("Test of PayBlockReward", t, func() {
+		mdb, _ := db.NewMemDatabase()
+		pool := state.NewPool(state.NewDatabase(mdb))
+		pool.Put(BlockRewardKey, state.MakeVFloat(8))
+		pool.Put(RewardHalvingKey, state.MakeVInt(10))
+		pool.PutHM("iost", "w", state.MakeVFloat(1))
+
+		So(BlockRewardAt(pool, 0), ShouldEqual, 0)
+		So(BlockRewardAt(pool, 10), ShouldEqual, 8)
+		So(BlockRewardAt(pool, 11), ShouldEqual, 4)
+		So(BlockRewardAt(pool, 21), ShouldEqual, 2)
+
+		receipt := PayBlockReward(pool, "w", 11, 0.5)
+		So(receipt.Reward, ShouldEqual, 4)
+		So(BalanceOf("w", pool), ShouldEqual, 5.5)
+		So(RewardOf(pool, "w"), ShouldEqual, 4.5)
+
+		got, err := GetRewardReceipt(pool, 11)
+		So(err, ShouldBeNil)
+		So(*got, ShouldResemble, *receipt)
+		_, err = GetRewardReceipt(pool, 12)
+		So(err, ShouldNotBeNil)
+
+		PayBlockReward(pool, "w", 11+RewardReceiptsKept, 0)
+		_, err = GetRewardReceipt(pool, 11)
+		So(err, ShouldNotBeNil)
+		_, err = GetRewardReceipt(pool, 11+RewardReceiptsKept)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestRecordServi(t *testing.T) {
+	Convey("Test of RecordServi", t, func() {
+		mdb, _ := db.NewMemDatabase()
+		pool := state.NewPool(state.NewDatabase(mdb))
+		pool.Put(ServiRewardKey, state.MakeVFloat(1))
+		pool.Put(ServiPeriodKey, state.MakeVInt(4))
+
+		RecordServi(pool, []Recording{{"b", "x"}, {"a", "x"}, {"a", "y"}, {"a", "y"}}, 1)
+		So(ServiOf(pool, "a", 1), ShouldEqual, 2)
+		So(ServiOf(pool, "b", 1), ShouldEqual, 1)
+		So(BalanceOf("a", pool), ShouldEqual, 0)
+
+		RecordServi(pool, []Recording{{"a", "x"}, {"a", "z"}}, 4)
+		top := ServiTopOf(pool, 4)
+		So(len(top), ShouldEqual, 2)
+		So(top[0].Account, ShouldEqual, "a")
+		So(top[0].Score, ShouldEqual, 3)
+		So(BalanceOf("a", pool), ShouldEqual, 3)
+		So(BalanceOf("b", pool), ShouldEqual, 1)
+		So(RewardOf(pool, "b"), ShouldEqual, 1)
+
+		So(ServiOf(pool, "a", 5), ShouldAlmostEqual, 3*ServiDecay)
+		So(ServiOf(pool, "c", 5), ShouldEqual, 0)
+
+		RecordServi(pool, []Recording{{"a", "x"}}, 5)
+		So(ServiOf(pool, "a", 5), ShouldAlmostEqual, 3*ServiDecay+1)
+	})
+}
+
+func TestSponsor(t *testing.T) {
+	Convey("Test of sponsored gas", t, func() {
+		main := lua.NewMethod(vm.Public, "main", 0, 1)
+		code := `function main()
+	Transfer("a", "b", 50)
+end`
+		mdb, _ := db.NewMemDatabase()
+		pool := state.NewPool(state.NewDatabase(mdb))
+		pool.PutHM("iost", "a", state.MakeVFloat(100))
+		vm.SetSponsorBudget(pool, "dapp", 100)
+		cv := NewCacheVerifier()
+
+		Convey("sponsor which did not sign refunds gas used inside its contract", func() {
+			hello := lua.NewMethod(vm.Public, "hello", 0, 1)
+			dapp := lua.NewContract(vm.ContractInfo{Prefix: "dapp", GasLimit: 10000, Price: 0.001, Publisher: "dapp"}, `function hello()
+	local n = 0
+	for i = 1, 100 do
+		n = n + i
+	end
+	return n
+end`, hello, hello)
+			cv.StartVM(&dapp)
+			callCode := `function main()
+	Call("dapp", "hello")
+	Transfer("a", "b", 50)
+end`
+			lc := lua.NewContract(vm.ContractInfo{Prefix: "test", GasLimit: 10000, Price: 0.001, Publisher: "a", Sponsor: "dapp"}, callCode, main)
+			_, err := cv.VerifyContract(&lc, pool.Copy())
+			So(err, ShouldEqual, vm.ErrSponsorRefused)
+
+			vm.PutSponsorLimit(pool, "dapp", 20)
+			pool2, err := cv.VerifyContract(&lc, pool.Copy())
+			So(err, ShouldBeNil)
+			refund := 100 - vm.SponsorBudget(pool2, "dapp")
+			So(refund, ShouldBeGreaterThan, 0)
+			So(refund, ShouldBeLessThan, cv.Fees-TxBaseFee)
+			So(BalanceOf("a", pool2), ShouldAlmostEqual, 50-cv.Fees+refund)
+
+			vm.PutSponsorLimit(pool, "dapp", 0.00001)
+			cv.Fees = 0
+			pool2, err = cv.VerifyContract(&lc, pool.Copy())
+			So(err, ShouldBeNil)
+			So(vm.SponsorBudget(pool2, "dapp"), ShouldAlmostEqual, 100-0.00001)
+
+			cv.Fees = 0
+			other := lua.NewContract(vm.ContractInfo{Prefix: "test", GasLimit: 10000, Price: 0.001, Publisher: "a", Sponsor: "dapp"}, code, main)
+			pool2, err = cv.VerifyContract(&other, pool.Copy())
+			So(err, ShouldBeNil)
+			So(vm.SponsorBudget(pool2, "dapp"), ShouldEqual, 100)
+			So(BalanceOf("a", pool2), ShouldAlmostEqual, 50-cv.Fees)
+		})
+
+		Convey("sponsor which signed pays without limit", func() {
+			lc := lua.NewContract(vm.ContractInfo{Prefix: "test", GasLimit: 10000, Price: 0.001, Publisher: "a", Sponsor: "dapp"}, code, main)
+			lc.AddSigner("dapp")
+			pool2, err := cv.VerifyContract(&lc, pool)
+			So(err, ShouldBeNil)
+			So(BalanceOf("a", pool2), ShouldEqual, 50)
+			So(vm.SponsorBudget(pool2, "dapp"), ShouldBeLessThan, 100)
+		})
+	})
+}
+
+func TestRunDeferred(t *testing.T) {
+	Convey("Test of RunDeferred", t, func() {
+		main := lua.NewMethod(vm.Public, "main", 0, 1)
+		pay := lua.NewMethod(vm.Public, "pay", 1, 0)
+		code := `function main()
+	return true
+end
+function pay(v)
+	Transfer("a", "b", v)
+end`
+		lc := lua.NewContract(vm.ContractInfo{Prefix: "payroll", GasLimit: 10000, Price: 0.001, Publisher: "c"}, code, main, pay)
+
+		mdb, _ := db.NewMemDatabase()
+		pool := state.NewPool(state.NewDatabase(mdb))
+		// a prepaid the deposits of both calls
+		pool.PutHM("iost", "a", state.MakeVFloat(80))
+		cv := NewCacheVerifier()
+		_, err := cv.StartVM(&lc)
+		So(err, ShouldBeNil)
+
+		byHeight := vm.DeferredCall{Owner: "a", Contract: "payroll", Method: "pay", Args: []state.Value{state.MakeVFloat(10)}, Height: 6, GasLimit: 10000, Price: 0.001}
+		So(vm.PutDeferred(pool, &byHeight), ShouldBeNil)
+		byTime := vm.DeferredCall{Owner: "a", Contract: "payroll", Method: "pay", Args: []state.Value{state.MakeVFloat(10)}, Time: 103, GasLimit: 10000, Price: 0.001}
+		So(vm.PutDeferred(pool, &byTime), ShouldBeNil)
+
+		cv.Context = &vm.Context{BlockHeight: 6, Timestamp: 101}
+		cv.RunDeferred(pool)
+		So(BalanceOf("b", pool), ShouldEqual, 10)
+		_, err = vm.GetDeferred(pool, byHeight.ID)
+		So(err, ShouldNotBeNil)
+
+		// the slot of byTime was skipped
+		cv.Context = &vm.Context{BlockHeight: 7, Timestamp: 104}
+		So(cv.RunDeferred(pool), ShouldEqual, 10000)
+		So(BalanceOf("b", pool), ShouldEqual, 20)
+		So(BalanceOf("a", pool)+BalanceOf("b", pool)+cv.Fees, ShouldAlmostEqual, 100)
+
+		Convey("calls over the bounds of a block wait for the next blocks", func() {
+			pool.Put(vm.MaxDeferredGasKey, state.MakeVInt(25000))
+			pool.Put(vm.MaxDeferredPerBlockKey, state.MakeVInt(3))
+			pool.PutHM("iost", "a", state.MakeVFloat(1000))
+			ids := make([]string, 0)
+			for i := 0; i < 4; i++ {
+				call := vm.DeferredCall{Owner: "a", Contract: "payroll", Method: "pay", Args: []state.Value{state.MakeVFloat(1)}, Time: 105, GasLimit: 10000, Price: 0.001}
+				So(vm.PutDeferred(pool, &call), ShouldBeNil)
+				ids = append(ids, call.ID)
+			}
+			cv.Context = &vm.Context{BlockHeight: 8, Timestamp: 1e9}
+			So(cv.RunDeferred(pool), ShouldEqual, 20000)
+			So(vm.DueDeferred(pool, 8, 1e9, 3), ShouldResemble, ids[2:])
+
+			cv.Context = &vm.Context{BlockHeight: 9, Timestamp: 1e9 + 3}
+			So(cv.RunDeferred(pool), ShouldEqual, 20000)
+			So(vm.DueDeferred(pool, 9, 1e9+3, 3), ShouldBeEmpty)
+			So(BalanceOf("b", pool), ShouldEqual, 24)
+		})
+	})
+}
@@ -1,5 +1,12 @@
 package vm
 
+// Least gas limit the lua vm accepts to start a contract and least price of gas, txs and deferred
+// calls below them are rejected
+var (
+	MinGasLimit int64 = 1000
+	MinGasPrice       = 0.0
+)
+
 type ContractInfo struct {
 	Prefix   string
 	Language string
@@ -10,6 +17,8 @@ type ContractInfo struct {
 
 	Signers   []IOSTAccount
 	Publisher IOSTAccount
+	// Sponsor pays gas of the tx from its budget, or part of it, see CheckSponsor
+	Sponsor IOSTAccount
 }
 
 func (c *ContractInfo) toRaw() contractInfoRaw {
@@ -18,6 +27,7 @@ func (c *ContractInfo) toRaw() contractInfoRaw {
 		Version:  c.Version,
 		GasLimit: c.GasLimit,
 		Price:    c.Price,
+		Sponsor:  string(c.Sponsor),
 	}
 }
 
@@ -40,5 +50,6 @@ func (c *ContractInfo) Decode(b []byte) error {
 	c.Version = cir.Version
 	c.GasLimit = cir.GasLimit
 	c.Price = cir.Price
+	c.Sponsor = IOSTAccount(cir.Sponsor)
 	return nil
 }
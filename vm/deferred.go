@@ -0,0 +1,322 @@
+package vm
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+)
+
+// State of deferred calls: DeferredKey maps ids to calls, DeferredHeightKey and DeferredTimeKey map
+// block heights and block times to the ids due then, DeferredHeightIndexKey and DeferredTimeIndexKey
+// hold the sorted heights and times which have pending calls and DeferredSeqKey numbers the calls
+const (
+	DeferredKey            state.Key = "deferred"
+	DeferredHeightKey      state.Key = "deferred-height"
+	DeferredTimeKey        state.Key = "deferred-time"
+	DeferredHeightIndexKey state.Key = "deferred-heights"
+	DeferredTimeIndexKey   state.Key = "deferred-times"
+	DeferredSeqKey         state.Key = "deferred-seq"
+)
+
+// MaxDeferredCalls is the max number of calls due at one block height or one block time
+const MaxDeferredCalls = 100
+
+// Bounds of the deferred calls run at a block written into chain parameters at genesis. The calls
+// of a block are given at most MaxDeferredGas in total, which counts against the gas of the block,
+// and there are at most MaxDeferredPerBlock of them. Calls left over stay due for the next blocks
+var (
+	MaxDeferredGas      int64 = 500000
+	MaxDeferredPerBlock       = 20
+)
+
+// Chain parameter keys of the bounds of deferred calls
+const (
+	MaxDeferredGasKey      state.Key = "max-deferred-gas"
+	MaxDeferredPerBlockKey state.Key = "max-deferred-per-block"
+)
+
+var (
+	ErrDeferredNotFound = errors.New("deferred call not found")
+	ErrDeferredFull     = errors.New("too many deferred calls at the same slot")
+	ErrInvalidDeferred  = errors.New("invalid deferred call")
+)
+
+// DeferredCall invokes Method of Contract with Args at block Height or, if Height is 0, at the first
+// block whose time is not before Time. Owner prepaid GasLimit*Price, gas left unused is refunded
+type DeferredCall struct {
+	ID       string
+	Owner    IOSTAccount
+	Contract string
+	Method   string
+	Args     []state.Value
+	Height   int64
+	Time     int64
+	GasLimit int64
+	Price    float64
+}
+
+// Deposit is what the owner pays when scheduling c
+func (c *DeferredCall) Deposit() float64 {
+	return float64(c.GasLimit) * c.Price
+}
+
+func (c *DeferredCall) toRaw() deferredCallRaw {
+	args := make([]string, 0, len(c.Args))
+	for _, a := range c.Args {
+		args = append(args, a.EncodeString())
+	}
+	return deferredCallRaw{
+		ID:       c.ID,
+		Owner:    string(c.Owner),
+		Contract: c.Contract,
+		Method:   c.Method,
+		Args:     args,
+		Height:   c.Height,
+		Time:     c.Time,
+		GasLimit: c.GasLimit,
+		Price:    c.Price,
+	}
+}
+
+func (c *DeferredCall) Encode() []byte {
+	cr := c.toRaw()
+	buf, err := cr.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+	return buf
+}
+
+func (c *DeferredCall) Decode(b []byte) error {
+	cr := deferredCallRaw{}
+	_, err := cr.Unmarshal(b)
+	if err != nil {
+		return err
+	}
+	args := make([]state.Value, 0, len(cr.Args))
+	for _, a := range cr.Args {
+		v, err := state.ParseValue(a)
+		if err != nil {
+			return err
+		}
+		args = append(args, v)
+	}
+	*c = DeferredCall{
+		ID:       cr.ID,
+		Owner:    IOSTAccount(cr.Owner),
+		Contract: cr.Contract,
+		Method:   cr.Method,
+		Args:     args,
+		Height:   cr.Height,
+		Time:     cr.Time,
+		GasLimit: cr.GasLimit,
+		Price:    cr.Price,
+	}
+	return nil
+}
+
+// DeferredLimits returns the gas and the number of deferred calls a block may run recorded in pool,
+// MaxDeferredGas and MaxDeferredPerBlock if the chain has none
+func DeferredLimits(pool state.Pool) (gas int64, count int) {
+	gas, count = MaxDeferredGas, MaxDeferredPerBlock
+	if val, err := pool.Get(MaxDeferredGasKey); err == nil {
+		if v, ok := val.(*state.VInt); ok && v.ToInt() > 0 {
+			gas = int64(v.ToInt())
+		}
+	}
+	if val, err := pool.Get(MaxDeferredPerBlockKey); err == nil {
+		if v, ok := val.(*state.VInt); ok && v.ToInt() > 0 {
+			count = v.ToInt()
+		}
+	}
+	return
+}
+
+// bucket returns the state map and field listing c among the calls due with it, and the index of
+// the slots of that map
+func (c *DeferredCall) bucket() (key, field, index state.Key, slot int64) {
+	if c.Height > 0 {
+		return DeferredHeightKey, slotField(c.Height), DeferredHeightIndexKey, c.Height
+	}
+	return DeferredTimeKey, slotField(c.Time), DeferredTimeIndexKey, c.Time
+}
+
+func slotField(slot int64) state.Key {
+	return state.Key(strconv.FormatInt(slot, 10))
+}
+
+func getDeferredQueue(pool state.Pool, key, field state.Key) []string {
+	val, err := pool.GetHM(key, field)
+	if err != nil {
+		return nil
+	}
+	b, ok := val.(*state.VBytes)
+	if !ok {
+		return nil
+	}
+	var q deferredQueueRaw
+	if _, err := q.Unmarshal(b.ToBytes()); err != nil {
+		return nil
+	}
+	return q.IDs
+}
+
+func putDeferredQueue(pool state.Pool, key, field state.Key, ids []string) {
+	if len(ids) == 0 {
+		pool.PutHM(key, field, state.VNil)
+		return
+	}
+	q := deferredQueueRaw{IDs: ids}
+	buf, err := q.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+	pool.PutHM(key, field, state.MakeVByte(buf))
+}
+
+func getDeferredIndex(pool state.Pool, index state.Key) []int64 {
+	val, err := pool.Get(index)
+	if err != nil {
+		return nil
+	}
+	b, ok := val.(*state.VBytes)
+	if !ok {
+		return nil
+	}
+	var idx deferredIndexRaw
+	if _, err := idx.Unmarshal(b.ToBytes()); err != nil {
+		return nil
+	}
+	return idx.Slots
+}
+
+func putDeferredIndex(pool state.Pool, index state.Key, slots []int64) {
+	if len(slots) == 0 {
+		pool.Put(index, state.VNil)
+		return
+	}
+	idx := deferredIndexRaw{Slots: slots}
+	buf, err := idx.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+	pool.Put(index, state.MakeVByte(buf))
+}
+
+// addDeferredSlot inserts slot into the sorted slots of index
+func addDeferredSlot(pool state.Pool, index state.Key, slot int64) {
+	slots := getDeferredIndex(pool, index)
+	i := sort.Search(len(slots), func(i int) bool { return slots[i] >= slot })
+	if i < len(slots) && slots[i] == slot {
+		return
+	}
+	slots = append(slots, 0)
+	copy(slots[i+1:], slots[i:])
+	slots[i] = slot
+	putDeferredIndex(pool, index, slots)
+}
+
+func delDeferredSlot(pool state.Pool, index state.Key, slot int64) {
+	slots := getDeferredIndex(pool, index)
+	rest := make([]int64, 0, len(slots))
+	for _, s := range slots {
+		if s != slot {
+			rest = append(rest, s)
+		}
+	}
+	putDeferredIndex(pool, index, rest)
+}
+
+// GetDeferred returns the pending call id
+func GetDeferred(pool state.Pool, id string) (*DeferredCall, error) {
+	val, err := pool.GetHM(DeferredKey, state.Key(id))
+	if err != nil {
+		return nil, err
+	}
+	b, ok := val.(*state.VBytes)
+	if !ok {
+		return nil, ErrDeferredNotFound
+	}
+	var c DeferredCall
+	if err := c.Decode(b.ToBytes()); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// PutDeferred numbers c and stores it among the calls due at its height or time
+func PutDeferred(pool state.Pool, c *DeferredCall) error {
+	maxGas, _ := DeferredLimits(pool)
+	if c.Contract == "" || c.Method == "" || c.GasLimit < MinGasLimit || c.GasLimit > maxGas ||
+		c.Price < 0 || c.Price < MinGasPrice || (c.Height <= 0 && c.Time <= 0) {
+		return ErrInvalidDeferred
+	}
+	key, field, index, slot := c.bucket()
+	ids := getDeferredQueue(pool, key, field)
+	if len(ids) >= MaxDeferredCalls {
+		return ErrDeferredFull
+	}
+
+	var seq int64
+	if val, err := pool.Get(DeferredSeqKey); err == nil {
+		if v, ok := val.(*state.VInt); ok {
+			seq = int64(v.ToInt())
+		}
+	}
+	seq++
+	pool.Put(DeferredSeqKey, state.MakeVInt(int(seq)))
+	c.ID = strconv.FormatInt(seq, 10)
+
+	if len(ids) == 0 {
+		addDeferredSlot(pool, index, slot)
+	}
+	putDeferredQueue(pool, key, field, append(ids, c.ID))
+	return pool.PutHM(DeferredKey, state.Key(c.ID), state.MakeVByte(c.Encode()))
+}
+
+// DelDeferred removes the pending call id
+func DelDeferred(pool state.Pool, id string) error {
+	c, err := GetDeferred(pool, id)
+	if err != nil {
+		return err
+	}
+	key, field, index, slot := c.bucket()
+	ids := getDeferredQueue(pool, key, field)
+	rest := make([]string, 0, len(ids))
+	for _, i := range ids {
+		if i != id {
+			rest = append(rest, i)
+		}
+	}
+	if len(rest) == 0 {
+		delDeferredSlot(pool, index, slot)
+	}
+	putDeferredQueue(pool, key, field, rest)
+	return pool.PutHM(DeferredKey, state.Key(id), state.VNil)
+}
+
+// DueDeferred returns the ids of at most n calls due at the block of height and time, calls by
+// height before calls by time, each in the order they fell due and were scheduled. Calls stay
+// pending until deleted, so calls left over or in skipped slots are due at the next blocks
+func DueDeferred(pool state.Pool, height, time int64, n int) []string {
+	ids := make([]string, 0)
+	ids = dueDeferred(pool, DeferredHeightIndexKey, DeferredHeightKey, height, n, ids)
+	return dueDeferred(pool, DeferredTimeIndexKey, DeferredTimeKey, time, n, ids)
+}
+
+func dueDeferred(pool state.Pool, index, key state.Key, until int64, n int, ids []string) []string {
+	for _, slot := range getDeferredIndex(pool, index) {
+		if slot > until {
+			break
+		}
+		for _, id := range getDeferredQueue(pool, key, slotField(slot)) {
+			if len(ids) >= n {
+				return ids
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
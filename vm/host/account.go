@@ -0,0 +1,51 @@
+package host
+
+import (
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+)
+
+// NewAccount registers name with a single owner key and a single active key, fails if name is taken
+func NewAccount(pool state.Pool, name string, owner, active vm.IOSTAccount) bool {
+	if vm.IsAccountName(string(owner)) || vm.IsAccountName(string(active)) {
+		return false
+	}
+	if _, err := vm.GetNamedAccount(pool, name); err == nil {
+		return false
+	}
+	return vm.PutNamedAccount(pool, vm.NewNamedAccount(name, owner, active)) == nil
+}
+
+// SetAccountKeys replaces permission level of name, this is how keys are rotated. The keys which
+// signed the running contract must satisfy the owner permission of name
+func SetAccountKeys(pool state.Pool, ctx *vm.Context, info vm.ContractInfo, name string, level vm.PermissionLevel, perm vm.Permission) bool {
+	acc, err := vm.GetNamedAccount(pool, name)
+	if err != nil {
+		return false
+	}
+	if !vm.CheckPermission(pool, ctx, info, name, vm.Owner) {
+		return false
+	}
+	for _, k := range perm.Keys {
+		if vm.IsAccountName(string(k.ID)) {
+			return false
+		}
+	}
+	*acc.Permission(level) = perm
+	return vm.PutNamedAccount(pool, acc) == nil
+}
+
+// SetMultisig puts account under the control of perm, afterwards every privileged operation of
+// account needs perm satisfied. The running contract must have the privilege of account, which
+// for an account already under multisig means satisfying its current policy
+func SetMultisig(pool state.Pool, ctx *vm.Context, info vm.ContractInfo, account string, perm vm.Permission) bool {
+	if vm.CheckPrivilege(pool, ctx, info, account) <= 0 {
+		return false
+	}
+	return vm.PutMultisig(pool, vm.IOSTAccount(account), perm) == nil
+}
+
+// SetSponsorLimit offers to pay gas up to maxFee for every tx naming sponsor, 0 withdraws the offer
+func SetSponsorLimit(pool state.Pool, sponsor string, maxFee float64) bool {
+	return vm.PutSponsorLimit(pool, vm.IOSTAccount(sponsor), maxFee) == nil
+}
@@ -0,0 +1,36 @@
+package host
+
+import (
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
+)
+
+// Schedule stores call to run at a future block, its owner prepays the gas. Returns the id of call
+func Schedule(pool state.Pool, ctx *vm.Context, call *vm.DeferredCall) (string, bool) {
+	if call.Height > 0 && call.Height <= BlockHeight(ctx) {
+		return "", false
+	}
+	if call.Height <= 0 && call.Time <= Now(ctx) {
+		return "", false
+	}
+	if err := changeToken(pool, "iost", state.Key(call.Owner), -call.Deposit()); err != nil {
+		return "", false
+	}
+	if err := vm.PutDeferred(pool, call); err != nil {
+		changeToken(pool, "iost", state.Key(call.Owner), call.Deposit())
+		return "", false
+	}
+	return call.ID, true
+}
+
+// CancelSchedule removes the pending call id and refunds its deposit to the owner
+func CancelSchedule(pool state.Pool, id string) bool {
+	call, err := vm.GetDeferred(pool, id)
+	if err != nil {
+		return false
+	}
+	if err := vm.DelDeferred(pool, id); err != nil {
+		return false
+	}
+	return changeToken(pool, "iost", state.Key(call.Owner), call.Deposit()) == nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/iost-official/Go-IOS-Protocol/core/state"
 	"github.com/iost-official/Go-IOS-Protocol/db"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -24,3 +25,142 @@ func TestTransfer(t *testing.T) {
 
 	})
 }
+
+func TestNFT(t *testing.T) {
+	Convey("Test of non-fungible token", t, func() {
+		db, _ := db.DatabaseFactory("redis")
+		mdb := state.NewDatabase(db)
+		pool := state.NewPool(mdb)
+
+		id, ok := MintNFT(pool, "contract", "a", "cat", []byte(`{"color":"black"}`))
+		So(ok, ShouldBeTrue)
+		So(id, ShouldEqual, "contract.cat")
+		_, ok = MintNFT(pool, "contract", "b", "cat", nil)
+		So(ok, ShouldBeFalse)
+		So(NFTOwner(pool, id), ShouldEqual, "a")
+		So(NFTIssuer(pool, id), ShouldEqual, "contract")
+		meta, ok := NFTMetadata(pool, id)
+		So(ok, ShouldBeTrue)
+		So(string(meta), ShouldEqual, `{"color":"black"}`)
+
+		So(TransferNFT(pool, "b", "b", id), ShouldBeFalse)
+		So(ApproveNFT(pool, "a", "b", id), ShouldBeTrue)
+		So(TransferNFT(pool, "b", "c", id), ShouldBeTrue)
+		So(NFTOwner(pool, id), ShouldEqual, "c")
+		So(NFTApproved(pool, id), ShouldEqual, "")
+		So(NFTsOf(pool, "a"), ShouldBeEmpty)
+		So(NFTsOf(pool, "c"), ShouldResemble, []string{id})
+
+		So(SetNFTOperator(pool, "c", "d", true), ShouldBeTrue)
+		So(BurnNFT(pool, "d", id), ShouldBeTrue)
+		So(NFTOwner(pool, id), ShouldEqual, "")
+		So(NFTsOf(pool, "c"), ShouldBeEmpty)
+		So(IsNFTBurned(pool, id), ShouldBeTrue)
+		_, ok = MintNFT(pool, "contract", "a", "cat", nil)
+		So(ok, ShouldBeFalse)
+
+		_, ok = MintNFT(pool, "contract", "a", "dog:1,", nil)
+		So(ok, ShouldBeFalse)
+		_, ok = MintNFT(pool, "contract", "a:b", "dog", nil)
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestNamedAccount(t *testing.T) {
+	Convey("Test of named account", t, func() {
+		db, _ := db.DatabaseFactory("redis")
+		mdb := state.NewDatabase(db)
+		pool := state.NewPool(mdb)
+
+		So(NewAccount(pool, "alice", "OwnerKey", "ActiveKey"), ShouldBeTrue)
+		So(NewAccount(pool, "alice", "OwnerKey", "ActiveKey"), ShouldBeFalse)
+		So(NewAccount(pool, "Alice!", "OwnerKey", "ActiveKey"), ShouldBeFalse)
+
+		info := vm.ContractInfo{Publisher: "ActiveKey"}
+		So(vm.CheckPrivilege(pool, nil, info, "alice"), ShouldEqual, 1)
+		So(vm.CheckPermission(pool, nil, info, "alice", vm.Owner), ShouldBeFalse)
+
+		perm := vm.Permission{Threshold: 2, Keys: []vm.KeyWeight{{ID: "Key1", Weight: 1}, {ID: "Key2", Weight: 1}}}
+		So(SetAccountKeys(pool, nil, info, "alice", vm.Active, perm), ShouldBeFalse)
+		owner := vm.ContractInfo{Publisher: "OwnerKey"}
+		So(SetAccountKeys(pool, nil, owner, "alice", vm.Active, perm), ShouldBeTrue)
+		So(vm.CheckPrivilege(pool, nil, info, "alice"), ShouldEqual, 0)
+
+		ctx := vm.BaseContext()
+		ctx.Publisher = "Key1"
+		So(vm.CheckPrivilege(pool, ctx, info, "alice"), ShouldEqual, 0)
+		ctx.Signers = []vm.IOSTAccount{"Key2"}
+		So(vm.CheckPrivilege(pool, ctx, info, "alice"), ShouldEqual, 1)
+		So(vm.CheckPrivilege(pool, ctx, vm.ContractInfo{Publisher: "OwnerKey"}, "alice"), ShouldEqual, 1)
+
+		So(SetAccountKeys(pool, nil, owner, "alice", vm.Owner, vm.Permission{Threshold: 3, Keys: perm.Keys}), ShouldBeFalse)
+		So(SetAccountKeys(pool, nil, owner, "bob", vm.Owner, perm), ShouldBeFalse)
+	})
+}
+
+func TestMultisig(t *testing.T) {
+	Convey("Test of multisig account", t, func() {
+		db, _ := db.DatabaseFactory("redis")
+		mdb := state.NewDatabase(db)
+		pool := state.NewPool(mdb)
+
+		perm := vm.Permission{Threshold: 2, Keys: []vm.KeyWeight{{ID: "KeyA", Weight: 1}, {ID: "KeyB", Weight: 1}, {ID: "KeyC", Weight: 1}}}
+		info := vm.ContractInfo{Publisher: "Vault"}
+		So(SetMultisig(pool, nil, vm.ContractInfo{Publisher: "Other"}, "Vault", perm), ShouldBeFalse)
+		So(SetMultisig(pool, nil, info, "Vault", perm), ShouldBeTrue)
+		So(SetMultisig(pool, nil, vm.ContractInfo{Publisher: "vault"}, "vault", perm), ShouldBeFalse)
+		So(SetMultisig(pool, nil, vm.ContractInfo{Publisher: "Vault2"}, "Vault2", vm.Permission{Threshold: 4, Keys: perm.Keys}), ShouldBeFalse)
+		So(SetMultisig(pool, nil, info, "Vault", vm.Permission{}), ShouldBeFalse)
+
+		So(vm.CheckPrivilege(pool, nil, info, "Vault"), ShouldEqual, 0)
+		info.Signers = []vm.IOSTAccount{"KeyA"}
+		So(vm.CheckPrivilege(pool, nil, info, "Vault"), ShouldEqual, 0)
+		So(vm.CheckMultisig(pool, nil, info, "Vault"), ShouldBeFalse)
+		info.Signers = append(info.Signers, "KeyC")
+		So(vm.CheckPrivilege(pool, nil, info, "Vault"), ShouldEqual, 2)
+		So(vm.CheckMultisig(pool, nil, info, "Vault"), ShouldBeTrue)
+		So(vm.CheckPrivilege(pool, nil, vm.ContractInfo{Signers: info.Signers}, "Vault"), ShouldEqual, 1)
+		So(vm.CheckMultisig(pool, nil, vm.ContractInfo{}, "Other"), ShouldBeTrue)
+
+		pool.Put(vm.MultisigKey, state.MakeVString("broken"))
+		So(vm.CheckMultisig(pool, nil, info, "Vault"), ShouldBeFalse)
+		So(vm.CheckPrivilege(pool, nil, info, "Vault"), ShouldEqual, 0)
+	})
+}
+
+func TestSchedule(t *testing.T) {
+	Convey("Test of deferred calls", t, func() {
+		db, _ := db.DatabaseFactory("redis")
+		mdb := state.NewDatabase(db)
+		pool := state.NewPool(mdb)
+		pool.PutHM("iost", "a", state.MakeVFloat(100))
+		ctx := &vm.Context{BlockHeight: 5, Timestamp: 100}
+
+		call := vm.DeferredCall{Owner: "a", Contract: "payroll", Method: "pay", Height: 6, GasLimit: 10000, Price: 0.001}
+		id, ok := Schedule(pool, ctx, &call)
+		So(ok, ShouldBeTrue)
+		aa, _ := pool.GetHM("iost", "a")
+		So(aa.(*state.VFloat).ToFloat64(), ShouldEqual, 90)
+		So(vm.DueDeferred(pool, 6, 101, vm.MaxDeferredPerBlock), ShouldResemble, []string{id})
+		So(vm.DueDeferred(pool, 5, 101, vm.MaxDeferredPerBlock), ShouldBeEmpty)
+
+		past := vm.DeferredCall{Owner: "a", Contract: "payroll", Method: "pay", Time: 100, GasLimit: 10000, Price: 0.001}
+		_, ok = Schedule(pool, ctx, &past)
+		So(ok, ShouldBeFalse)
+		poor := vm.DeferredCall{Owner: "a", Contract: "payroll", Method: "pay", Time: 101, GasLimit: 100000, Price: 0.001}
+		_, ok = Schedule(pool, ctx, &poor)
+		So(ok, ShouldBeFalse)
+		huge := vm.DeferredCall{Owner: "a", Contract: "payroll", Method: "pay", Time: 101, GasLimit: vm.MaxDeferredGas + 1, Price: 0}
+		_, ok = Schedule(pool, ctx, &huge)
+		So(ok, ShouldBeFalse)
+		tiny := vm.DeferredCall{Owner: "a", Contract: "payroll", Method: "pay", Time: 101, GasLimit: vm.MinGasLimit - 1, Price: 0.001}
+		_, ok = Schedule(pool, ctx, &tiny)
+		So(ok, ShouldBeFalse)
+
+		So(CancelSchedule(pool, id), ShouldBeTrue)
+		So(CancelSchedule(pool, id), ShouldBeFalse)
+		aa, _ = pool.GetHM("iost", "a")
+		So(aa.(*state.VFloat).ToFloat64(), ShouldEqual, 100)
+		So(vm.DueDeferred(pool, 6, 102, vm.MaxDeferredPerBlock), ShouldBeEmpty)
+	})
+}
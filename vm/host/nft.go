@@ -0,0 +1,194 @@
+package host
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+)
+
+// Layout of non-fungible tokens in state:
+//
+//	nft-owner        tokenID  -> owner account
+//	nft-issuer       tokenID  -> prefix of the minting contract
+//	nft-meta         tokenID  -> metadata bytes
+//	nft-approval     tokenID  -> account approved to move the token
+//	nft-burned       tokenID  -> true for every burned token, which can not be minted again
+//	nft-operator.<owner>  operator -> true if operator can move every token of owner
+//	nft-owned.<owner>     tokenID  -> true for every token held by owner
+const (
+	NFTOwnerKey    state.Key = "nft-owner"
+	NFTIssuerKey   state.Key = "nft-issuer"
+	NFTMetaKey     state.Key = "nft-meta"
+	NFTApprovalKey state.Key = "nft-approval"
+	NFTBurnedKey   state.Key = "nft-burned"
+)
+
+// ids and accounts are keys of state maps, so they must not contain the separators of map encoding
+var nftIDRegexp = regexp.MustCompile("^[a-zA-Z0-9_-]{1,64}$")
+
+func validNFTAccount(acc string) bool {
+	return acc != "" && !strings.ContainsAny(acc, ":,")
+}
+
+func NFTOperatorKey(owner string) state.Key {
+	return state.Key("nft-operator." + owner)
+}
+
+func NFTOwnedKey(owner string) state.Key {
+	return state.Key("nft-owned." + owner)
+}
+
+// NFTID returns the global id of token name minted by contract issuer
+func NFTID(issuer, name string) string {
+	return issuer + "." + name
+}
+
+func MintNFT(pool state.Pool, issuer, owner, name string, metadata []byte) (string, bool) {
+	if !validNFTAccount(owner) || !nftIDRegexp.MatchString(issuer) || !nftIDRegexp.MatchString(name) {
+		return "", false
+	}
+	id := NFTID(issuer, name)
+	if NFTOwner(pool, id) != "" || IsNFTBurned(pool, id) {
+		return "", false
+	}
+	pool.PutHM(NFTOwnerKey, state.Key(id), state.MakeVString(owner))
+	pool.PutHM(NFTIssuerKey, state.Key(id), state.MakeVString(issuer))
+	pool.PutHM(NFTMetaKey, state.Key(id), state.MakeVByte(metadata))
+	pool.PutHM(NFTOwnedKey(owner), state.Key(id), state.VTrue)
+	return id, true
+}
+
+func TransferNFT(pool state.Pool, spender, des, id string) bool {
+	owner := NFTOwner(pool, id)
+	if owner == "" || !validNFTAccount(des) || !nftCanMove(pool, spender, owner, id) {
+		return false
+	}
+	pool.PutHM(NFTOwnerKey, state.Key(id), state.MakeVString(des))
+	pool.PutHM(NFTApprovalKey, state.Key(id), state.VDelete)
+	pool.PutHM(NFTOwnedKey(owner), state.Key(id), state.VDelete)
+	pool.PutHM(NFTOwnedKey(des), state.Key(id), state.VTrue)
+	return true
+}
+
+func ApproveNFT(pool state.Pool, owner, spender, id string) bool {
+	if NFTOwner(pool, id) != owner || owner == "" {
+		return false
+	}
+	if spender == "" {
+		pool.PutHM(NFTApprovalKey, state.Key(id), state.VDelete)
+	} else if !validNFTAccount(spender) {
+		return false
+	} else {
+		pool.PutHM(NFTApprovalKey, state.Key(id), state.MakeVString(spender))
+	}
+	return true
+}
+
+func SetNFTOperator(pool state.Pool, owner, operator string, approved bool) bool {
+	if owner == "" || !validNFTAccount(operator) || owner == operator {
+		return false
+	}
+	if approved {
+		pool.PutHM(NFTOperatorKey(owner), state.Key(operator), state.VTrue)
+	} else {
+		pool.PutHM(NFTOperatorKey(owner), state.Key(operator), state.VDelete)
+	}
+	return true
+}
+
+func BurnNFT(pool state.Pool, spender, id string) bool {
+	owner := NFTOwner(pool, id)
+	if owner == "" || !nftCanMove(pool, spender, owner, id) {
+		return false
+	}
+	pool.PutHM(NFTOwnerKey, state.Key(id), state.VDelete)
+	pool.PutHM(NFTIssuerKey, state.Key(id), state.VDelete)
+	pool.PutHM(NFTMetaKey, state.Key(id), state.VDelete)
+	pool.PutHM(NFTApprovalKey, state.Key(id), state.VDelete)
+	pool.PutHM(NFTOwnedKey(owner), state.Key(id), state.VDelete)
+	pool.PutHM(NFTBurnedKey, state.Key(id), state.VTrue)
+	return true
+}
+
+func IsNFTBurned(pool state.Pool, id string) bool {
+	val, err := pool.GetHM(NFTBurnedKey, state.Key(id))
+	if err != nil {
+		return false
+	}
+	return val == state.VTrue
+}
+
+func NFTOwner(pool state.Pool, id string) string {
+	return hmString(pool, NFTOwnerKey, state.Key(id))
+}
+
+func NFTIssuer(pool state.Pool, id string) string {
+	return hmString(pool, NFTIssuerKey, state.Key(id))
+}
+
+func NFTApproved(pool state.Pool, id string) string {
+	return hmString(pool, NFTApprovalKey, state.Key(id))
+}
+
+func NFTMetadata(pool state.Pool, id string) ([]byte, bool) {
+	val, err := pool.GetHM(NFTMetaKey, state.Key(id))
+	if err != nil {
+		return nil, false
+	}
+	b, ok := val.(*state.VBytes)
+	if !ok {
+		return nil, false
+	}
+	return b.ToBytes(), true
+}
+
+func IsNFTOperator(pool state.Pool, owner, operator string) bool {
+	val, err := pool.GetHM(NFTOperatorKey(owner), state.Key(operator))
+	if err != nil {
+		return false
+	}
+	return val == state.VTrue
+}
+
+// NFTsOf returns ids of all tokens held by owner, sorted
+func NFTsOf(pool state.Pool, owner string) []string {
+	ids := make([]string, 0)
+	val, err := pool.Get(NFTOwnedKey(owner))
+	if err != nil {
+		return ids
+	}
+	m, ok := val.(*state.VMap)
+	if !ok {
+		return ids
+	}
+	for k, v := range m.Map() {
+		if v == state.VTrue {
+			ids = append(ids, string(k))
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func nftCanMove(pool state.Pool, spender, owner, id string) bool {
+	if spender == "" {
+		return false
+	}
+	return spender == owner ||
+		NFTApproved(pool, id) == spender ||
+		IsNFTOperator(pool, owner, spender)
+}
+
+func hmString(pool state.Pool, key, field state.Key) string {
+	val, err := pool.GetHM(key, field)
+	if err != nil {
+		return ""
+	}
+	s, ok := val.(*state.VString)
+	if !ok {
+		return ""
+	}
+	return s.ToString()
+}
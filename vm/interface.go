@@ -76,7 +76,31 @@ func PrefixToHash(prefix string) []byte {
 	return common.Base58Decode(prefix)
 }
 
-func CheckPrivilege(ctx *Context, info ContractInfo, name string) int {
+// CheckPrivilege returns 2 if name published the tx, 1 if name signed it or name is a named
+// account whose active permission is satisfied by the signers, 0 otherwise. If name has a
+// multisig policy, the policy must be satisfied as well
+func CheckPrivilege(pool state.Pool, ctx *Context, info ContractInfo, name string) int {
+	p := checkSigned(ctx, info, name)
+	perm, err := GetMultisig(pool, IOSTAccount(name))
+	if err != nil && err != ErrNoMultisig {
+		return 0
+	}
+	if err == nil {
+		if !perm.Satisfied(func(id IOSTAccount) bool { return signedIn(ctx, info, id) }) {
+			return 0
+		}
+		if p == 0 {
+			p = 1
+		}
+		return p
+	}
+	if p == 0 && IsAccountName(name) && CheckPermission(pool, ctx, info, name, Active) {
+		return 1
+	}
+	return p
+}
+
+func checkSigned(ctx *Context, info ContractInfo, name string) int {
 	for {
 		if ctx != nil {
 			if IOSTAccount(name) == ctx.Publisher {
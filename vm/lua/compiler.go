@@ -81,6 +81,13 @@ func matchPublisher(code string) (publisher string, err error) {
 	return match, nil
 }
 
+// matchSponsor returns the sponsor named by @sponsor, empty if the publisher pays gas
+func matchSponsor(code string) string {
+	sponsorRe := regexp.MustCompile("@sponsor ([a-zA-Z0-9.]+)")
+	match, _ := optionalMatchOne(sponsorRe, code)
+	return match
+}
+
 func matchPriv(code string) vm.Privilege {
 	privRe := regexp.MustCompile("@privilege ([a-z]+)")
 	var privS string
@@ -203,6 +210,7 @@ func (p *DocCommentParser) Parse() (*Contract, error) {
 	contract.info.Language = "lua"
 	contract.info.GasLimit = gasLimit
 	contract.info.Price = gasPrice
+	contract.info.Sponsor = vm.IOSTAccount(matchSponsor(content))
 	contract.apis = make(map[string]Method)
 
 	re := regexp.MustCompile("(--- .*\n)(-- .*\n)*function(.*\n)*?end--f")
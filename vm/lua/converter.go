@@ -4,10 +4,12 @@ import (
 	"fmt"
 
 	"reflect"
+	"sort"
 
 	"strconv"
 
 	"github.com/iost-official/Go-IOS-Protocol/core/state"
+	"github.com/iost-official/Go-IOS-Protocol/vm"
 	"github.com/iost-official/gopher-lua"
 )
 
@@ -131,3 +133,15 @@ func Bool2Lua(b bool) lua.LValue {
 	return rtnl
 
 }
+
+// Lua2Permission makes a permission from threshold and a table of {id = weight}
+func Lua2Permission(threshold lua.LNumber, keys *lua.LTable) vm.Permission {
+	perm := vm.Permission{Threshold: int64(threshold)}
+	keys.ForEach(func(key lua.LValue, value lua.LValue) {
+		perm.Keys = append(perm.Keys, vm.KeyWeight{ID: vm.IOSTAccount(key.String()), Weight: int64(lua.LVAsNumber(value))})
+	})
+	sort.Slice(perm.Keys, func(i, j int) bool {
+		return perm.Keys[i].ID < perm.Keys[j].ID
+	})
+	return perm
+}
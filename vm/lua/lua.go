@@ -194,7 +194,7 @@ func (l *VM) Prepare(monitor vm.Monitor) error {
 		name: "Transfer",
 		function: func(L *lua.LState) int {
 			src := L.ToString(1)
-			if vm.CheckPrivilege(l.ctx, l.contract.info, src) <= 0 {
+			if vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, src) <= 0 {
 				L.Push(lua.LFalse)
 				return 1
 			}
@@ -211,7 +211,7 @@ func (l *VM) Prepare(monitor vm.Monitor) error {
 		name: "Deposit",
 		function: func(L *lua.LState) int {
 			src := L.ToString(1)
-			if vm.CheckPrivilege(l.ctx, l.contract.info, src) <= 0 {
+			if vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, src) <= 0 {
 				L.Push(lua.LString("privilege error"))
 				return 1
 			}
@@ -236,6 +236,10 @@ func (l *VM) Prepare(monitor vm.Monitor) error {
 	var Withdraw = api{
 		name: "Withdraw",
 		function: func(L *lua.LState) int {
+			if !vm.CheckMultisig(l.cachePool, l.ctx, l.contract.info, vm.IOSTAccount(l.contract.Info().Prefix)) {
+				L.Push(lua.LFalse)
+				return 1
+			}
 			des := L.ToString(1)
 			value := L.ToNumber(2)
 			rtn := host.Withdraw(l.cachePool, l.contract.Info().Prefix, des, float64(value))
@@ -245,6 +249,333 @@ func (l *VM) Prepare(monitor vm.Monitor) error {
 	}
 	l.APIs = append(l.APIs, Withdraw)
 
+	var DepositSponsor = api{
+		name: "DepositSponsor",
+		function: func(L *lua.LState) int {
+			sponsor := L.ToString(1)
+			src := L.ToString(2)
+			if vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, src) <= 0 {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			value := L.ToNumber(3)
+			rtn := host.Deposit(l.cachePool, sponsor, src, float64(value))
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, DepositSponsor)
+
+	var WithdrawSponsor = api{
+		name: "WithdrawSponsor",
+		function: func(L *lua.LState) int {
+			sponsor := L.ToString(1)
+			if !l.ownsSponsor(sponsor) {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			des := L.ToString(2)
+			value := L.ToNumber(3)
+			rtn := host.Withdraw(l.cachePool, sponsor, des, float64(value))
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, WithdrawSponsor)
+
+	var SetSponsorLimit = api{
+		name: "SetSponsorLimit",
+		function: func(L *lua.LState) int {
+			sponsor := L.ToString(1)
+			if !l.ownsSponsor(sponsor) {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			maxFee := L.ToNumber(2)
+			rtn := host.SetSponsorLimit(l.cachePool, sponsor, float64(maxFee))
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, SetSponsorLimit)
+
+	// schedule stores a call of a method at a future block, the second argument is the height of the
+	// block or its time
+	schedule := func(L *lua.LState, byHeight bool) int {
+		owner := L.ToString(1)
+		if vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, owner) <= 0 {
+			L.Push(lua.LFalse)
+			return 1
+		}
+		call := vm.DeferredCall{
+			Owner:    vm.IOSTAccount(owner),
+			Contract: L.ToString(3),
+			Method:   L.ToString(4),
+			GasLimit: int64(L.ToNumber(5)),
+			Price:    float64(L.ToNumber(6)),
+		}
+		if byHeight {
+			call.Height = int64(L.ToNumber(2))
+		} else {
+			call.Time = int64(L.ToNumber(2))
+		}
+		if call.Method == "main" {
+			L.Push(lua.LFalse)
+			return 1
+		}
+		method, _, err := l.monitor.GetMethod(call.Contract, call.Method)
+		if err != nil {
+			L.Push(lua.LFalse)
+			return 1
+		}
+		for i := 1; i <= method.InputCount(); i++ {
+			v, err := Lua2Core(L.Get(i + 6))
+			if err != nil {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			call.Args = append(call.Args, v)
+		}
+		id, ok := host.Schedule(l.cachePool, l.ctx, &call)
+		if !ok {
+			L.Push(lua.LFalse)
+			return 1
+		}
+		L.PCount += 1000
+		L.Push(lua.LTrue)
+		L.Push(lua.LString(id))
+		return 2
+	}
+
+	var ScheduleAtHeight = api{
+		name: "ScheduleAtHeight",
+		function: func(L *lua.LState) int {
+			return schedule(L, true)
+		},
+	}
+	l.APIs = append(l.APIs, ScheduleAtHeight)
+
+	var ScheduleAtTime = api{
+		name: "ScheduleAtTime",
+		function: func(L *lua.LState) int {
+			return schedule(L, false)
+		},
+	}
+	l.APIs = append(l.APIs, ScheduleAtTime)
+
+	var CancelSchedule = api{
+		name: "CancelSchedule",
+		function: func(L *lua.LState) int {
+			id := L.ToString(1)
+			call, err := vm.GetDeferred(l.cachePool, id)
+			if err != nil || vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, string(call.Owner)) <= 0 {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			rtn := host.CancelSchedule(l.cachePool, id)
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, CancelSchedule)
+
+	var NFTMint = api{
+		name: "NFTMint",
+		function: func(L *lua.LState) int {
+			owner := L.ToString(1)
+			name := L.ToString(2)
+			var metadata string
+			if table, ok := L.Get(3).(*lua.LTable); ok {
+				jsonStr, err := host.TableToJson(table)
+				if err != nil {
+					L.Push(lua.LFalse)
+					return 1
+				}
+				metadata = jsonStr
+			} else {
+				metadata = L.ToString(3)
+			}
+			id, ok := host.MintNFT(l.cachePool, l.contract.Info().Prefix, owner, name, []byte(metadata))
+			L.PCount += 1000
+			if !ok {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			L.Push(lua.LTrue)
+			L.Push(lua.LString(id))
+			return 2
+		},
+	}
+	l.APIs = append(l.APIs, NFTMint)
+
+	var NFTTransfer = api{
+		name: "NFTTransfer",
+		function: func(L *lua.LState) int {
+			spender := L.ToString(1)
+			if vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, spender) <= 0 {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			des := L.ToString(2)
+			id := L.ToString(3)
+			rtn := host.TransferNFT(l.cachePool, spender, des, id)
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, NFTTransfer)
+
+	var NFTApprove = api{
+		name: "NFTApprove",
+		function: func(L *lua.LState) int {
+			owner := L.ToString(1)
+			if vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, owner) <= 0 {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			spender := L.ToString(2)
+			id := L.ToString(3)
+			rtn := host.ApproveNFT(l.cachePool, owner, spender, id)
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, NFTApprove)
+
+	var NFTSetOperator = api{
+		name: "NFTSetOperator",
+		function: func(L *lua.LState) int {
+			owner := L.ToString(1)
+			if vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, owner) <= 0 {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			operator := L.ToString(2)
+			approved := L.ToBool(3)
+			rtn := host.SetNFTOperator(l.cachePool, owner, operator, approved)
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, NFTSetOperator)
+
+	var NFTBurn = api{
+		name: "NFTBurn",
+		function: func(L *lua.LState) int {
+			spender := L.ToString(1)
+			if vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, spender) <= 0 {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			id := L.ToString(2)
+			rtn := host.BurnNFT(l.cachePool, spender, id)
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, NFTBurn)
+
+	var NFTOwner = api{
+		name: "NFTOwner",
+		function: func(L *lua.LState) int {
+			id := L.ToString(1)
+			owner := host.NFTOwner(l.cachePool, id)
+			L.PCount += 100
+			if owner == "" {
+				L.Push(lua.LNil)
+				return 1
+			}
+			L.Push(lua.LString(owner))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, NFTOwner)
+
+	var NFTMetadata = api{
+		name: "NFTMetadata",
+		function: func(L *lua.LState) int {
+			id := L.ToString(1)
+			metadata, ok := host.NFTMetadata(l.cachePool, id)
+			L.PCount += 100
+			if !ok {
+				L.Push(lua.LNil)
+				return 1
+			}
+			L.Push(lua.LString(metadata))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, NFTMetadata)
+
+	var NewAccount = api{
+		name: "NewAccount",
+		function: func(L *lua.LState) int {
+			name := L.ToString(1)
+			owner := L.ToString(2)
+			active := L.ToString(3)
+			if vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, owner) <= 0 {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			rtn := host.NewAccount(l.cachePool, name, vm.IOSTAccount(owner), vm.IOSTAccount(active))
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, NewAccount)
+
+	var SetAccountKeys = api{
+		name: "SetAccountKeys",
+		function: func(L *lua.LState) int {
+			name := L.ToString(1)
+			var level vm.PermissionLevel
+			switch L.ToString(2) {
+			case "owner":
+				level = vm.Owner
+			case "active":
+				level = vm.Active
+			default:
+				L.Push(lua.LFalse)
+				return 1
+			}
+			keys, ok := L.Get(4).(*lua.LTable)
+			if !ok {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			rtn := host.SetAccountKeys(l.cachePool, l.ctx, l.contract.info, name, level, Lua2Permission(L.ToNumber(3), keys))
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, SetAccountKeys)
+
+	var SetMultisig = api{
+		name: "SetMultisig",
+		function: func(L *lua.LState) int {
+			account := L.ToString(1)
+			keys, ok := L.Get(3).(*lua.LTable)
+			if !ok {
+				L.Push(lua.LFalse)
+				return 1
+			}
+			rtn := host.SetMultisig(l.cachePool, l.ctx, l.contract.info, account, Lua2Permission(L.ToNumber(2), keys))
+			L.PCount += 1000
+			L.Push(Bool2Lua(rtn))
+			return 1
+		},
+	}
+	l.APIs = append(l.APIs, SetMultisig)
+
 	var Random = api{
 		name: "Random",
 		function: func(L *lua.LState) int {
@@ -352,7 +683,7 @@ func (l *VM) Prepare(monitor vm.Monitor) error {
 				return 1
 			}
 
-			p := vm.CheckPrivilege(l.ctx, *info, string(l.contract.Info().Publisher))
+			p := vm.CheckPrivilege(l.cachePool, l.ctx, *info, string(l.contract.Info().Publisher))
 			pri := method.Privilege()
 			switch {
 			case pri == vm.Private && p > 1:
@@ -429,3 +760,12 @@ func (l *VM) Restart(contract vm.Contract) error {
 func (l *VM) Contract() vm.Contract {
 	return l.contract
 }
+
+// ownsSponsor tells whether the running contract may spend or limit the budget of sponsor, that is
+// sponsor is the contract itself or an account which authorized the tx
+func (l *VM) ownsSponsor(sponsor string) bool {
+	if sponsor == l.contract.Info().Prefix {
+		return vm.CheckMultisig(l.cachePool, l.ctx, l.contract.info, vm.IOSTAccount(sponsor))
+	}
+	return vm.CheckPrivilege(l.cachePool, l.ctx, l.contract.info, sponsor) > 0
+}
@@ -0,0 +1,78 @@
+package vm
+
+import (
+	"errors"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+)
+
+// MultisigKey is the state map of multisig policies, field is the account (or contract prefix)
+const MultisigKey state.Key = "multisig"
+
+var ErrNoMultisig = errors.New("no multisig policy")
+
+func (p *Permission) Encode() []byte {
+	pr := p.toRaw()
+	buf, err := pr.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+	return buf
+}
+
+func (p *Permission) Decode(b []byte) error {
+	pr := permissionRaw{}
+	_, err := pr.Unmarshal(b)
+	if err != nil {
+		return err
+	}
+	p.fromRaw(pr)
+	return nil
+}
+
+// GetMultisig returns the policy of id, ErrNoMultisig if id is an ordinary account
+func GetMultisig(pool state.Pool, id IOSTAccount) (*Permission, error) {
+	if pool == nil {
+		return nil, ErrNoMultisig
+	}
+	val, err := pool.GetHM(MultisigKey, state.Key(id))
+	if err != nil {
+		return nil, err
+	}
+	b, ok := val.(*state.VBytes)
+	if !ok {
+		return nil, ErrNoMultisig
+	}
+	var perm Permission
+	err = perm.Decode(b.ToBytes())
+	if err != nil {
+		return nil, err
+	}
+	return &perm, nil
+}
+
+// PutMultisig makes id a multisig account, named accounts carry their own thresholds and are refused
+func PutMultisig(pool state.Pool, id IOSTAccount, perm Permission) error {
+	if IsAccountName(string(id)) {
+		return ErrInvalidAccountName
+	}
+	if err := perm.Validate(); err != nil {
+		return err
+	}
+	return pool.PutHM(MultisigKey, state.Key(id), state.MakeVByte(perm.Encode()))
+}
+
+// CheckMultisig tells whether the signers of ctx and info satisfy the policy of id, true if id has
+// none. A policy which cannot be read is not satisfied
+func CheckMultisig(pool state.Pool, ctx *Context, info ContractInfo, id IOSTAccount) bool {
+	perm, err := GetMultisig(pool, id)
+	if err == ErrNoMultisig {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	return perm.Satisfied(func(k IOSTAccount) bool {
+		return signedIn(ctx, info, k)
+	})
+}
@@ -0,0 +1,213 @@
+package vm
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+)
+
+// NamedAccountKey is the state map holding every named account, field is the account name
+const NamedAccountKey state.Key = "named-account"
+
+type PermissionLevel int
+
+const (
+	Active PermissionLevel = iota
+	Owner
+)
+
+var (
+	ErrInvalidAccountName = errors.New("invalid account name")
+	ErrInvalidPermission  = errors.New("invalid permission")
+	ErrAccountNotFound    = errors.New("account not found")
+)
+
+var accountNameRegexp = regexp.MustCompile(`^[a-z1-5.]{1,12}$`)
+
+// KeyWeight is one key of a permission, ID is the base58 form of the public key
+type KeyWeight struct {
+	ID     IOSTAccount
+	Weight int64
+}
+
+// Permission is satisfied when the sum of weights of signed keys reaches Threshold
+type Permission struct {
+	Threshold int64
+	Keys      []KeyWeight
+}
+
+// NamedAccount maps a human readable name to two sets of weighted keys. Owner keys
+// can rotate both permissions, active keys can act on behalf of the account
+type NamedAccount struct {
+	Name   string
+	Owner  Permission
+	Active Permission
+}
+
+func NewNamedAccount(name string, owner, active IOSTAccount) *NamedAccount {
+	return &NamedAccount{
+		Name:   name,
+		Owner:  Permission{Threshold: 1, Keys: []KeyWeight{{ID: owner, Weight: 1}}},
+		Active: Permission{Threshold: 1, Keys: []KeyWeight{{ID: active, Weight: 1}}},
+	}
+}
+
+// IsAccountName tells whether name is a valid account name. Names never collide with
+// ids derived from public keys, which are much longer
+func IsAccountName(name string) bool {
+	return accountNameRegexp.MatchString(name)
+}
+
+func (p *Permission) Validate() error {
+	if p.Threshold <= 0 || len(p.Keys) == 0 {
+		return ErrInvalidPermission
+	}
+	var total int64
+	seen := make(map[IOSTAccount]bool)
+	for _, k := range p.Keys {
+		if k.Weight <= 0 || k.ID == "" || seen[k.ID] {
+			return ErrInvalidPermission
+		}
+		seen[k.ID] = true
+		total += k.Weight
+	}
+	if total < p.Threshold {
+		return ErrInvalidPermission
+	}
+	return nil
+}
+
+// Satisfied sums the weights of keys reported by signed and compares with the threshold
+func (p *Permission) Satisfied(signed func(id IOSTAccount) bool) bool {
+	var total int64
+	for _, k := range p.Keys {
+		if signed(k.ID) {
+			total += k.Weight
+		}
+	}
+	return p.Threshold > 0 && total >= p.Threshold
+}
+
+func (a *NamedAccount) Permission(level PermissionLevel) *Permission {
+	if level == Owner {
+		return &a.Owner
+	}
+	return &a.Active
+}
+
+// Satisfied checks level of a, owner keys are always enough for active level
+func (a *NamedAccount) Satisfied(level PermissionLevel, signed func(id IOSTAccount) bool) bool {
+	if a.Owner.Satisfied(signed) {
+		return true
+	}
+	return level == Active && a.Active.Satisfied(signed)
+}
+
+func (a *NamedAccount) Validate() error {
+	if !IsAccountName(a.Name) {
+		return ErrInvalidAccountName
+	}
+	if err := a.Owner.Validate(); err != nil {
+		return err
+	}
+	return a.Active.Validate()
+}
+
+func (p *Permission) toRaw() permissionRaw {
+	pr := permissionRaw{Threshold: p.Threshold, Keys: make([]keyWeightRaw, 0, len(p.Keys))}
+	for _, k := range p.Keys {
+		pr.Keys = append(pr.Keys, keyWeightRaw{ID: string(k.ID), Weight: k.Weight})
+	}
+	return pr
+}
+
+func (p *Permission) fromRaw(pr permissionRaw) {
+	p.Threshold = pr.Threshold
+	p.Keys = make([]KeyWeight, 0, len(pr.Keys))
+	for _, k := range pr.Keys {
+		p.Keys = append(p.Keys, KeyWeight{ID: IOSTAccount(k.ID), Weight: k.Weight})
+	}
+}
+
+func (a *NamedAccount) Encode() []byte {
+	nar := namedAccountRaw{Name: a.Name, Owner: a.Owner.toRaw(), Active: a.Active.toRaw()}
+	buf, err := nar.Marshal(nil)
+	if err != nil {
+		panic(err)
+	}
+	return buf
+}
+
+func (a *NamedAccount) Decode(b []byte) error {
+	nar := namedAccountRaw{}
+	_, err := nar.Unmarshal(b)
+	if err != nil {
+		return err
+	}
+	a.Name = nar.Name
+	a.Owner.fromRaw(nar.Owner)
+	a.Active.fromRaw(nar.Active)
+	return nil
+}
+
+func GetNamedAccount(pool state.Pool, name string) (*NamedAccount, error) {
+	if pool == nil || !IsAccountName(name) {
+		return nil, ErrAccountNotFound
+	}
+	val, err := pool.GetHM(NamedAccountKey, state.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	b, ok := val.(*state.VBytes)
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+	var acc NamedAccount
+	err = acc.Decode(b.ToBytes())
+	if err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+func PutNamedAccount(pool state.Pool, acc *NamedAccount) error {
+	if err := acc.Validate(); err != nil {
+		return err
+	}
+	return pool.PutHM(NamedAccountKey, state.Key(acc.Name), state.MakeVByte(acc.Encode()))
+}
+
+// signedIn reports whether id signed ctx (including its bases) or info
+func signedIn(ctx *Context, info ContractInfo, id IOSTAccount) bool {
+	for ; ctx != nil; ctx = ctx.Base {
+		if id == ctx.Publisher {
+			return true
+		}
+		for _, signer := range ctx.Signers {
+			if id == signer {
+				return true
+			}
+		}
+	}
+	if id == info.Publisher {
+		return true
+	}
+	for _, signer := range info.Signers {
+		if id == signer {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPermission tells whether keys that signed ctx or info satisfy level of named account name
+func CheckPermission(pool state.Pool, ctx *Context, info ContractInfo, name string, level PermissionLevel) bool {
+	acc, err := GetNamedAccount(pool, name)
+	if err != nil {
+		return false
+	}
+	return acc.Satisfied(level, func(id IOSTAccount) bool {
+		return signedIn(ctx, info, id)
+	})
+}
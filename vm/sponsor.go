@@ -0,0 +1,79 @@
+package vm
+
+import (
+	"errors"
+
+	"github.com/iost-official/Go-IOS-Protocol/core/state"
+)
+
+// SponsorBudgetKey is the state map of gas budgets, field is the sponsor. It is the balance space
+// contracts deposit into, so the deposit of a contract is the budget it sponsors callers with
+const SponsorBudgetKey state.Key = "iost-contract"
+
+// SponsorLimitKey maps sponsors to the max gas fee they refund for a tx they did not sign
+const SponsorLimitKey state.Key = "sponsor-limit"
+
+var (
+	ErrNoSponsorLimit      = errors.New("no sponsor limit")
+	ErrInvalidSponsorLimit = errors.New("invalid sponsor limit")
+	ErrSponsorRefused      = errors.New("sponsor refused to pay gas")
+)
+
+// SponsorBudget returns the gas budget of sponsor
+func SponsorBudget(pool state.Pool, sponsor IOSTAccount) float64 {
+	val, err := pool.GetHM(SponsorBudgetKey, state.Key(sponsor))
+	if err != nil {
+		return 0
+	}
+	v, ok := val.(*state.VFloat)
+	if !ok {
+		return 0
+	}
+	return v.ToFloat64()
+}
+
+// SetSponsorBudget overwrites the gas budget of sponsor
+func SetSponsorBudget(pool state.Pool, sponsor IOSTAccount, budget float64) {
+	pool.PutHM(SponsorBudgetKey, state.Key(sponsor), state.MakeVFloat(budget))
+}
+
+// GetSponsorLimit returns the max fee sponsor refunds for a tx it did not sign, ErrNoSponsorLimit if
+// sponsor only pays for txs it signed
+func GetSponsorLimit(pool state.Pool, sponsor IOSTAccount) (float64, error) {
+	val, err := pool.GetHM(SponsorLimitKey, state.Key(sponsor))
+	if err != nil {
+		return 0, err
+	}
+	v, ok := val.(*state.VFloat)
+	if !ok || v.ToFloat64() <= 0 {
+		return 0, ErrNoSponsorLimit
+	}
+	return v.ToFloat64(), nil
+}
+
+// PutSponsorLimit sets the max fee sponsor refunds for a tx it did not sign, 0 withdraws the offer
+func PutSponsorLimit(pool state.Pool, sponsor IOSTAccount, maxFee float64) error {
+	if maxFee < 0 {
+		return ErrInvalidSponsorLimit
+	}
+	return pool.PutHM(SponsorLimitKey, state.Key(sponsor), state.MakeVFloat(maxFee))
+}
+
+// CheckSponsor tells whether the sponsor of contract agrees to pay for it. A sponsor which signed
+// the tx pays all of its gas, other sponsors only refund the gas used inside their contract up to
+// the limit they set, the publisher pays the rest
+func CheckSponsor(pool state.Pool, contract Contract) error {
+	info := contract.Info()
+	if info.Sponsor == "" || SponsorSigned(pool, info) {
+		return nil
+	}
+	if _, err := GetSponsorLimit(pool, info.Sponsor); err != nil {
+		return ErrSponsorRefused
+	}
+	return nil
+}
+
+// SponsorSigned tells whether the sponsor of info authorized the tx
+func SponsorSigned(pool state.Pool, info ContractInfo) bool {
+	return CheckPrivilege(pool, nil, info, string(info.Sponsor)) > 0
+}
@@ -17,6 +17,7 @@ type contractInfoRaw struct {
 	Version  int8
 	GasLimit int64
 	Price    float64
+	Sponsor  string
 }
 
 func (d *contractInfoRaw) Size() (s uint64) {
@@ -36,6 +37,21 @@ func (d *contractInfoRaw) Size() (s uint64) {
 		}
 		s += l
 	}
+	{
+		l := uint64(len(d.Sponsor))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
 	s += 17
 	return
 }
@@ -114,6 +130,25 @@ func (d *contractInfoRaw) Marshal(buf []byte) ([]byte, error) {
 		buf[i+7+9] = byte(v >> 56)
 
 	}
+	{
+		l := uint64(len(d.Sponsor))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+17] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+17] = byte(t)
+			i++
+
+		}
+		copy(buf[i+17:], d.Sponsor)
+		i += l
+	}
 	return buf[:i+17], nil
 }
 
@@ -156,5 +191,1135 @@ func (d *contractInfoRaw) Unmarshal(buf []byte) (uint64, error) {
 		d.Price = *(*float64)(unsafe.Pointer(&v))
 
 	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+17] & 0x7F)
+			for buf[i+17]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+17]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Sponsor = string(buf[i+17 : i+17+l])
+		i += l
+	}
 	return i + 17, nil
 }
+
+type keyWeightRaw struct {
+	ID     string
+	Weight int64
+}
+
+func (d *keyWeightRaw) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 8
+	return
+}
+func (d *keyWeightRaw) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.ID)
+		i += l
+	}
+	{
+
+		buf[i+0+0] = byte(d.Weight >> 0)
+
+		buf[i+1+0] = byte(d.Weight >> 8)
+
+		buf[i+2+0] = byte(d.Weight >> 16)
+
+		buf[i+3+0] = byte(d.Weight >> 24)
+
+		buf[i+4+0] = byte(d.Weight >> 32)
+
+		buf[i+5+0] = byte(d.Weight >> 40)
+
+		buf[i+6+0] = byte(d.Weight >> 48)
+
+		buf[i+7+0] = byte(d.Weight >> 56)
+
+	}
+	return buf[:i+8], nil
+}
+
+func (d *keyWeightRaw) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ID = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+
+		d.Weight = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+	}
+	return i + 8, nil
+}
+
+type permissionRaw struct {
+	Threshold int64
+	Keys      []keyWeightRaw
+}
+
+func (d *permissionRaw) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Keys))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Keys {
+
+			{
+				s += d.Keys[k0].Size()
+			}
+
+		}
+
+	}
+	s += 8
+	return
+}
+func (d *permissionRaw) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+
+		buf[0+0] = byte(d.Threshold >> 0)
+
+		buf[1+0] = byte(d.Threshold >> 8)
+
+		buf[2+0] = byte(d.Threshold >> 16)
+
+		buf[3+0] = byte(d.Threshold >> 24)
+
+		buf[4+0] = byte(d.Threshold >> 32)
+
+		buf[5+0] = byte(d.Threshold >> 40)
+
+		buf[6+0] = byte(d.Threshold >> 48)
+
+		buf[7+0] = byte(d.Threshold >> 56)
+
+	}
+	{
+		l := uint64(len(d.Keys))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+8] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+8] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Keys {
+
+			{
+				nbuf, err := d.Keys[k0].Marshal(buf[i+8:])
+				if err != nil {
+					return nil, err
+				}
+				i += uint64(len(nbuf))
+			}
+
+		}
+	}
+	return buf[:i+8], nil
+}
+
+func (d *permissionRaw) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+
+		d.Threshold = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+8] & 0x7F)
+			for buf[i+8]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+8]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Keys)) >= l {
+			d.Keys = d.Keys[:l]
+		} else {
+			d.Keys = make([]keyWeightRaw, l)
+		}
+		for k0 := range d.Keys {
+
+			{
+				ni, err := d.Keys[k0].Unmarshal(buf[i+8:])
+				if err != nil {
+					return 0, err
+				}
+				i += ni
+			}
+
+		}
+	}
+	return i + 8, nil
+}
+
+type namedAccountRaw struct {
+	Name   string
+	Owner  permissionRaw
+	Active permissionRaw
+}
+
+func (d *namedAccountRaw) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Name))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		s += d.Owner.Size()
+	}
+	{
+		s += d.Active.Size()
+	}
+	return
+}
+func (d *namedAccountRaw) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Name))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Name)
+		i += l
+	}
+	{
+		nbuf, err := d.Owner.Marshal(buf[i+0:])
+		if err != nil {
+			return nil, err
+		}
+		i += uint64(len(nbuf))
+	}
+	{
+		nbuf, err := d.Active.Marshal(buf[i+0:])
+		if err != nil {
+			return nil, err
+		}
+		i += uint64(len(nbuf))
+	}
+	return buf[:i+0], nil
+}
+
+func (d *namedAccountRaw) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Name = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		ni, err := d.Owner.Unmarshal(buf[i+0:])
+		if err != nil {
+			return 0, err
+		}
+		i += ni
+	}
+	{
+		ni, err := d.Active.Unmarshal(buf[i+0:])
+		if err != nil {
+			return 0, err
+		}
+		i += ni
+	}
+	return i + 0, nil
+}
+
+type deferredCallRaw struct {
+	ID       string
+	Owner    string
+	Contract string
+	Method   string
+	Args     []string
+	Height   int64
+	Time     int64
+	GasLimit int64
+	Price    float64
+}
+
+func (d *deferredCallRaw) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Owner))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Contract))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Method))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Args))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Args {
+
+			{
+				l := uint64(len(d.Args[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	s += 32
+	return
+}
+func (d *deferredCallRaw) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.ID)
+		i += l
+	}
+	{
+		l := uint64(len(d.Owner))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Owner)
+		i += l
+	}
+	{
+		l := uint64(len(d.Contract))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Contract)
+		i += l
+	}
+	{
+		l := uint64(len(d.Method))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Method)
+		i += l
+	}
+	{
+		l := uint64(len(d.Args))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Args {
+
+			{
+				l := uint64(len(d.Args[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+0] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+0] = byte(t)
+					i++
+
+				}
+				copy(buf[i+0:], d.Args[k0])
+				i += l
+			}
+
+		}
+	}
+	{
+
+		buf[i+0+0] = byte(d.Height >> 0)
+
+		buf[i+1+0] = byte(d.Height >> 8)
+
+		buf[i+2+0] = byte(d.Height >> 16)
+
+		buf[i+3+0] = byte(d.Height >> 24)
+
+		buf[i+4+0] = byte(d.Height >> 32)
+
+		buf[i+5+0] = byte(d.Height >> 40)
+
+		buf[i+6+0] = byte(d.Height >> 48)
+
+		buf[i+7+0] = byte(d.Height >> 56)
+
+	}
+	{
+
+		buf[i+0+8] = byte(d.Time >> 0)
+
+		buf[i+1+8] = byte(d.Time >> 8)
+
+		buf[i+2+8] = byte(d.Time >> 16)
+
+		buf[i+3+8] = byte(d.Time >> 24)
+
+		buf[i+4+8] = byte(d.Time >> 32)
+
+		buf[i+5+8] = byte(d.Time >> 40)
+
+		buf[i+6+8] = byte(d.Time >> 48)
+
+		buf[i+7+8] = byte(d.Time >> 56)
+
+	}
+	{
+
+		buf[i+0+16] = byte(d.GasLimit >> 0)
+
+		buf[i+1+16] = byte(d.GasLimit >> 8)
+
+		buf[i+2+16] = byte(d.GasLimit >> 16)
+
+		buf[i+3+16] = byte(d.GasLimit >> 24)
+
+		buf[i+4+16] = byte(d.GasLimit >> 32)
+
+		buf[i+5+16] = byte(d.GasLimit >> 40)
+
+		buf[i+6+16] = byte(d.GasLimit >> 48)
+
+		buf[i+7+16] = byte(d.GasLimit >> 56)
+
+	}
+	{
+
+		v := *(*uint64)(unsafe.Pointer(&(d.Price)))
+
+		buf[i+0+24] = byte(v >> 0)
+
+		buf[i+1+24] = byte(v >> 8)
+
+		buf[i+2+24] = byte(v >> 16)
+
+		buf[i+3+24] = byte(v >> 24)
+
+		buf[i+4+24] = byte(v >> 32)
+
+		buf[i+5+24] = byte(v >> 40)
+
+		buf[i+6+24] = byte(v >> 48)
+
+		buf[i+7+24] = byte(v >> 56)
+
+	}
+	return buf[:i+32], nil
+}
+
+func (d *deferredCallRaw) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ID = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Owner = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Contract = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Method = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Args)) >= l {
+			d.Args = d.Args[:l]
+		} else {
+			d.Args = make([]string, l)
+		}
+		for k0 := range d.Args {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+0] & 0x7F)
+					for buf[i+0]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+0]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.Args[k0] = string(buf[i+0 : i+0+l])
+				i += l
+			}
+
+		}
+	}
+	{
+
+		d.Height = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+	}
+	{
+
+		d.Time = 0 | (int64(buf[i+0+8]) << 0) | (int64(buf[i+1+8]) << 8) | (int64(buf[i+2+8]) << 16) | (int64(buf[i+3+8]) << 24) | (int64(buf[i+4+8]) << 32) | (int64(buf[i+5+8]) << 40) | (int64(buf[i+6+8]) << 48) | (int64(buf[i+7+8]) << 56)
+
+	}
+	{
+
+		d.GasLimit = 0 | (int64(buf[i+0+16]) << 0) | (int64(buf[i+1+16]) << 8) | (int64(buf[i+2+16]) << 16) | (int64(buf[i+3+16]) << 24) | (int64(buf[i+4+16]) << 32) | (int64(buf[i+5+16]) << 40) | (int64(buf[i+6+16]) << 48) | (int64(buf[i+7+16]) << 56)
+
+	}
+	{
+
+		v := 0 | (uint64(buf[i+0+24]) << 0) | (uint64(buf[i+1+24]) << 8) | (uint64(buf[i+2+24]) << 16) | (uint64(buf[i+3+24]) << 24) | (uint64(buf[i+4+24]) << 32) | (uint64(buf[i+5+24]) << 40) | (uint64(buf[i+6+24]) << 48) | (uint64(buf[i+7+24]) << 56)
+		d.Price = *(*float64)(unsafe.Pointer(&v))
+
+	}
+	return i + 32, nil
+}
+
+type deferredQueueRaw struct {
+	IDs []string
+}
+
+func (d *deferredQueueRaw) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.IDs))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.IDs {
+
+			{
+				l := uint64(len(d.IDs[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	return
+}
+func (d *deferredQueueRaw) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.IDs))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.IDs {
+
+			{
+				l := uint64(len(d.IDs[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+0] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+0] = byte(t)
+					i++
+
+				}
+				copy(buf[i+0:], d.IDs[k0])
+				i += l
+			}
+
+		}
+	}
+	return buf[:i+0], nil
+}
+
+func (d *deferredQueueRaw) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.IDs)) >= l {
+			d.IDs = d.IDs[:l]
+		} else {
+			d.IDs = make([]string, l)
+		}
+		for k0 := range d.IDs {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+0] & 0x7F)
+					for buf[i+0]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+0]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.IDs[k0] = string(buf[i+0 : i+0+l])
+				i += l
+			}
+
+		}
+	}
+	return i + 0, nil
+}
+
+type deferredIndexRaw struct {
+	Slots []int64
+}
+
+func (d *deferredIndexRaw) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Slots))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		s += 8 * l
+
+	}
+	return
+}
+func (d *deferredIndexRaw) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Slots))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Slots {
+
+			{
+
+				buf[i+0+0] = byte(d.Slots[k0] >> 0)
+
+				buf[i+1+0] = byte(d.Slots[k0] >> 8)
+
+				buf[i+2+0] = byte(d.Slots[k0] >> 16)
+
+				buf[i+3+0] = byte(d.Slots[k0] >> 24)
+
+				buf[i+4+0] = byte(d.Slots[k0] >> 32)
+
+				buf[i+5+0] = byte(d.Slots[k0] >> 40)
+
+				buf[i+6+0] = byte(d.Slots[k0] >> 48)
+
+				buf[i+7+0] = byte(d.Slots[k0] >> 56)
+
+			}
+
+			i += 8
+
+		}
+	}
+	return buf[:i+0], nil
+}
+
+func (d *deferredIndexRaw) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Slots)) >= l {
+			d.Slots = d.Slots[:l]
+		} else {
+			d.Slots = make([]int64, l)
+		}
+		for k0 := range d.Slots {
+
+			{
+
+				d.Slots[k0] = 0 | (int64(buf[i+0+0]) << 0) | (int64(buf[i+1+0]) << 8) | (int64(buf[i+2+0]) << 16) | (int64(buf[i+3+0]) << 24) | (int64(buf[i+4+0]) << 32) | (int64(buf[i+5+0]) << 40) | (int64(buf[i+6+0]) << 48) | (int64(buf[i+7+0]) << 56)
+
+			}
+
+			i += 8
+
+		}
+	}
+	return i + 0, nil
+}